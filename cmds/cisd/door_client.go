@@ -0,0 +1,204 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/tierklinik-dobersberg/cis/gen/go/tkd/door/v1/doorv1connect"
+)
+
+// doorClientOptions holds the flags used by door commands to
+// connect to a running cisd instance.
+type doorClientOptions struct {
+	server string
+	token  string
+	user   string
+	output string
+}
+
+var doorClientOpts doorClientOptions
+
+// doorClient talks to the door API of a running cisd instance.
+type doorClient struct {
+	baseURL string
+	door    string
+	token   string
+	user    string
+	client  *http.Client
+}
+
+func newDoorClient() *doorClient {
+	return &doorClient{
+		baseURL: strings.TrimSuffix(doorClientOpts.server, "/") + "/api/door/v1/",
+		door:    doorName,
+		token:   doorClientOpts.token,
+		user:    doorClientOpts.user,
+		client: &http.Client{
+			Timeout: time.Minute,
+		},
+	}
+}
+
+// newDoorServiceClient returns a client for the door service of the
+// running cisd instance. Requests are authenticated like the ones of
+// doorClient.
+func newDoorServiceClient() doorv1connect.DoorServiceClient {
+	authenticate := connect.UnaryInterceptorFunc(func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			doorClientOpts.authenticate(req.Header())
+
+			return next(ctx, req)
+		}
+	})
+
+	return doorv1connect.NewDoorServiceClient(
+		&http.Client{
+			Timeout: time.Minute,
+		},
+		strings.TrimSuffix(doorClientOpts.server, "/")+"/api",
+		connect.WithInterceptors(authenticate),
+	)
+}
+
+// authenticate sets the authentication headers of a request.
+func (opts doorClientOptions) authenticate(header http.Header) {
+	if opts.token != "" {
+		header.Set("Authorization", "Bearer "+opts.token)
+	}
+
+	if opts.user != "" {
+		header.Set("X-Remote-User-ID", opts.user)
+	}
+}
+
+// envOrDefault returns the value of the environment variable key
+// or def if it is not set.
+func envOrDefault(key, def string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+
+	return def
+}
+
+// do performs a request against endpoint of the selected door and
+// returns the raw response body. If body is non-nil, it is sent as
+// JSON.
+func (cli *doorClient) do(ctx context.Context, method, endpoint string, query url.Values, body any) ([]byte, error) {
+	path := cli.baseURL
+	if cli.door != "" {
+		path += url.PathEscape(cli.door) + "/"
+	}
+	path += endpoint
+
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		blob, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+
+		reqBody = bytes.NewReader(blob)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, path, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if cli.token != "" {
+		req.Header.Set("Authorization", "Bearer "+cli.token)
+	}
+
+	if cli.user != "" {
+		req.Header.Set("X-Remote-User-ID", cli.user)
+	}
+
+	res, err := cli.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to perform request: %w", err)
+	}
+	defer res.Body.Close()
+
+	blob, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		if err := json.Unmarshal(blob, &apiErr); err == nil && apiErr.Message != "" {
+			return nil, fmt.Errorf("%s: %s", res.Status, apiErr.Message)
+		}
+
+		return nil, errors.New(res.Status)
+	}
+
+	return blob, nil
+}
+
+// call performs a request like do and prints the response. If JSON
+// output is requested the response is printed as is. Otherwise it is
+// decoded into result and human is called to print it.
+func (cli *doorClient) call(ctx context.Context, method, endpoint string, query url.Values, body any, result any, human func()) error {
+	blob, err := cli.do(ctx, method, endpoint, query, body)
+	if err != nil {
+		return err
+	}
+
+	switch doorClientOpts.output {
+	case "json":
+		buf := new(bytes.Buffer)
+		if err := json.Indent(buf, blob, "", "  "); err != nil {
+			return fmt.Errorf("invalid response: %w", err)
+		}
+		buf.WriteString("\n")
+
+		_, err := buf.WriteTo(os.Stdout)
+
+		return err
+
+	case "human", "":
+		if len(blob) > 0 && result != nil {
+			if err := json.Unmarshal(blob, result); err != nil {
+				return fmt.Errorf("invalid response: %w", err)
+			}
+		}
+
+		human()
+
+		return nil
+
+	default:
+		return fmt.Errorf("unsupported output format %q", doorClientOpts.output)
+	}
+}
+
+// formatTime formats t for human readable output.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+
+	return t.Local().Format("2006-01-02 15:04:05")
+}
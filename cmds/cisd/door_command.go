@@ -2,73 +2,374 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/tierklinik-dobersberg/cis/internal/api/doorapi"
+	"github.com/tierklinik-dobersberg/cis/internal/door"
 	"github.com/tierklinik-dobersberg/logger"
 )
 
+var doorName string
+
 func getDoorCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "door",
 		Short: "Control the entry door",
+		Long: "Control the entry door using the door API of a running cisd instance.\n" +
+			"Requests are either authenticated using a bearer token (--token) if cisd\n" +
+			"is running behind the authentication proxy or by passing the user ID\n" +
+			"directly (--user) if connecting to cisd without a proxy.",
 	}
 
+	flags := cmd.PersistentFlags()
+	flags.StringVar(&doorName, "door", "", "The name of the door to control. Defaults to the entry door")
+	flags.StringVar(&doorClientOpts.server, "server", envOrDefault("CISD_URL", "http://localhost:3000"), "The URL of the running cisd instance (env: CISD_URL)")
+	flags.StringVar(&doorClientOpts.token, "token", os.Getenv("CISD_TOKEN"), "A bearer token used to authenticate (env: CISD_TOKEN)")
+	flags.StringVar(&doorClientOpts.user, "user", os.Getenv("CISD_USER"), "The ID of the user to act as when connecting without the authentication proxy (env: CISD_USER)")
+
 	cmd.AddCommand(
 		getDoorLockCommand(),
 		getDoorUnlockCommand(),
 		getDoorOpenCommand(),
+		getDoorStateCommand(),
+		getDoorOverwriteCommand(),
+		getDoorResetCommand(),
+		getDoorHistoryCommand(),
+		getDoorSimulateCommand(),
 	)
 
 	return cmd
 }
 
+// addOutputFlag adds the --output flag to a door client command.
+func addOutputFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&doorClientOpts.output, "output", "o", "human", "Output format. One of human or json")
+}
+
+// overwriteResponse is returned by the overwrite endpoint.
+type overwriteResponse struct {
+	Door      string          `json:"door"`
+	State     door.State      `json:"state"`
+	Until     time.Time       `json:"until"`
+	Overwrite *door.Overwrite `json:"overwrite"`
+}
+
+// runOverwrite creates a door overwrite and prints the result. If mode
+// is set, the end of the overwrite is resolved from the opening hours
+// and duration is ignored.
+func runOverwrite(ctx context.Context, state string, from time.Time, duration time.Duration, mode string, priority int) error {
+	body := map[string]any{
+		"state":    state,
+		"duration": duration.String(),
+		"priority": priority,
+	}
+
+	if mode != "" {
+		body["mode"] = mode
+	}
+
+	if !from.IsZero() {
+		body["from"] = from.Format(time.RFC3339)
+	}
+
+	var res overwriteResponse
+
+	return newDoorClient().call(ctx, http.MethodPost, "overwrite", nil, body, &res, func() {
+		if res.Overwrite != nil {
+			fmt.Printf("Door %s: %s from %s until %s\n", res.Door, res.Overwrite.State, formatTime(res.Overwrite.From), formatTime(res.Overwrite.Until))
+		}
+
+		fmt.Printf("Current state: %s until %s\n", res.State, formatTime(res.Until))
+	})
+}
+
 func getDoorLockCommand() *cobra.Command {
-	return &cobra.Command{
+	var duration time.Duration
+
+	cmd := &cobra.Command{
 		Use:   "lock",
 		Short: "Lock the door",
+		Long:  "Lock the door by creating a manual overwrite. The door stays locked for the duration specified using --for.",
 		Run: func(_ *cobra.Command, _ []string) {
-			ctx, cancel := context.WithCancel(context.Background())
-			defer cancel()
-
-			app, _, ctx := getApp(ctx)
+			ctx := context.Background()
 
-			if err := app.Door.Lock(ctx); err != nil {
+			if err := runOverwrite(ctx, "lock", time.Time{}, duration, "", 0); err != nil {
 				logger.Fatalf(ctx, err.Error())
 			}
 		},
 	}
+
+	cmd.Flags().DurationVar(&duration, "for", time.Hour, "How long the door should stay locked")
+	addOutputFlag(cmd)
+
+	return cmd
 }
 
 func getDoorUnlockCommand() *cobra.Command {
-	return &cobra.Command{
+	var duration time.Duration
+
+	cmd := &cobra.Command{
 		Use:   "unlock",
 		Short: "Unlock the door",
+		Long:  "Unlock the door by creating a manual overwrite. The door stays unlocked for the duration specified using --for.",
 		Run: func(_ *cobra.Command, _ []string) {
-			ctx, cancel := context.WithCancel(context.Background())
-			defer cancel()
+			ctx := context.Background()
 
-			app, _, ctx := getApp(ctx)
-
-			if err := app.Door.Unlock(ctx); err != nil {
+			if err := runOverwrite(ctx, "unlock", time.Time{}, duration, "", 0); err != nil {
 				logger.Fatalf(ctx, err.Error())
 			}
 		},
 	}
+
+	cmd.Flags().DurationVar(&duration, "for", time.Hour, "How long the door should stay unlocked")
+	addOutputFlag(cmd)
+
+	return cmd
 }
 
 func getDoorOpenCommand() *cobra.Command {
-	return &cobra.Command{
+	var duration time.Duration
+
+	cmd := &cobra.Command{
 		Use:   "open",
 		Short: "Open the door",
 		Run: func(_ *cobra.Command, _ []string) {
-			ctx, cancel := context.WithCancel(context.Background())
-			defer cancel()
+			ctx := context.Background()
+
+			body := map[string]any{}
+			if duration > 0 {
+				body["duration"] = duration.String()
+			}
 
-			app, _, ctx := getApp(ctx)
+			var res struct {
+				Door     string    `json:"door"`
+				Duration string    `json:"duration"`
+				Until    time.Time `json:"until"`
+			}
 
-			if err := app.Door.Open(ctx); err != nil {
+			if err := newDoorClient().call(ctx, http.MethodPost, "open", nil, body, &res, func() {
+				fmt.Printf("Door %s opened for %s (until %s)\n", res.Door, res.Duration, formatTime(res.Until))
+			}); err != nil {
 				logger.Fatalf(ctx, err.Error())
 			}
 		},
 	}
+
+	cmd.Flags().DurationVar(&duration, "for", 0, "How long the door should be kept open. Defaults to the configured OpenDuration")
+	addOutputFlag(cmd)
+
+	return cmd
+}
+
+func getDoorStateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "state",
+		Short: "Print the current state of the door",
+		Run: func(_ *cobra.Command, _ []string) {
+			ctx := context.Background()
+
+			var res struct {
+				Door                  string         `json:"door"`
+				DesiredState          door.State     `json:"desiredState"`
+				ActualState           door.State     `json:"actualState"`
+				ActualStateReportedAt time.Time      `json:"actualStateReportedAt"`
+				Until                 time.Time      `json:"until"`
+				ResetInProgress       bool           `json:"resetInProgress"`
+				Reason                door.Reason    `json:"reason"`
+				Lockdown              *door.Lockdown `json:"lockdown"`
+			}
+
+			if err := newDoorClient().call(ctx, http.MethodGet, "state", nil, nil, &res, func() {
+				tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+				defer tw.Flush()
+
+				fmt.Fprintf(tw, "Door:\t%s\n", res.Door)
+				fmt.Fprintf(tw, "Desired state:\t%s\n", res.DesiredState)
+				fmt.Fprintf(tw, "Reason:\t%s\n", res.Reason)
+				fmt.Fprintf(tw, "Until:\t%s\n", formatTime(res.Until))
+
+				if res.ActualState != "" && res.ActualState != door.Unknown {
+					fmt.Fprintf(tw, "Actual state:\t%s (reported %s)\n", res.ActualState, formatTime(res.ActualStateReportedAt))
+				}
+
+				if res.ResetInProgress {
+					fmt.Fprintf(tw, "Reset:\tin progress\n")
+				}
+
+				if res.Lockdown != nil {
+					fmt.Fprintf(tw, "Lockdown:\tsince %s by %q: %s\n", formatTime(res.Lockdown.CreatedAt), res.Lockdown.SessionUser, res.Lockdown.Reason)
+				}
+			}); err != nil {
+				logger.Fatalf(ctx, err.Error())
+			}
+		},
+	}
+
+	addOutputFlag(cmd)
+
+	return cmd
+}
+
+func getDoorOverwriteCommand() *cobra.Command {
+	var (
+		state    string
+		duration time.Duration
+		from     string
+		mode     string
+		priority int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "overwrite",
+		Short: "Overwrite the door state for a given time",
+		Run: func(_ *cobra.Command, _ []string) {
+			ctx := context.Background()
+
+			var start time.Time
+			if from != "" {
+				var err error
+				start, err = parseSimulationTime(from, time.Local)
+				if err != nil {
+					logger.Fatalf(ctx, "--from: %s", err)
+				}
+			}
+
+			if err := runOverwrite(ctx, state, start, duration, mode, priority); err != nil {
+				logger.Fatalf(ctx, err.Error())
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&state, "state", "", "The door state to enforce. Either lock or unlock")
+	cmd.Flags().DurationVar(&duration, "for", time.Hour, "How long the overwrite should last")
+	cmd.Flags().StringVar(&from, "from", "", "When the overwrite should start. Defaults to now")
+	cmd.Flags().StringVar(&mode, "mode", "", "Resolve the end of the overwrite from the opening hours instead of using --for. One of until-next-change, until-next-opening or until-end-of-day")
+	cmd.Flags().IntVar(&priority, "priority", 0, "The priority of the overwrite")
+	addOutputFlag(cmd)
+
+	_ = cmd.MarkFlagRequired("state")
+
+	return cmd
+}
+
+func getDoorResetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reset",
+		Short: "Reset the door and re-apply the current state",
+		Run: func(_ *cobra.Command, _ []string) {
+			ctx := context.Background()
+
+			var res struct {
+				Door  string                 `json:"door"`
+				State door.State             `json:"state"`
+				Until time.Time              `json:"until"`
+				Steps []door.ResetStepResult `json:"steps"`
+			}
+
+			if err := newDoorClient().call(ctx, http.MethodPost, "reset", nil, nil, &res, func() {
+				tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+				fmt.Fprintln(tw, "STEP\tACTION\tTIME\tRESULT")
+				for _, step := range res.Steps {
+					result := "ok"
+					if step.Error != "" {
+						result = step.Error
+					}
+
+					fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", step.Step, step.Action, formatTime(step.Time), result)
+				}
+
+				tw.Flush()
+
+				fmt.Printf("Current state: %s until %s\n", res.State, formatTime(res.Until))
+			}); err != nil {
+				logger.Fatalf(ctx, err.Error())
+			}
+		},
+	}
+
+	addOutputFlag(cmd)
+
+	return cmd
+}
+
+func getDoorHistoryCommand() *cobra.Command {
+	var (
+		from   string
+		to     string
+		actor  string
+		limit  int
+		offset int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Print the door audit log",
+		Run: func(_ *cobra.Command, _ []string) {
+			ctx := context.Background()
+
+			query := url.Values{}
+			query.Set("limit", strconv.Itoa(limit))
+			query.Set("offset", strconv.Itoa(offset))
+
+			if actor != "" {
+				query.Set("actor", actor)
+			}
+
+			for name, value := range map[string]string{"from": from, "to": to} {
+				if value == "" {
+					continue
+				}
+
+				t, err := parseSimulationTime(value, time.Local)
+				if err != nil {
+					logger.Fatalf(ctx, "--%s: %s", name, err)
+				}
+
+				query.Set(name, t.Format(time.RFC3339))
+			}
+
+			var res doorapi.HistoryResponse
+
+			if err := newDoorClient().call(ctx, http.MethodGet, "history", query, nil, &res, func() {
+				tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+				defer tw.Flush()
+
+				fmt.Fprintln(tw, "TIME\tDOOR\tACTION\tACTOR\tSTATE\tRESULT")
+				for _, record := range res.Records {
+					actor := record.Actor
+					if actor == "" {
+						actor = "-"
+					}
+
+					result := "ok"
+					if record.Error != "" {
+						result = record.Error
+					}
+
+					fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", formatTime(record.Time), record.Door, record.Action, actor, record.DesiredState, result)
+				}
+
+				fmt.Fprintf(tw, "\nShowing %d of %d records\n", len(res.Records), res.Total)
+			}); err != nil {
+				logger.Fatalf(ctx, err.Error())
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "Only show records after this time")
+	cmd.Flags().StringVar(&to, "to", "", "Only show records before this time")
+	cmd.Flags().StringVar(&actor, "actor", "", "Only show records of this user")
+	cmd.Flags().IntVar(&limit, "limit", 50, "The maximum number of records to show")
+	cmd.Flags().IntVar(&offset, "offset", 0, "The number of records to skip")
+	addOutputFlag(cmd)
+
+	return cmd
 }
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/spf13/cobra"
+	doorv1 "github.com/tierklinik-dobersberg/cis/gen/go/tkd/door/v1"
+	"github.com/tierklinik-dobersberg/cis/internal/door"
+	"github.com/tierklinik-dobersberg/cis/pkg/ical"
+	"github.com/tierklinik-dobersberg/logger"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var protoStates = map[doorv1.DoorState]door.State{
+	doorv1.DoorState_DOOR_STATE_LOCKED:   door.Locked,
+	doorv1.DoorState_DOOR_STATE_UNLOCKED: door.Unlocked,
+	doorv1.DoorState_DOOR_STATE_OPEN:     door.Open,
+}
+
+func getDoorSimulateCommand() *cobra.Command {
+	var (
+		from   string
+		to     string
+		output string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "simulate",
+		Short: "Print all desired door state transitions in a time window",
+		Long: "Print all desired door state transitions in a time window together with the reason\n" +
+			"(regular, date-specific, holiday, closure, overwrite or lockdown) as simulated by the\n" +
+			"running cisd instance. Times may be specified as RFC3339, as \"2006-01-02 15:04\" or\n" +
+			"as \"2006-01-02\" in the local time zone. The time window must not exceed 31 days.",
+		Run: func(_ *cobra.Command, _ []string) {
+			ctx := context.Background()
+
+			start := time.Now()
+			if from != "" {
+				var err error
+				start, err = parseSimulationTime(from, time.Local)
+				if err != nil {
+					logger.Fatalf(ctx, "--from: %s", err)
+				}
+			}
+
+			end := start.AddDate(0, 0, 7)
+			if to != "" {
+				var err error
+				end, err = parseSimulationTime(to, time.Local)
+				if err != nil {
+					logger.Fatalf(ctx, "--to: %s", err)
+				}
+			}
+
+			if !end.After(start) {
+				logger.Fatalf(ctx, "--to must be after --from")
+			}
+
+			status, transitions, err := simulateDoor(ctx, start, end)
+			if err != nil {
+				logger.Fatalf(ctx, err.Error())
+			}
+
+			switch output {
+			case "table":
+				err = printTransitionTable(transitions, end)
+			case "json":
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				err = enc.Encode(transitions)
+			case "ical", "ics":
+				err = printTransitionCalendar(status, transitions, end)
+			default:
+				err = fmt.Errorf("unsupported output format %q", output)
+			}
+
+			if err != nil {
+				logger.Fatalf(ctx, err.Error())
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "Start of the simulation. Defaults to now")
+	cmd.Flags().StringVar(&to, "to", "", "End of the simulation. Defaults to 7 days after --from")
+	cmd.Flags().StringVarP(&output, "output", "o", "table", "Output format. One of table, json or ical")
+
+	return cmd
+}
+
+// simulateDoor returns the status of the selected door and all
+// transitions of the desired door state between start and end as
+// simulated by the running cisd instance. The simulation does not
+// require access to the door itself.
+func simulateDoor(ctx context.Context, start, end time.Time) (*doorv1.DoorStatus, []door.Transition, error) {
+	cli := newDoorServiceClient()
+
+	state, err := cli.GetState(ctx, connect.NewRequest(&doorv1.GetStateRequest{
+		Door: doorName,
+	}))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get door state: %w", err)
+	}
+
+	res, err := cli.ListUpcomingTransitions(ctx, connect.NewRequest(&doorv1.ListUpcomingTransitionsRequest{
+		Door: state.Msg.Status.GetDoor(),
+		From: timestamppb.New(start),
+		To:   timestamppb.New(end),
+	}))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to simulate door: %w", err)
+	}
+
+	transitions := make([]door.Transition, len(res.Msg.Transitions))
+	for idx, t := range res.Msg.Transitions {
+		transitions[idx] = door.Transition{
+			Time:   t.GetTime().AsTime().In(start.Location()),
+			State:  protoStates[t.GetState()],
+			Until:  t.GetUntil().AsTime().In(start.Location()),
+			Reason: door.Reason(t.GetReason()),
+		}
+	}
+
+	return state.Msg.Status, transitions, nil
+}
+
+// parseSimulationTime parses s as RFC3339 or as a local date with
+// an optional time in loc.
+func parseSimulationTime(s string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.In(loc), nil
+	}
+
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02T15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time %q", s)
+}
+
+func printTransitionTable(transitions []door.Transition, end time.Time) error {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "FROM\tUNTIL\tSTATE\tREASON")
+	for _, t := range transitions {
+		until := t.Until
+		if until.After(end) {
+			until = end
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n",
+			t.Time.Format("Mon 2006-01-02 15:04"),
+			until.Format("Mon 2006-01-02 15:04"),
+			strings.ToUpper(string(t.State)),
+			t.Reason,
+		)
+	}
+
+	return tw.Flush()
+}
+
+func printTransitionCalendar(status *doorv1.DoorStatus, transitions []door.Transition, end time.Time) error {
+	cal := ical.Calendar{
+		ProdID: "-//tierklinik-dobersberg//cisd//EN",
+		Name:   fmt.Sprintf("Door schedule: %s", status.GetDisplayName()),
+	}
+
+	for _, t := range transitions {
+		until := t.Until
+		if until.After(end) {
+			until = end
+		}
+
+		cal.Events = append(cal.Events, ical.Event{
+			UID:         fmt.Sprintf("door-%s-%d@cisd", status.GetDoor(), t.Time.Unix()),
+			Summary:     fmt.Sprintf("%s %s", status.GetDisplayName(), t.State),
+			Description: fmt.Sprintf("Reason: %s", t.Reason),
+			Start:       t.Time,
+			End:         until,
+			Categories:  []string{string(t.State), string(t.Reason)},
+			Transparent: true,
+		})
+	}
+
+	_, err := cal.WriteTo(os.Stdout)
+
+	return err
+}
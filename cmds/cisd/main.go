@@ -22,6 +22,7 @@ import (
 	idmv1 "github.com/tierklinik-dobersberg/apis/gen/go/tkd/idm/v1"
 	"github.com/tierklinik-dobersberg/cis/internal/api/configapi"
 	"github.com/tierklinik-dobersberg/cis/internal/api/doorapi"
+	"github.com/tierklinik-dobersberg/cis/internal/api/doorservice"
 	"github.com/tierklinik-dobersberg/cis/internal/api/openinghoursapi"
 	"github.com/tierklinik-dobersberg/cis/internal/app"
 	"github.com/tierklinik-dobersberg/cis/internal/cfgspec"
@@ -37,6 +38,7 @@ import (
 	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
 	"go.opentelemetry.io/otel"
 	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+	"golang.org/x/net/http2"
 
 	//
 	// underscore imports that register themself somewhere.
@@ -44,6 +46,12 @@ import (
 
 	// All available/build-in identity providers.
 	"github.com/tierklinik-dobersberg/cis/internal/idm"
+
+	// All built-in door drivers.
+	_ "github.com/tierklinik-dobersberg/cis/internal/door/drivers/httpdoor"
+	_ "github.com/tierklinik-dobersberg/cis/internal/door/drivers/mqttdoor"
+	_ "github.com/tierklinik-dobersberg/cis/internal/door/drivers/shellyrpc"
+	_ "github.com/tierklinik-dobersberg/cis/internal/door/drivers/shellyscript"
 )
 
 //go:embed ui
@@ -125,9 +133,24 @@ func getApp(baseCtx context.Context) (*app.App, *tracesdk.TracerProvider, contex
 	}
 
 	//
-	// prepare entry door controller
+	// prepare the door controllers
 	//
-	doorController, err := door.NewDoorController(ctx, openingHoursCtrl, runtime.GlobalSchema)
+	doorOverwrites, err := door.NewOverwriteDatabase(ctx, mongoClient.Database(databaseName))
+	if err != nil {
+		logger.Fatalf(ctx, "door-overwrites: %s", err.Error())
+	}
+
+	doorLockdowns, err := door.NewLockdownDatabase(ctx, mongoClient.Database(databaseName))
+	if err != nil {
+		logger.Fatalf(ctx, "door-lockdowns: %s", err.Error())
+	}
+
+	doorAudit, err := door.NewAuditLog(ctx, mongoClient.Database(databaseName))
+	if err != nil {
+		logger.Fatalf(ctx, "door-audit: %s", err.Error())
+	}
+
+	doorManager, err := door.NewManager(ctx, openingHoursCtrl, runtime.GlobalSchema, doorOverwrites, doorLockdowns, doorAudit)
 	if err != nil {
 		logger.Fatalf(ctx, "door-controler: %s", err.Error())
 	}
@@ -138,7 +161,8 @@ func getApp(baseCtx context.Context) (*app.App, *tracesdk.TracerProvider, contex
 	//
 	appCtx := app.NewApp(
 		cfg,
-		doorController,
+		doorManager,
+		openingHoursCtrl,
 		os.Getenv("ROSTERD_SERVER"),
 		idm.New(os.Getenv("IDM_URL"), http.DefaultClient),
 	)
@@ -221,6 +245,15 @@ func setupAPI(app *app.App, grp *echo.Echo) {
 		configapi.Setup(app, apis.Group("config/", session.Require()))
 		// openinghoursapi provides access to the configured openinghours
 		openinghoursapi.Setup(app, apis.Group("openinghours/", session.Require()))
+		// the opening hours calendar feed is subscribed to by calendar
+		// applications that cannot authenticate.
+		openinghoursapi.SetupPublic(app, apis.Group("openinghours/"))
+
+		// doorservice provides the door API as a Connect/gRPC service
+		// at /api/tkd.door.v1.DoorService/. It rejects all calls without
+		// a session user itself so clients get proper Connect errors.
+		path, handler := doorservice.NewHandler(app.Doors)
+		apis.Any(strings.TrimPrefix(path, "/")+"*", echo.WrapHandler(http.StripPrefix("/api", handler)))
 
 		// grp.StaticFS("", webapp)
 		// grp.FileFS("/*", "index.html", webapp)
@@ -281,7 +314,7 @@ func runMain() {
 	//
 	logger.Infof(ctx, "starting door scheduler ...")
 
-	if err := app.Door.Start(); err != nil {
+	if err := app.Doors.Start(); err != nil {
 		logger.Fatalf(ctx, "failed to start door scheduler: %s", err)
 	}
 
@@ -304,11 +337,12 @@ func runMain() {
 	}
 	setupAPI(app, srv)
 
-	if err := srv.Start(app.Config.Config.Listen); err != nil {
+	// use h2c so gRPC clients can connect without TLS.
+	if err := srv.StartH2CServer(app.Config.Config.Listen, &http2.Server{}); err != nil {
 		logger.Fatalf(ctx, "failed to start listening: %s", err)
 	}
 
-	if err := app.Door.Stop(); err != nil {
+	if err := app.Doors.Stop(); err != nil {
 		logger.Errorf(ctx, "failed to stop door scheduler: %s", err)
 	}
 
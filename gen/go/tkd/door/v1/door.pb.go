@@ -0,0 +1,1635 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.1
+// 	protoc        (unknown)
+// source: tkd/door/v1/door.proto
+
+package doorv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// DoorState describes the state of a door.
+type DoorState int32
+
+const (
+	DoorState_DOOR_STATE_UNSPECIFIED DoorState = 0
+	DoorState_DOOR_STATE_LOCKED      DoorState = 1
+	DoorState_DOOR_STATE_UNLOCKED    DoorState = 2
+	DoorState_DOOR_STATE_OPEN        DoorState = 3
+)
+
+// Enum value maps for DoorState.
+var (
+	DoorState_name = map[int32]string{
+		0: "DOOR_STATE_UNSPECIFIED",
+		1: "DOOR_STATE_LOCKED",
+		2: "DOOR_STATE_UNLOCKED",
+		3: "DOOR_STATE_OPEN",
+	}
+	DoorState_value = map[string]int32{
+		"DOOR_STATE_UNSPECIFIED": 0,
+		"DOOR_STATE_LOCKED":      1,
+		"DOOR_STATE_UNLOCKED":    2,
+		"DOOR_STATE_OPEN":        3,
+	}
+)
+
+func (x DoorState) Enum() *DoorState {
+	p := new(DoorState)
+	*p = x
+	return p
+}
+
+func (x DoorState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DoorState) Descriptor() protoreflect.EnumDescriptor {
+	return file_tkd_door_v1_door_proto_enumTypes[0].Descriptor()
+}
+
+func (DoorState) Type() protoreflect.EnumType {
+	return &file_tkd_door_v1_door_proto_enumTypes[0]
+}
+
+func (x DoorState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DoorState.Descriptor instead.
+func (DoorState) EnumDescriptor() ([]byte, []int) {
+	return file_tkd_door_v1_door_proto_rawDescGZIP(), []int{0}
+}
+
+// OverwriteMode resolves the end of an overwrite from the opening hours
+// of the door.
+type OverwriteMode int32
+
+const (
+	OverwriteMode_OVERWRITE_MODE_UNSPECIFIED OverwriteMode = 0
+	// UNTIL_NEXT_CHANGE lasts until the schedule would change the door
+	// state anyway.
+	OverwriteMode_OVERWRITE_MODE_UNTIL_NEXT_CHANGE OverwriteMode = 1
+	// UNTIL_NEXT_OPENING lasts until the next opening hour starts.
+	OverwriteMode_OVERWRITE_MODE_UNTIL_NEXT_OPENING OverwriteMode = 2
+	// UNTIL_END_OF_DAY lasts until midnight.
+	OverwriteMode_OVERWRITE_MODE_UNTIL_END_OF_DAY OverwriteMode = 3
+)
+
+// Enum value maps for OverwriteMode.
+var (
+	OverwriteMode_name = map[int32]string{
+		0: "OVERWRITE_MODE_UNSPECIFIED",
+		1: "OVERWRITE_MODE_UNTIL_NEXT_CHANGE",
+		2: "OVERWRITE_MODE_UNTIL_NEXT_OPENING",
+		3: "OVERWRITE_MODE_UNTIL_END_OF_DAY",
+	}
+	OverwriteMode_value = map[string]int32{
+		"OVERWRITE_MODE_UNSPECIFIED":        0,
+		"OVERWRITE_MODE_UNTIL_NEXT_CHANGE":  1,
+		"OVERWRITE_MODE_UNTIL_NEXT_OPENING": 2,
+		"OVERWRITE_MODE_UNTIL_END_OF_DAY":   3,
+	}
+)
+
+func (x OverwriteMode) Enum() *OverwriteMode {
+	p := new(OverwriteMode)
+	*p = x
+	return p
+}
+
+func (x OverwriteMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OverwriteMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_tkd_door_v1_door_proto_enumTypes[1].Descriptor()
+}
+
+func (OverwriteMode) Type() protoreflect.EnumType {
+	return &file_tkd_door_v1_door_proto_enumTypes[1]
+}
+
+func (x OverwriteMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OverwriteMode.Descriptor instead.
+func (OverwriteMode) EnumDescriptor() ([]byte, []int) {
+	return file_tkd_door_v1_door_proto_rawDescGZIP(), []int{1}
+}
+
+// Lockdown forces the door to be locked until it is released.
+type Lockdown struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reason        string                 `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
+	SessionUser   string                 `protobuf:"bytes,2,opt,name=session_user,json=sessionUser,proto3" json:"session_user,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Lockdown) Reset() {
+	*x = Lockdown{}
+	mi := &file_tkd_door_v1_door_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Lockdown) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Lockdown) ProtoMessage() {}
+
+func (x *Lockdown) ProtoReflect() protoreflect.Message {
+	mi := &file_tkd_door_v1_door_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Lockdown.ProtoReflect.Descriptor instead.
+func (*Lockdown) Descriptor() ([]byte, []int) {
+	return file_tkd_door_v1_door_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Lockdown) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Lockdown) GetSessionUser() string {
+	if x != nil {
+		return x.SessionUser
+	}
+	return ""
+}
+
+func (x *Lockdown) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// Closure describes a day at which the clinic is closed.
+type Closure struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Reason describes why the clinic is closed.
+	Reason string `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
+	// KeepDoorLocked is set if the door stays locked during the
+	// closure. Otherwise the door still follows the opening hours
+	// that would apply without the closure.
+	KeepDoorLocked bool `protobuf:"varint,2,opt,name=keep_door_locked,json=keepDoorLocked,proto3" json:"keep_door_locked,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Closure) Reset() {
+	*x = Closure{}
+	mi := &file_tkd_door_v1_door_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Closure) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Closure) ProtoMessage() {}
+
+func (x *Closure) ProtoReflect() protoreflect.Message {
+	mi := &file_tkd_door_v1_door_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Closure.ProtoReflect.Descriptor instead.
+func (*Closure) Descriptor() ([]byte, []int) {
+	return file_tkd_door_v1_door_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Closure) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Closure) GetKeepDoorLocked() bool {
+	if x != nil {
+		return x.KeepDoorLocked
+	}
+	return false
+}
+
+// DoorStatus describes the current state of a door.
+type DoorStatus struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Door is the name of the door.
+	Door string `protobuf:"bytes,1,opt,name=door,proto3" json:"door,omitempty"`
+	// DisplayName is the human readable name of the door.
+	DisplayName string `protobuf:"bytes,2,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	// State is the desired state of the door.
+	State DoorState `protobuf:"varint,3,opt,name=state,proto3,enum=tkd.door.v1.DoorState" json:"state,omitempty"`
+	// Until is the time the desired state is expected to change. It is
+	// unset if no change is scheduled.
+	Until *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=until,proto3" json:"until,omitempty"`
+	// Reason describes why the door is in state. One of regular,
+	// date-specific, holiday, closure, overwrite or lockdown.
+	Reason string `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	// ResetInProgress is set while the door is being reset.
+	ResetInProgress bool `protobuf:"varint,6,opt,name=reset_in_progress,json=resetInProgress,proto3" json:"reset_in_progress,omitempty"`
+	// ActualState is the state reported by the door interfacer, if
+	// supported.
+	ActualState DoorState `protobuf:"varint,7,opt,name=actual_state,json=actualState,proto3,enum=tkd.door.v1.DoorState" json:"actual_state,omitempty"`
+	// Lockdown is set if the door is in lockdown.
+	Lockdown *Lockdown `protobuf:"bytes,8,opt,name=lockdown,proto3" json:"lockdown,omitempty"`
+	// Closure is set if the clinic is closed today. It only affects
+	// the state of the door if keep_door_locked is set.
+	Closure       *Closure `protobuf:"bytes,9,opt,name=closure,proto3" json:"closure,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DoorStatus) Reset() {
+	*x = DoorStatus{}
+	mi := &file_tkd_door_v1_door_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DoorStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DoorStatus) ProtoMessage() {}
+
+func (x *DoorStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_tkd_door_v1_door_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DoorStatus.ProtoReflect.Descriptor instead.
+func (*DoorStatus) Descriptor() ([]byte, []int) {
+	return file_tkd_door_v1_door_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *DoorStatus) GetDoor() string {
+	if x != nil {
+		return x.Door
+	}
+	return ""
+}
+
+func (x *DoorStatus) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *DoorStatus) GetState() DoorState {
+	if x != nil {
+		return x.State
+	}
+	return DoorState_DOOR_STATE_UNSPECIFIED
+}
+
+func (x *DoorStatus) GetUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Until
+	}
+	return nil
+}
+
+func (x *DoorStatus) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *DoorStatus) GetResetInProgress() bool {
+	if x != nil {
+		return x.ResetInProgress
+	}
+	return false
+}
+
+func (x *DoorStatus) GetActualState() DoorState {
+	if x != nil {
+		return x.ActualState
+	}
+	return DoorState_DOOR_STATE_UNSPECIFIED
+}
+
+func (x *DoorStatus) GetLockdown() *Lockdown {
+	if x != nil {
+		return x.Lockdown
+	}
+	return nil
+}
+
+func (x *DoorStatus) GetClosure() *Closure {
+	if x != nil {
+		return x.Closure
+	}
+	return nil
+}
+
+// Transition describes a change of the desired door state.
+type Transition struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Time          *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	State         DoorState              `protobuf:"varint,2,opt,name=state,proto3,enum=tkd.door.v1.DoorState" json:"state,omitempty"`
+	Until         *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=until,proto3" json:"until,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Transition) Reset() {
+	*x = Transition{}
+	mi := &file_tkd_door_v1_door_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Transition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Transition) ProtoMessage() {}
+
+func (x *Transition) ProtoReflect() protoreflect.Message {
+	mi := &file_tkd_door_v1_door_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Transition.ProtoReflect.Descriptor instead.
+func (*Transition) Descriptor() ([]byte, []int) {
+	return file_tkd_door_v1_door_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Transition) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *Transition) GetState() DoorState {
+	if x != nil {
+		return x.State
+	}
+	return DoorState_DOOR_STATE_UNSPECIFIED
+}
+
+func (x *Transition) GetUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Until
+	}
+	return nil
+}
+
+func (x *Transition) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// Overwrite overwrites the desired door state for a time range.
+type Overwrite struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Door          string                 `protobuf:"bytes,2,opt,name=door,proto3" json:"door,omitempty"`
+	State         DoorState              `protobuf:"varint,3,opt,name=state,proto3,enum=tkd.door.v1.DoorState" json:"state,omitempty"`
+	From          *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=from,proto3" json:"from,omitempty"`
+	Until         *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=until,proto3" json:"until,omitempty"`
+	Priority      int32                  `protobuf:"varint,6,opt,name=priority,proto3" json:"priority,omitempty"`
+	SessionUser   string                 `protobuf:"bytes,7,opt,name=session_user,json=sessionUser,proto3" json:"session_user,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Overwrite) Reset() {
+	*x = Overwrite{}
+	mi := &file_tkd_door_v1_door_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Overwrite) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Overwrite) ProtoMessage() {}
+
+func (x *Overwrite) ProtoReflect() protoreflect.Message {
+	mi := &file_tkd_door_v1_door_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Overwrite.ProtoReflect.Descriptor instead.
+func (*Overwrite) Descriptor() ([]byte, []int) {
+	return file_tkd_door_v1_door_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Overwrite) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Overwrite) GetDoor() string {
+	if x != nil {
+		return x.Door
+	}
+	return ""
+}
+
+func (x *Overwrite) GetState() DoorState {
+	if x != nil {
+		return x.State
+	}
+	return DoorState_DOOR_STATE_UNSPECIFIED
+}
+
+func (x *Overwrite) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *Overwrite) GetUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Until
+	}
+	return nil
+}
+
+func (x *Overwrite) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *Overwrite) GetSessionUser() string {
+	if x != nil {
+		return x.SessionUser
+	}
+	return ""
+}
+
+func (x *Overwrite) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// ResetStep holds the outcome of a single step of the reset sequence.
+type ResetStep struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Step          int32                  `protobuf:"varint,1,opt,name=step,proto3" json:"step,omitempty"`
+	Action        DoorState              `protobuf:"varint,2,opt,name=action,proto3,enum=tkd.door.v1.DoorState" json:"action,omitempty"`
+	Time          *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=time,proto3" json:"time,omitempty"`
+	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResetStep) Reset() {
+	*x = ResetStep{}
+	mi := &file_tkd_door_v1_door_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResetStep) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResetStep) ProtoMessage() {}
+
+func (x *ResetStep) ProtoReflect() protoreflect.Message {
+	mi := &file_tkd_door_v1_door_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResetStep.ProtoReflect.Descriptor instead.
+func (*ResetStep) Descriptor() ([]byte, []int) {
+	return file_tkd_door_v1_door_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ResetStep) GetStep() int32 {
+	if x != nil {
+		return x.Step
+	}
+	return 0
+}
+
+func (x *ResetStep) GetAction() DoorState {
+	if x != nil {
+		return x.Action
+	}
+	return DoorState_DOOR_STATE_UNSPECIFIED
+}
+
+func (x *ResetStep) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *ResetStep) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type GetStateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Door          string                 `protobuf:"bytes,1,opt,name=door,proto3" json:"door,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStateRequest) Reset() {
+	*x = GetStateRequest{}
+	mi := &file_tkd_door_v1_door_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStateRequest) ProtoMessage() {}
+
+func (x *GetStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tkd_door_v1_door_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStateRequest.ProtoReflect.Descriptor instead.
+func (*GetStateRequest) Descriptor() ([]byte, []int) {
+	return file_tkd_door_v1_door_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetStateRequest) GetDoor() string {
+	if x != nil {
+		return x.Door
+	}
+	return ""
+}
+
+type GetStateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        *DoorStatus            `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStateResponse) Reset() {
+	*x = GetStateResponse{}
+	mi := &file_tkd_door_v1_door_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStateResponse) ProtoMessage() {}
+
+func (x *GetStateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tkd_door_v1_door_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStateResponse.ProtoReflect.Descriptor instead.
+func (*GetStateResponse) Descriptor() ([]byte, []int) {
+	return file_tkd_door_v1_door_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetStateResponse) GetStatus() *DoorStatus {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+type ListUpcomingTransitionsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Door  string                 `protobuf:"bytes,1,opt,name=door,proto3" json:"door,omitempty"`
+	// From defaults to now.
+	From *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	// To defaults to seven days after from.
+	To            *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUpcomingTransitionsRequest) Reset() {
+	*x = ListUpcomingTransitionsRequest{}
+	mi := &file_tkd_door_v1_door_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUpcomingTransitionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUpcomingTransitionsRequest) ProtoMessage() {}
+
+func (x *ListUpcomingTransitionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tkd_door_v1_door_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUpcomingTransitionsRequest.ProtoReflect.Descriptor instead.
+func (*ListUpcomingTransitionsRequest) Descriptor() ([]byte, []int) {
+	return file_tkd_door_v1_door_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListUpcomingTransitionsRequest) GetDoor() string {
+	if x != nil {
+		return x.Door
+	}
+	return ""
+}
+
+func (x *ListUpcomingTransitionsRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ListUpcomingTransitionsRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+type ListUpcomingTransitionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Transitions   []*Transition          `protobuf:"bytes,1,rep,name=transitions,proto3" json:"transitions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUpcomingTransitionsResponse) Reset() {
+	*x = ListUpcomingTransitionsResponse{}
+	mi := &file_tkd_door_v1_door_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUpcomingTransitionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUpcomingTransitionsResponse) ProtoMessage() {}
+
+func (x *ListUpcomingTransitionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tkd_door_v1_door_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUpcomingTransitionsResponse.ProtoReflect.Descriptor instead.
+func (*ListUpcomingTransitionsResponse) Descriptor() ([]byte, []int) {
+	return file_tkd_door_v1_door_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListUpcomingTransitionsResponse) GetTransitions() []*Transition {
+	if x != nil {
+		return x.Transitions
+	}
+	return nil
+}
+
+type OverwriteRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Door  string                 `protobuf:"bytes,1,opt,name=door,proto3" json:"door,omitempty"`
+	// State must either be DOOR_STATE_LOCKED or DOOR_STATE_UNLOCKED.
+	State DoorState `protobuf:"varint,2,opt,name=state,proto3,enum=tkd.door.v1.DoorState" json:"state,omitempty"`
+	// From defaults to now.
+	From *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	// Types that are valid to be assigned to End:
+	//
+	//	*OverwriteRequest_Duration
+	//	*OverwriteRequest_Until
+	//	*OverwriteRequest_Mode
+	End           isOverwriteRequest_End `protobuf_oneof:"end"`
+	Priority      int32                  `protobuf:"varint,7,opt,name=priority,proto3" json:"priority,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OverwriteRequest) Reset() {
+	*x = OverwriteRequest{}
+	mi := &file_tkd_door_v1_door_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OverwriteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OverwriteRequest) ProtoMessage() {}
+
+func (x *OverwriteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tkd_door_v1_door_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OverwriteRequest.ProtoReflect.Descriptor instead.
+func (*OverwriteRequest) Descriptor() ([]byte, []int) {
+	return file_tkd_door_v1_door_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *OverwriteRequest) GetDoor() string {
+	if x != nil {
+		return x.Door
+	}
+	return ""
+}
+
+func (x *OverwriteRequest) GetState() DoorState {
+	if x != nil {
+		return x.State
+	}
+	return DoorState_DOOR_STATE_UNSPECIFIED
+}
+
+func (x *OverwriteRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *OverwriteRequest) GetEnd() isOverwriteRequest_End {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+func (x *OverwriteRequest) GetDuration() *durationpb.Duration {
+	if x != nil {
+		if x, ok := x.End.(*OverwriteRequest_Duration); ok {
+			return x.Duration
+		}
+	}
+	return nil
+}
+
+func (x *OverwriteRequest) GetUntil() *timestamppb.Timestamp {
+	if x != nil {
+		if x, ok := x.End.(*OverwriteRequest_Until); ok {
+			return x.Until
+		}
+	}
+	return nil
+}
+
+func (x *OverwriteRequest) GetMode() OverwriteMode {
+	if x != nil {
+		if x, ok := x.End.(*OverwriteRequest_Mode); ok {
+			return x.Mode
+		}
+	}
+	return OverwriteMode_OVERWRITE_MODE_UNSPECIFIED
+}
+
+func (x *OverwriteRequest) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+type isOverwriteRequest_End interface {
+	isOverwriteRequest_End()
+}
+
+type OverwriteRequest_Duration struct {
+	Duration *durationpb.Duration `protobuf:"bytes,4,opt,name=duration,proto3,oneof"`
+}
+
+type OverwriteRequest_Until struct {
+	Until *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=until,proto3,oneof"`
+}
+
+type OverwriteRequest_Mode struct {
+	Mode OverwriteMode `protobuf:"varint,6,opt,name=mode,proto3,enum=tkd.door.v1.OverwriteMode,oneof"`
+}
+
+func (*OverwriteRequest_Duration) isOverwriteRequest_End() {}
+
+func (*OverwriteRequest_Until) isOverwriteRequest_End() {}
+
+func (*OverwriteRequest_Mode) isOverwriteRequest_End() {}
+
+type OverwriteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Overwrite     *Overwrite             `protobuf:"bytes,1,opt,name=overwrite,proto3" json:"overwrite,omitempty"`
+	Status        *DoorStatus            `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OverwriteResponse) Reset() {
+	*x = OverwriteResponse{}
+	mi := &file_tkd_door_v1_door_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OverwriteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OverwriteResponse) ProtoMessage() {}
+
+func (x *OverwriteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tkd_door_v1_door_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OverwriteResponse.ProtoReflect.Descriptor instead.
+func (*OverwriteResponse) Descriptor() ([]byte, []int) {
+	return file_tkd_door_v1_door_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *OverwriteResponse) GetOverwrite() *Overwrite {
+	if x != nil {
+		return x.Overwrite
+	}
+	return nil
+}
+
+func (x *OverwriteResponse) GetStatus() *DoorStatus {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+type OpenRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Door  string                 `protobuf:"bytes,1,opt,name=door,proto3" json:"door,omitempty"`
+	// Duration defaults to the configured open duration of the door.
+	Duration      *durationpb.Duration `protobuf:"bytes,2,opt,name=duration,proto3" json:"duration,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OpenRequest) Reset() {
+	*x = OpenRequest{}
+	mi := &file_tkd_door_v1_door_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OpenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OpenRequest) ProtoMessage() {}
+
+func (x *OpenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tkd_door_v1_door_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OpenRequest.ProtoReflect.Descriptor instead.
+func (*OpenRequest) Descriptor() ([]byte, []int) {
+	return file_tkd_door_v1_door_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *OpenRequest) GetDoor() string {
+	if x != nil {
+		return x.Door
+	}
+	return ""
+}
+
+func (x *OpenRequest) GetDuration() *durationpb.Duration {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+type OpenResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Duration      *durationpb.Duration   `protobuf:"bytes,1,opt,name=duration,proto3" json:"duration,omitempty"`
+	Until         *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=until,proto3" json:"until,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OpenResponse) Reset() {
+	*x = OpenResponse{}
+	mi := &file_tkd_door_v1_door_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OpenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OpenResponse) ProtoMessage() {}
+
+func (x *OpenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tkd_door_v1_door_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OpenResponse.ProtoReflect.Descriptor instead.
+func (*OpenResponse) Descriptor() ([]byte, []int) {
+	return file_tkd_door_v1_door_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *OpenResponse) GetDuration() *durationpb.Duration {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+func (x *OpenResponse) GetUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Until
+	}
+	return nil
+}
+
+type ResetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Door          string                 `protobuf:"bytes,1,opt,name=door,proto3" json:"door,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResetRequest) Reset() {
+	*x = ResetRequest{}
+	mi := &file_tkd_door_v1_door_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResetRequest) ProtoMessage() {}
+
+func (x *ResetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tkd_door_v1_door_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResetRequest.ProtoReflect.Descriptor instead.
+func (*ResetRequest) Descriptor() ([]byte, []int) {
+	return file_tkd_door_v1_door_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ResetRequest) GetDoor() string {
+	if x != nil {
+		return x.Door
+	}
+	return ""
+}
+
+type ResetResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Steps         []*ResetStep           `protobuf:"bytes,1,rep,name=steps,proto3" json:"steps,omitempty"`
+	Status        *DoorStatus            `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResetResponse) Reset() {
+	*x = ResetResponse{}
+	mi := &file_tkd_door_v1_door_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResetResponse) ProtoMessage() {}
+
+func (x *ResetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tkd_door_v1_door_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResetResponse.ProtoReflect.Descriptor instead.
+func (*ResetResponse) Descriptor() ([]byte, []int) {
+	return file_tkd_door_v1_door_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ResetResponse) GetSteps() []*ResetStep {
+	if x != nil {
+		return x.Steps
+	}
+	return nil
+}
+
+func (x *ResetResponse) GetStatus() *DoorStatus {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+type WatchStateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Door          string                 `protobuf:"bytes,1,opt,name=door,proto3" json:"door,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchStateRequest) Reset() {
+	*x = WatchStateRequest{}
+	mi := &file_tkd_door_v1_door_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchStateRequest) ProtoMessage() {}
+
+func (x *WatchStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tkd_door_v1_door_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchStateRequest.ProtoReflect.Descriptor instead.
+func (*WatchStateRequest) Descriptor() ([]byte, []int) {
+	return file_tkd_door_v1_door_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *WatchStateRequest) GetDoor() string {
+	if x != nil {
+		return x.Door
+	}
+	return ""
+}
+
+type WatchStateResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Event is the type of the door event that caused the update. It
+	// is empty for the initial status.
+	Event         string      `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	Status        *DoorStatus `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchStateResponse) Reset() {
+	*x = WatchStateResponse{}
+	mi := &file_tkd_door_v1_door_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchStateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchStateResponse) ProtoMessage() {}
+
+func (x *WatchStateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tkd_door_v1_door_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchStateResponse.ProtoReflect.Descriptor instead.
+func (*WatchStateResponse) Descriptor() ([]byte, []int) {
+	return file_tkd_door_v1_door_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *WatchStateResponse) GetEvent() string {
+	if x != nil {
+		return x.Event
+	}
+	return ""
+}
+
+func (x *WatchStateResponse) GetStatus() *DoorStatus {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+var File_tkd_door_v1_door_proto protoreflect.FileDescriptor
+
+var file_tkd_door_v1_door_proto_rawDesc = []byte{
+	0x0a, 0x16, 0x74, 0x6b, 0x64, 0x2f, 0x64, 0x6f, 0x6f, 0x72, 0x2f, 0x76, 0x31, 0x2f, 0x64, 0x6f,
+	0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0b, 0x74, 0x6b, 0x64, 0x2e, 0x64, 0x6f,
+	0x6f, 0x72, 0x2e, 0x76, 0x31, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x80, 0x01, 0x0a, 0x08, 0x4c, 0x6f, 0x63, 0x6b, 0x64,
+	0x6f, 0x77, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x21, 0x0a, 0x0c, 0x73,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0b, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x55, 0x73, 0x65, 0x72, 0x12, 0x39,
+	0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09,
+	0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0x4b, 0x0a, 0x07, 0x43, 0x6c, 0x6f,
+	0x73, 0x75, 0x72, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x28, 0x0a, 0x10,
+	0x6b, 0x65, 0x65, 0x70, 0x5f, 0x64, 0x6f, 0x6f, 0x72, 0x5f, 0x6c, 0x6f, 0x63, 0x6b, 0x65, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x6b, 0x65, 0x65, 0x70, 0x44, 0x6f, 0x6f, 0x72,
+	0x4c, 0x6f, 0x63, 0x6b, 0x65, 0x64, 0x22, 0x85, 0x03, 0x0a, 0x0a, 0x44, 0x6f, 0x6f, 0x72, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x6f, 0x6f, 0x72, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x64, 0x6f, 0x6f, 0x72, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x69, 0x73,
+	0x70, 0x6c, 0x61, 0x79, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x64, 0x69, 0x73, 0x70, 0x6c, 0x61, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x2c, 0x0a, 0x05,
+	0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x74, 0x6b,
+	0x64, 0x2e, 0x64, 0x6f, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x6f, 0x6f, 0x72, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x30, 0x0a, 0x05, 0x75, 0x6e,
+	0x74, 0x69, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x05, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x12, 0x16, 0x0a, 0x06,
+	0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65,
+	0x61, 0x73, 0x6f, 0x6e, 0x12, 0x2a, 0x0a, 0x11, 0x72, 0x65, 0x73, 0x65, 0x74, 0x5f, 0x69, 0x6e,
+	0x5f, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0f, 0x72, 0x65, 0x73, 0x65, 0x74, 0x49, 0x6e, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73,
+	0x12, 0x39, 0x0a, 0x0c, 0x61, 0x63, 0x74, 0x75, 0x61, 0x6c, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x74, 0x6b, 0x64, 0x2e, 0x64, 0x6f, 0x6f,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x6f, 0x6f, 0x72, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x0b,
+	0x61, 0x63, 0x74, 0x75, 0x61, 0x6c, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x31, 0x0a, 0x08, 0x6c,
+	0x6f, 0x63, 0x6b, 0x64, 0x6f, 0x77, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e,
+	0x74, 0x6b, 0x64, 0x2e, 0x64, 0x6f, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x63, 0x6b,
+	0x64, 0x6f, 0x77, 0x6e, 0x52, 0x08, 0x6c, 0x6f, 0x63, 0x6b, 0x64, 0x6f, 0x77, 0x6e, 0x12, 0x2e,
+	0x0a, 0x07, 0x63, 0x6c, 0x6f, 0x73, 0x75, 0x72, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x14, 0x2e, 0x74, 0x6b, 0x64, 0x2e, 0x64, 0x6f, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6c,
+	0x6f, 0x73, 0x75, 0x72, 0x65, 0x52, 0x07, 0x63, 0x6c, 0x6f, 0x73, 0x75, 0x72, 0x65, 0x22, 0xb4,
+	0x01, 0x0a, 0x0a, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2e, 0x0a,
+	0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x2c, 0x0a,
+	0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x74,
+	0x6b, 0x64, 0x2e, 0x64, 0x6f, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x6f, 0x6f, 0x72, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x30, 0x0a, 0x05, 0x75,
+	0x6e, 0x74, 0x69, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x05, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x12, 0x16, 0x0a,
+	0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72,
+	0x65, 0x61, 0x73, 0x6f, 0x6e, 0x22, 0xb9, 0x02, 0x0a, 0x09, 0x4f, 0x76, 0x65, 0x72, 0x77, 0x72,
+	0x69, 0x74, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x6f, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x64, 0x6f, 0x6f, 0x72, 0x12, 0x2c, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x74, 0x6b, 0x64, 0x2e, 0x64, 0x6f, 0x6f,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x6f, 0x6f, 0x72, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05,
+	0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x2e, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x30, 0x0a, 0x05, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x05, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x69, 0x6f, 0x72,
+	0x69, 0x74, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x72, 0x69, 0x6f, 0x72,
+	0x69, 0x74, 0x79, 0x12, 0x21, 0x0a, 0x0c, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x75,
+	0x73, 0x65, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x73, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x55, 0x73, 0x65, 0x72, 0x12, 0x39, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x64, 0x5f, 0x61, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41,
+	0x74, 0x22, 0x95, 0x01, 0x0a, 0x09, 0x52, 0x65, 0x73, 0x65, 0x74, 0x53, 0x74, 0x65, 0x70, 0x12,
+	0x12, 0x0a, 0x04, 0x73, 0x74, 0x65, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x73,
+	0x74, 0x65, 0x70, 0x12, 0x2e, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x74, 0x6b, 0x64, 0x2e, 0x64, 0x6f, 0x6f, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x44, 0x6f, 0x6f, 0x72, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x06, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x2e, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x74,
+	0x69, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x25, 0x0a, 0x0f, 0x47, 0x65, 0x74,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04,
+	0x64, 0x6f, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x64, 0x6f, 0x6f, 0x72,
+	0x22, 0x43, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2f, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x74, 0x6b, 0x64, 0x2e, 0x64, 0x6f, 0x6f, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x44, 0x6f, 0x6f, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x90, 0x01, 0x0a, 0x1e, 0x4c, 0x69, 0x73, 0x74, 0x55, 0x70,
+	0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x6f, 0x6f, 0x72,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x64, 0x6f, 0x6f, 0x72, 0x12, 0x2e, 0x0a, 0x04,
+	0x66, 0x72, 0x6f, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x2a, 0x0a, 0x02,
+	0x74, 0x6f, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x02, 0x74, 0x6f, 0x22, 0x5c, 0x0a, 0x1f, 0x4c, 0x69, 0x73, 0x74,
+	0x55, 0x70, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x39, 0x0a, 0x0b, 0x74,
+	0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x17, 0x2e, 0x74, 0x6b, 0x64, 0x2e, 0x64, 0x6f, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x54,
+	0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x74, 0x72, 0x61, 0x6e, 0x73,
+	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0xc6, 0x02, 0x0a, 0x10, 0x4f, 0x76, 0x65, 0x72, 0x77,
+	0x72, 0x69, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x64,
+	0x6f, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x64, 0x6f, 0x6f, 0x72, 0x12,
+	0x2c, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16,
+	0x2e, 0x74, 0x6b, 0x64, 0x2e, 0x64, 0x6f, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x6f, 0x6f,
+	0x72, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x2e, 0x0a,
+	0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x37, 0x0a,
+	0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x08, 0x64, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x32, 0x0a, 0x05, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x48, 0x00, 0x52, 0x05, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x12, 0x30, 0x0a, 0x04, 0x6d, 0x6f,
+	0x64, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x74, 0x6b, 0x64, 0x2e, 0x64,
+	0x6f, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x76, 0x65, 0x72, 0x77, 0x72, 0x69, 0x74, 0x65,
+	0x4d, 0x6f, 0x64, 0x65, 0x48, 0x00, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x12, 0x1a, 0x0a, 0x08,
+	0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08,
+	0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x42, 0x05, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x22,
+	0x7a, 0x0a, 0x11, 0x4f, 0x76, 0x65, 0x72, 0x77, 0x72, 0x69, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x34, 0x0a, 0x09, 0x6f, 0x76, 0x65, 0x72, 0x77, 0x72, 0x69, 0x74,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x74, 0x6b, 0x64, 0x2e, 0x64, 0x6f,
+	0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x76, 0x65, 0x72, 0x77, 0x72, 0x69, 0x74, 0x65, 0x52,
+	0x09, 0x6f, 0x76, 0x65, 0x72, 0x77, 0x72, 0x69, 0x74, 0x65, 0x12, 0x2f, 0x0a, 0x06, 0x73, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x74, 0x6b, 0x64,
+	0x2e, 0x64, 0x6f, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x6f, 0x6f, 0x72, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x58, 0x0a, 0x0b, 0x4f,
+	0x70, 0x65, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x6f,
+	0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x64, 0x6f, 0x6f, 0x72, 0x12, 0x35,
+	0x0a, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x64, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x77, 0x0a, 0x0c, 0x4f, 0x70, 0x65, 0x6e, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x35, 0x0a, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x30, 0x0a, 0x05,
+	0x75, 0x6e, 0x74, 0x69, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x05, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x22, 0x22,
+	0x0a, 0x0c, 0x52, 0x65, 0x73, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12,
+	0x0a, 0x04, 0x64, 0x6f, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x64, 0x6f,
+	0x6f, 0x72, 0x22, 0x6e, 0x0a, 0x0d, 0x52, 0x65, 0x73, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x05, 0x73, 0x74, 0x65, 0x70, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x16, 0x2e, 0x74, 0x6b, 0x64, 0x2e, 0x64, 0x6f, 0x6f, 0x72, 0x2e, 0x76, 0x31,
+	0x2e, 0x52, 0x65, 0x73, 0x65, 0x74, 0x53, 0x74, 0x65, 0x70, 0x52, 0x05, 0x73, 0x74, 0x65, 0x70,
+	0x73, 0x12, 0x2f, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x17, 0x2e, 0x74, 0x6b, 0x64, 0x2e, 0x64, 0x6f, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x44, 0x6f, 0x6f, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x22, 0x27, 0x0a, 0x11, 0x57, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x6f, 0x6f, 0x72, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x64, 0x6f, 0x6f, 0x72, 0x22, 0x5b, 0x0a, 0x12, 0x57,
+	0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x2f, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x74, 0x6b, 0x64, 0x2e, 0x64, 0x6f,
+	0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x6f, 0x6f, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x2a, 0x6c, 0x0a, 0x09, 0x44, 0x6f, 0x6f, 0x72,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x1a, 0x0a, 0x16, 0x44, 0x4f, 0x4f, 0x52, 0x5f, 0x53, 0x54,
+	0x41, 0x54, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10,
+	0x00, 0x12, 0x15, 0x0a, 0x11, 0x44, 0x4f, 0x4f, 0x52, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f,
+	0x4c, 0x4f, 0x43, 0x4b, 0x45, 0x44, 0x10, 0x01, 0x12, 0x17, 0x0a, 0x13, 0x44, 0x4f, 0x4f, 0x52,
+	0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x55, 0x4e, 0x4c, 0x4f, 0x43, 0x4b, 0x45, 0x44, 0x10,
+	0x02, 0x12, 0x13, 0x0a, 0x0f, 0x44, 0x4f, 0x4f, 0x52, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f,
+	0x4f, 0x50, 0x45, 0x4e, 0x10, 0x03, 0x2a, 0xa1, 0x01, 0x0a, 0x0d, 0x4f, 0x76, 0x65, 0x72, 0x77,
+	0x72, 0x69, 0x74, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x1e, 0x0a, 0x1a, 0x4f, 0x56, 0x45, 0x52,
+	0x57, 0x52, 0x49, 0x54, 0x45, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45,
+	0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x24, 0x0a, 0x20, 0x4f, 0x56, 0x45, 0x52,
+	0x57, 0x52, 0x49, 0x54, 0x45, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x55, 0x4e, 0x54, 0x49, 0x4c,
+	0x5f, 0x4e, 0x45, 0x58, 0x54, 0x5f, 0x43, 0x48, 0x41, 0x4e, 0x47, 0x45, 0x10, 0x01, 0x12, 0x25,
+	0x0a, 0x21, 0x4f, 0x56, 0x45, 0x52, 0x57, 0x52, 0x49, 0x54, 0x45, 0x5f, 0x4d, 0x4f, 0x44, 0x45,
+	0x5f, 0x55, 0x4e, 0x54, 0x49, 0x4c, 0x5f, 0x4e, 0x45, 0x58, 0x54, 0x5f, 0x4f, 0x50, 0x45, 0x4e,
+	0x49, 0x4e, 0x47, 0x10, 0x02, 0x12, 0x23, 0x0a, 0x1f, 0x4f, 0x56, 0x45, 0x52, 0x57, 0x52, 0x49,
+	0x54, 0x45, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x55, 0x4e, 0x54, 0x49, 0x4c, 0x5f, 0x45, 0x4e,
+	0x44, 0x5f, 0x4f, 0x46, 0x5f, 0x44, 0x41, 0x59, 0x10, 0x03, 0x32, 0xf2, 0x03, 0x0a, 0x0b, 0x44,
+	0x6f, 0x6f, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x49, 0x0a, 0x08, 0x47, 0x65,
+	0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x1c, 0x2e, 0x74, 0x6b, 0x64, 0x2e, 0x64, 0x6f, 0x6f,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x74, 0x6b, 0x64, 0x2e, 0x64, 0x6f, 0x6f, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x76, 0x0a, 0x17, 0x4c, 0x69, 0x73, 0x74, 0x55, 0x70, 0x63,
+	0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x12, 0x2b, 0x2e, 0x74, 0x6b, 0x64, 0x2e, 0x64, 0x6f, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4c,
+	0x69, 0x73, 0x74, 0x55, 0x70, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x54, 0x72, 0x61, 0x6e, 0x73,
+	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2c, 0x2e,
+	0x74, 0x6b, 0x64, 0x2e, 0x64, 0x6f, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74,
+	0x55, 0x70, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4c, 0x0a,
+	0x09, 0x4f, 0x76, 0x65, 0x72, 0x77, 0x72, 0x69, 0x74, 0x65, 0x12, 0x1d, 0x2e, 0x74, 0x6b, 0x64,
+	0x2e, 0x64, 0x6f, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x76, 0x65, 0x72, 0x77, 0x72, 0x69,
+	0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x74, 0x6b, 0x64, 0x2e,
+	0x64, 0x6f, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x76, 0x65, 0x72, 0x77, 0x72, 0x69, 0x74,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x3d, 0x0a, 0x04, 0x4f,
+	0x70, 0x65, 0x6e, 0x12, 0x18, 0x2e, 0x74, 0x6b, 0x64, 0x2e, 0x64, 0x6f, 0x6f, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x4f, 0x70, 0x65, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e,
+	0x74, 0x6b, 0x64, 0x2e, 0x64, 0x6f, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x70, 0x65, 0x6e,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x40, 0x0a, 0x05, 0x52, 0x65,
+	0x73, 0x65, 0x74, 0x12, 0x19, 0x2e, 0x74, 0x6b, 0x64, 0x2e, 0x64, 0x6f, 0x6f, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x52, 0x65, 0x73, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a,
+	0x2e, 0x74, 0x6b, 0x64, 0x2e, 0x64, 0x6f, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x73,
+	0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x51, 0x0a, 0x0a,
+	0x57, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x1e, 0x2e, 0x74, 0x6b, 0x64,
+	0x2e, 0x64, 0x6f, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x74, 0x6b, 0x64,
+	0x2e, 0x64, 0x6f, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x42,
+	0x40, 0x5a, 0x3e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x74, 0x69,
+	0x65, 0x72, 0x6b, 0x6c, 0x69, 0x6e, 0x69, 0x6b, 0x2d, 0x64, 0x6f, 0x62, 0x65, 0x72, 0x73, 0x62,
+	0x65, 0x72, 0x67, 0x2f, 0x63, 0x69, 0x73, 0x2f, 0x67, 0x65, 0x6e, 0x2f, 0x67, 0x6f, 0x2f, 0x74,
+	0x6b, 0x64, 0x2f, 0x64, 0x6f, 0x6f, 0x72, 0x2f, 0x76, 0x31, 0x3b, 0x64, 0x6f, 0x6f, 0x72, 0x76,
+	0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_tkd_door_v1_door_proto_rawDescOnce sync.Once
+	file_tkd_door_v1_door_proto_rawDescData = file_tkd_door_v1_door_proto_rawDesc
+)
+
+func file_tkd_door_v1_door_proto_rawDescGZIP() []byte {
+	file_tkd_door_v1_door_proto_rawDescOnce.Do(func() {
+		file_tkd_door_v1_door_proto_rawDescData = protoimpl.X.CompressGZIP(file_tkd_door_v1_door_proto_rawDescData)
+	})
+	return file_tkd_door_v1_door_proto_rawDescData
+}
+
+var file_tkd_door_v1_door_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_tkd_door_v1_door_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_tkd_door_v1_door_proto_goTypes = []any{
+	(DoorState)(0),                          // 0: tkd.door.v1.DoorState
+	(OverwriteMode)(0),                      // 1: tkd.door.v1.OverwriteMode
+	(*Lockdown)(nil),                        // 2: tkd.door.v1.Lockdown
+	(*Closure)(nil),                         // 3: tkd.door.v1.Closure
+	(*DoorStatus)(nil),                      // 4: tkd.door.v1.DoorStatus
+	(*Transition)(nil),                      // 5: tkd.door.v1.Transition
+	(*Overwrite)(nil),                       // 6: tkd.door.v1.Overwrite
+	(*ResetStep)(nil),                       // 7: tkd.door.v1.ResetStep
+	(*GetStateRequest)(nil),                 // 8: tkd.door.v1.GetStateRequest
+	(*GetStateResponse)(nil),                // 9: tkd.door.v1.GetStateResponse
+	(*ListUpcomingTransitionsRequest)(nil),  // 10: tkd.door.v1.ListUpcomingTransitionsRequest
+	(*ListUpcomingTransitionsResponse)(nil), // 11: tkd.door.v1.ListUpcomingTransitionsResponse
+	(*OverwriteRequest)(nil),                // 12: tkd.door.v1.OverwriteRequest
+	(*OverwriteResponse)(nil),               // 13: tkd.door.v1.OverwriteResponse
+	(*OpenRequest)(nil),                     // 14: tkd.door.v1.OpenRequest
+	(*OpenResponse)(nil),                    // 15: tkd.door.v1.OpenResponse
+	(*ResetRequest)(nil),                    // 16: tkd.door.v1.ResetRequest
+	(*ResetResponse)(nil),                   // 17: tkd.door.v1.ResetResponse
+	(*WatchStateRequest)(nil),               // 18: tkd.door.v1.WatchStateRequest
+	(*WatchStateResponse)(nil),              // 19: tkd.door.v1.WatchStateResponse
+	(*timestamppb.Timestamp)(nil),           // 20: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),             // 21: google.protobuf.Duration
+}
+var file_tkd_door_v1_door_proto_depIdxs = []int32{
+	20, // 0: tkd.door.v1.Lockdown.created_at:type_name -> google.protobuf.Timestamp
+	0,  // 1: tkd.door.v1.DoorStatus.state:type_name -> tkd.door.v1.DoorState
+	20, // 2: tkd.door.v1.DoorStatus.until:type_name -> google.protobuf.Timestamp
+	0,  // 3: tkd.door.v1.DoorStatus.actual_state:type_name -> tkd.door.v1.DoorState
+	2,  // 4: tkd.door.v1.DoorStatus.lockdown:type_name -> tkd.door.v1.Lockdown
+	3,  // 5: tkd.door.v1.DoorStatus.closure:type_name -> tkd.door.v1.Closure
+	20, // 6: tkd.door.v1.Transition.time:type_name -> google.protobuf.Timestamp
+	0,  // 7: tkd.door.v1.Transition.state:type_name -> tkd.door.v1.DoorState
+	20, // 8: tkd.door.v1.Transition.until:type_name -> google.protobuf.Timestamp
+	0,  // 9: tkd.door.v1.Overwrite.state:type_name -> tkd.door.v1.DoorState
+	20, // 10: tkd.door.v1.Overwrite.from:type_name -> google.protobuf.Timestamp
+	20, // 11: tkd.door.v1.Overwrite.until:type_name -> google.protobuf.Timestamp
+	20, // 12: tkd.door.v1.Overwrite.created_at:type_name -> google.protobuf.Timestamp
+	0,  // 13: tkd.door.v1.ResetStep.action:type_name -> tkd.door.v1.DoorState
+	20, // 14: tkd.door.v1.ResetStep.time:type_name -> google.protobuf.Timestamp
+	4,  // 15: tkd.door.v1.GetStateResponse.status:type_name -> tkd.door.v1.DoorStatus
+	20, // 16: tkd.door.v1.ListUpcomingTransitionsRequest.from:type_name -> google.protobuf.Timestamp
+	20, // 17: tkd.door.v1.ListUpcomingTransitionsRequest.to:type_name -> google.protobuf.Timestamp
+	5,  // 18: tkd.door.v1.ListUpcomingTransitionsResponse.transitions:type_name -> tkd.door.v1.Transition
+	0,  // 19: tkd.door.v1.OverwriteRequest.state:type_name -> tkd.door.v1.DoorState
+	20, // 20: tkd.door.v1.OverwriteRequest.from:type_name -> google.protobuf.Timestamp
+	21, // 21: tkd.door.v1.OverwriteRequest.duration:type_name -> google.protobuf.Duration
+	20, // 22: tkd.door.v1.OverwriteRequest.until:type_name -> google.protobuf.Timestamp
+	1,  // 23: tkd.door.v1.OverwriteRequest.mode:type_name -> tkd.door.v1.OverwriteMode
+	6,  // 24: tkd.door.v1.OverwriteResponse.overwrite:type_name -> tkd.door.v1.Overwrite
+	4,  // 25: tkd.door.v1.OverwriteResponse.status:type_name -> tkd.door.v1.DoorStatus
+	21, // 26: tkd.door.v1.OpenRequest.duration:type_name -> google.protobuf.Duration
+	21, // 27: tkd.door.v1.OpenResponse.duration:type_name -> google.protobuf.Duration
+	20, // 28: tkd.door.v1.OpenResponse.until:type_name -> google.protobuf.Timestamp
+	7,  // 29: tkd.door.v1.ResetResponse.steps:type_name -> tkd.door.v1.ResetStep
+	4,  // 30: tkd.door.v1.ResetResponse.status:type_name -> tkd.door.v1.DoorStatus
+	4,  // 31: tkd.door.v1.WatchStateResponse.status:type_name -> tkd.door.v1.DoorStatus
+	8,  // 32: tkd.door.v1.DoorService.GetState:input_type -> tkd.door.v1.GetStateRequest
+	10, // 33: tkd.door.v1.DoorService.ListUpcomingTransitions:input_type -> tkd.door.v1.ListUpcomingTransitionsRequest
+	12, // 34: tkd.door.v1.DoorService.Overwrite:input_type -> tkd.door.v1.OverwriteRequest
+	14, // 35: tkd.door.v1.DoorService.Open:input_type -> tkd.door.v1.OpenRequest
+	16, // 36: tkd.door.v1.DoorService.Reset:input_type -> tkd.door.v1.ResetRequest
+	18, // 37: tkd.door.v1.DoorService.WatchState:input_type -> tkd.door.v1.WatchStateRequest
+	9,  // 38: tkd.door.v1.DoorService.GetState:output_type -> tkd.door.v1.GetStateResponse
+	11, // 39: tkd.door.v1.DoorService.ListUpcomingTransitions:output_type -> tkd.door.v1.ListUpcomingTransitionsResponse
+	13, // 40: tkd.door.v1.DoorService.Overwrite:output_type -> tkd.door.v1.OverwriteResponse
+	15, // 41: tkd.door.v1.DoorService.Open:output_type -> tkd.door.v1.OpenResponse
+	17, // 42: tkd.door.v1.DoorService.Reset:output_type -> tkd.door.v1.ResetResponse
+	19, // 43: tkd.door.v1.DoorService.WatchState:output_type -> tkd.door.v1.WatchStateResponse
+	38, // [38:44] is the sub-list for method output_type
+	32, // [32:38] is the sub-list for method input_type
+	32, // [32:32] is the sub-list for extension type_name
+	32, // [32:32] is the sub-list for extension extendee
+	0,  // [0:32] is the sub-list for field type_name
+}
+
+func init() { file_tkd_door_v1_door_proto_init() }
+func file_tkd_door_v1_door_proto_init() {
+	if File_tkd_door_v1_door_proto != nil {
+		return
+	}
+	file_tkd_door_v1_door_proto_msgTypes[10].OneofWrappers = []any{
+		(*OverwriteRequest_Duration)(nil),
+		(*OverwriteRequest_Until)(nil),
+		(*OverwriteRequest_Mode)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_tkd_door_v1_door_proto_rawDesc,
+			NumEnums:      2,
+			NumMessages:   18,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_tkd_door_v1_door_proto_goTypes,
+		DependencyIndexes: file_tkd_door_v1_door_proto_depIdxs,
+		EnumInfos:         file_tkd_door_v1_door_proto_enumTypes,
+		MessageInfos:      file_tkd_door_v1_door_proto_msgTypes,
+	}.Build()
+	File_tkd_door_v1_door_proto = out.File
+	file_tkd_door_v1_door_proto_rawDesc = nil
+	file_tkd_door_v1_door_proto_goTypes = nil
+	file_tkd_door_v1_door_proto_depIdxs = nil
+}
@@ -0,0 +1,251 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: tkd/door/v1/door.proto
+
+package doorv1connect
+
+import (
+	context "context"
+	errors "errors"
+	connect_go "github.com/bufbuild/connect-go"
+	v1 "github.com/tierklinik-dobersberg/cis/gen/go/tkd/door/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect_go.IsAtLeastVersion0_1_0
+
+const (
+	// DoorServiceName is the fully-qualified name of the DoorService service.
+	DoorServiceName = "tkd.door.v1.DoorService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// DoorServiceGetStateProcedure is the fully-qualified name of the DoorService's GetState RPC.
+	DoorServiceGetStateProcedure = "/tkd.door.v1.DoorService/GetState"
+	// DoorServiceListUpcomingTransitionsProcedure is the fully-qualified name of the DoorService's
+	// ListUpcomingTransitions RPC.
+	DoorServiceListUpcomingTransitionsProcedure = "/tkd.door.v1.DoorService/ListUpcomingTransitions"
+	// DoorServiceOverwriteProcedure is the fully-qualified name of the DoorService's Overwrite RPC.
+	DoorServiceOverwriteProcedure = "/tkd.door.v1.DoorService/Overwrite"
+	// DoorServiceOpenProcedure is the fully-qualified name of the DoorService's Open RPC.
+	DoorServiceOpenProcedure = "/tkd.door.v1.DoorService/Open"
+	// DoorServiceResetProcedure is the fully-qualified name of the DoorService's Reset RPC.
+	DoorServiceResetProcedure = "/tkd.door.v1.DoorService/Reset"
+	// DoorServiceWatchStateProcedure is the fully-qualified name of the DoorService's WatchState RPC.
+	DoorServiceWatchStateProcedure = "/tkd.door.v1.DoorService/WatchState"
+)
+
+// DoorServiceClient is a client for the tkd.door.v1.DoorService service.
+type DoorServiceClient interface {
+	// GetState returns the current state of a door.
+	GetState(context.Context, *connect_go.Request[v1.GetStateRequest]) (*connect_go.Response[v1.GetStateResponse], error)
+	// ListUpcomingTransitions returns all changes of the desired door
+	// state in a time range.
+	ListUpcomingTransitions(context.Context, *connect_go.Request[v1.ListUpcomingTransitionsRequest]) (*connect_go.Response[v1.ListUpcomingTransitionsResponse], error)
+	// Overwrite overwrites the desired door state.
+	Overwrite(context.Context, *connect_go.Request[v1.OverwriteRequest]) (*connect_go.Response[v1.OverwriteResponse], error)
+	// Open opens the door for the next visitor.
+	Open(context.Context, *connect_go.Request[v1.OpenRequest]) (*connect_go.Response[v1.OpenResponse], error)
+	// Reset resets the door and re-applies the desired state.
+	Reset(context.Context, *connect_go.Request[v1.ResetRequest]) (*connect_go.Response[v1.ResetResponse], error)
+	// WatchState streams the door status whenever it changes. The
+	// current status is sent right after the call is established.
+	WatchState(context.Context, *connect_go.Request[v1.WatchStateRequest]) (*connect_go.ServerStreamForClient[v1.WatchStateResponse], error)
+}
+
+// NewDoorServiceClient constructs a client for the tkd.door.v1.DoorService service. By default, it
+// uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and sends
+// uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC() or
+// connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewDoorServiceClient(httpClient connect_go.HTTPClient, baseURL string, opts ...connect_go.ClientOption) DoorServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	return &doorServiceClient{
+		getState: connect_go.NewClient[v1.GetStateRequest, v1.GetStateResponse](
+			httpClient,
+			baseURL+DoorServiceGetStateProcedure,
+			opts...,
+		),
+		listUpcomingTransitions: connect_go.NewClient[v1.ListUpcomingTransitionsRequest, v1.ListUpcomingTransitionsResponse](
+			httpClient,
+			baseURL+DoorServiceListUpcomingTransitionsProcedure,
+			opts...,
+		),
+		overwrite: connect_go.NewClient[v1.OverwriteRequest, v1.OverwriteResponse](
+			httpClient,
+			baseURL+DoorServiceOverwriteProcedure,
+			opts...,
+		),
+		open: connect_go.NewClient[v1.OpenRequest, v1.OpenResponse](
+			httpClient,
+			baseURL+DoorServiceOpenProcedure,
+			opts...,
+		),
+		reset: connect_go.NewClient[v1.ResetRequest, v1.ResetResponse](
+			httpClient,
+			baseURL+DoorServiceResetProcedure,
+			opts...,
+		),
+		watchState: connect_go.NewClient[v1.WatchStateRequest, v1.WatchStateResponse](
+			httpClient,
+			baseURL+DoorServiceWatchStateProcedure,
+			opts...,
+		),
+	}
+}
+
+// doorServiceClient implements DoorServiceClient.
+type doorServiceClient struct {
+	getState                *connect_go.Client[v1.GetStateRequest, v1.GetStateResponse]
+	listUpcomingTransitions *connect_go.Client[v1.ListUpcomingTransitionsRequest, v1.ListUpcomingTransitionsResponse]
+	overwrite               *connect_go.Client[v1.OverwriteRequest, v1.OverwriteResponse]
+	open                    *connect_go.Client[v1.OpenRequest, v1.OpenResponse]
+	reset                   *connect_go.Client[v1.ResetRequest, v1.ResetResponse]
+	watchState              *connect_go.Client[v1.WatchStateRequest, v1.WatchStateResponse]
+}
+
+// GetState calls tkd.door.v1.DoorService.GetState.
+func (c *doorServiceClient) GetState(ctx context.Context, req *connect_go.Request[v1.GetStateRequest]) (*connect_go.Response[v1.GetStateResponse], error) {
+	return c.getState.CallUnary(ctx, req)
+}
+
+// ListUpcomingTransitions calls tkd.door.v1.DoorService.ListUpcomingTransitions.
+func (c *doorServiceClient) ListUpcomingTransitions(ctx context.Context, req *connect_go.Request[v1.ListUpcomingTransitionsRequest]) (*connect_go.Response[v1.ListUpcomingTransitionsResponse], error) {
+	return c.listUpcomingTransitions.CallUnary(ctx, req)
+}
+
+// Overwrite calls tkd.door.v1.DoorService.Overwrite.
+func (c *doorServiceClient) Overwrite(ctx context.Context, req *connect_go.Request[v1.OverwriteRequest]) (*connect_go.Response[v1.OverwriteResponse], error) {
+	return c.overwrite.CallUnary(ctx, req)
+}
+
+// Open calls tkd.door.v1.DoorService.Open.
+func (c *doorServiceClient) Open(ctx context.Context, req *connect_go.Request[v1.OpenRequest]) (*connect_go.Response[v1.OpenResponse], error) {
+	return c.open.CallUnary(ctx, req)
+}
+
+// Reset calls tkd.door.v1.DoorService.Reset.
+func (c *doorServiceClient) Reset(ctx context.Context, req *connect_go.Request[v1.ResetRequest]) (*connect_go.Response[v1.ResetResponse], error) {
+	return c.reset.CallUnary(ctx, req)
+}
+
+// WatchState calls tkd.door.v1.DoorService.WatchState.
+func (c *doorServiceClient) WatchState(ctx context.Context, req *connect_go.Request[v1.WatchStateRequest]) (*connect_go.ServerStreamForClient[v1.WatchStateResponse], error) {
+	return c.watchState.CallServerStream(ctx, req)
+}
+
+// DoorServiceHandler is an implementation of the tkd.door.v1.DoorService service.
+type DoorServiceHandler interface {
+	// GetState returns the current state of a door.
+	GetState(context.Context, *connect_go.Request[v1.GetStateRequest]) (*connect_go.Response[v1.GetStateResponse], error)
+	// ListUpcomingTransitions returns all changes of the desired door
+	// state in a time range.
+	ListUpcomingTransitions(context.Context, *connect_go.Request[v1.ListUpcomingTransitionsRequest]) (*connect_go.Response[v1.ListUpcomingTransitionsResponse], error)
+	// Overwrite overwrites the desired door state.
+	Overwrite(context.Context, *connect_go.Request[v1.OverwriteRequest]) (*connect_go.Response[v1.OverwriteResponse], error)
+	// Open opens the door for the next visitor.
+	Open(context.Context, *connect_go.Request[v1.OpenRequest]) (*connect_go.Response[v1.OpenResponse], error)
+	// Reset resets the door and re-applies the desired state.
+	Reset(context.Context, *connect_go.Request[v1.ResetRequest]) (*connect_go.Response[v1.ResetResponse], error)
+	// WatchState streams the door status whenever it changes. The
+	// current status is sent right after the call is established.
+	WatchState(context.Context, *connect_go.Request[v1.WatchStateRequest], *connect_go.ServerStream[v1.WatchStateResponse]) error
+}
+
+// NewDoorServiceHandler builds an HTTP handler from the service implementation. It returns the path
+// on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewDoorServiceHandler(svc DoorServiceHandler, opts ...connect_go.HandlerOption) (string, http.Handler) {
+	doorServiceGetStateHandler := connect_go.NewUnaryHandler(
+		DoorServiceGetStateProcedure,
+		svc.GetState,
+		opts...,
+	)
+	doorServiceListUpcomingTransitionsHandler := connect_go.NewUnaryHandler(
+		DoorServiceListUpcomingTransitionsProcedure,
+		svc.ListUpcomingTransitions,
+		opts...,
+	)
+	doorServiceOverwriteHandler := connect_go.NewUnaryHandler(
+		DoorServiceOverwriteProcedure,
+		svc.Overwrite,
+		opts...,
+	)
+	doorServiceOpenHandler := connect_go.NewUnaryHandler(
+		DoorServiceOpenProcedure,
+		svc.Open,
+		opts...,
+	)
+	doorServiceResetHandler := connect_go.NewUnaryHandler(
+		DoorServiceResetProcedure,
+		svc.Reset,
+		opts...,
+	)
+	doorServiceWatchStateHandler := connect_go.NewServerStreamHandler(
+		DoorServiceWatchStateProcedure,
+		svc.WatchState,
+		opts...,
+	)
+	return "/tkd.door.v1.DoorService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case DoorServiceGetStateProcedure:
+			doorServiceGetStateHandler.ServeHTTP(w, r)
+		case DoorServiceListUpcomingTransitionsProcedure:
+			doorServiceListUpcomingTransitionsHandler.ServeHTTP(w, r)
+		case DoorServiceOverwriteProcedure:
+			doorServiceOverwriteHandler.ServeHTTP(w, r)
+		case DoorServiceOpenProcedure:
+			doorServiceOpenHandler.ServeHTTP(w, r)
+		case DoorServiceResetProcedure:
+			doorServiceResetHandler.ServeHTTP(w, r)
+		case DoorServiceWatchStateProcedure:
+			doorServiceWatchStateHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedDoorServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedDoorServiceHandler struct{}
+
+func (UnimplementedDoorServiceHandler) GetState(context.Context, *connect_go.Request[v1.GetStateRequest]) (*connect_go.Response[v1.GetStateResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("tkd.door.v1.DoorService.GetState is not implemented"))
+}
+
+func (UnimplementedDoorServiceHandler) ListUpcomingTransitions(context.Context, *connect_go.Request[v1.ListUpcomingTransitionsRequest]) (*connect_go.Response[v1.ListUpcomingTransitionsResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("tkd.door.v1.DoorService.ListUpcomingTransitions is not implemented"))
+}
+
+func (UnimplementedDoorServiceHandler) Overwrite(context.Context, *connect_go.Request[v1.OverwriteRequest]) (*connect_go.Response[v1.OverwriteResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("tkd.door.v1.DoorService.Overwrite is not implemented"))
+}
+
+func (UnimplementedDoorServiceHandler) Open(context.Context, *connect_go.Request[v1.OpenRequest]) (*connect_go.Response[v1.OpenResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("tkd.door.v1.DoorService.Open is not implemented"))
+}
+
+func (UnimplementedDoorServiceHandler) Reset(context.Context, *connect_go.Request[v1.ResetRequest]) (*connect_go.Response[v1.ResetResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("tkd.door.v1.DoorService.Reset is not implemented"))
+}
+
+func (UnimplementedDoorServiceHandler) WatchState(context.Context, *connect_go.Request[v1.WatchStateRequest], *connect_go.ServerStream[v1.WatchStateResponse]) error {
+	return connect_go.NewError(connect_go.CodeUnimplemented, errors.New("tkd.door.v1.DoorService.WatchState is not implemented"))
+}
@@ -0,0 +1,44 @@
+package doorapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/labstack/echo/v4"
+	"github.com/tierklinik-dobersberg/cis/internal/app"
+	"github.com/tierklinik-dobersberg/cis/internal/door"
+	"github.com/tierklinik-dobersberg/cis/pkg/httperr"
+)
+
+// CancelOverwriteEndpoint cancels an active or scheduled
+// overwrite of a door.
+func CancelOverwriteEndpoint(grp *app.Router) {
+	handler := func(ctx context.Context, app *app.App, c echo.Context) error {
+		dc, err := getAuthorizedDoor(ctx, app, c, door.ActionOverwrite)
+		if err != nil {
+			return err
+		}
+
+		if err := dc.CancelOverwrite(ctx, c.Param("id")); err != nil {
+			if errors.Is(err, door.ErrUnknownOverwrite) {
+				return httperr.NotFound("overwrite", c.Param("id")).SetInternal(err)
+			}
+
+			return err
+		}
+
+		current, until, resetInProgress := dc.Current(ctx)
+
+		return c.JSON(http.StatusOK, gin.H{
+			"door":            dc.ID(),
+			"state":           current,
+			"until":           until,
+			"resetInProgress": resetInProgress,
+		})
+	}
+
+	grp.DELETE("v1/overwrites/:id", handler)
+	grp.DELETE("v1/:door/overwrites/:id", handler)
+}
@@ -8,21 +8,50 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/labstack/echo/v4"
 	"github.com/tierklinik-dobersberg/cis/internal/app"
+	"github.com/tierklinik-dobersberg/cis/internal/door"
 )
 
 // CurrentStateEndpoint returns the current state of the door
-// and when the next state change is expected.
+// and when the next state change is expected. If the door interfacer
+// is able to sense the physical door state it is reported as well.
+// The response also contains the reason for the desired state, the
+// active lockdown and the closure of the current day, if any. A closure
+// only affects the desired state if keepDoorLocked is set.
 func CurrentStateEndpoint(grp *app.Router) {
-	grp.GET(
-		"v1/state",
-		func(ctx context.Context, app *app.App, c echo.Context) error {
-			currentState, until, resetInProgress := app.Door.Current(ctx)
-
-			return c.JSON(http.StatusOK, gin.H{
-				"state":           currentState,
-				"until":           until.Format(time.RFC3339),
-				"resetInProgress": resetInProgress,
-			})
-		},
-	)
+	handler := func(ctx context.Context, app *app.App, c echo.Context) error {
+		dc, err := getAuthorizedDoor(ctx, app, c, door.ActionView)
+		if err != nil {
+			return err
+		}
+
+		currentState, until, resetInProgress := dc.Current(ctx)
+		actualState, reportedAt := dc.Actual()
+
+		res := gin.H{
+			"door":            dc.ID(),
+			"state":           currentState,
+			"desiredState":    currentState,
+			"actualState":     actualState,
+			"until":           until.Format(time.RFC3339),
+			"resetInProgress": resetInProgress,
+			"reason":          dc.ReasonFor(ctx, dc.Now()),
+		}
+
+		if lockdown := dc.ActiveLockdown(); lockdown != nil {
+			res["lockdown"] = lockdown
+		}
+
+		if closure := dc.ClosureFor(ctx, dc.Now()); closure != nil {
+			res["closure"] = closure
+		}
+
+		if !reportedAt.IsZero() {
+			res["actualStateReportedAt"] = reportedAt.Format(time.RFC3339)
+		}
+
+		return c.JSON(http.StatusOK, res)
+	}
+
+	grp.GET("v1/state", handler)
+	grp.GET("v1/:door/state", handler)
 }
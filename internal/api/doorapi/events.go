@@ -0,0 +1,95 @@
+package doorapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tierklinik-dobersberg/cis/internal/app"
+	"github.com/tierklinik-dobersberg/cis/internal/door"
+)
+
+// keepAliveInterval is the interval at which comments are sent
+// to keep idle event stream connections open.
+const keepAliveInterval = 30 * time.Second
+
+// EventsEndpoint streams door events to the client using
+// server-sent events. Right after connecting, a state-changed
+// event with the current door state is sent.
+func EventsEndpoint(grp *app.Router) {
+	handler := func(ctx context.Context, app *app.App, c echo.Context) error {
+		dc, err := getAuthorizedDoor(ctx, app, c, door.ActionView)
+		if err != nil {
+			return err
+		}
+
+		events, unsubscribe := dc.Subscribe()
+		defer unsubscribe()
+
+		res := c.Response()
+		res.Header().Set(echo.HeaderContentType, "text/event-stream")
+		res.Header().Set(echo.HeaderCacheControl, "no-cache")
+		res.Header().Set(echo.HeaderConnection, "keep-alive")
+		res.Header().Set("X-Accel-Buffering", "no")
+		res.WriteHeader(http.StatusOK)
+
+		state, until, _ := dc.Current(ctx)
+		if err := writeEvent(res, door.Event{
+			Type:  door.EventStateChanged,
+			Door:  dc.ID(),
+			Time:  dc.Now(),
+			State: state,
+			Until: &until,
+		}); err != nil {
+			return nil
+		}
+
+		ticker := time.NewTicker(keepAliveInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+
+			case <-ticker.C:
+				if _, err := fmt.Fprint(res, ": keep-alive\n\n"); err != nil {
+					return nil
+				}
+				res.Flush()
+
+			case evt, ok := <-events:
+				if !ok {
+					return nil
+				}
+
+				if err := writeEvent(res, evt); err != nil {
+					log.From(ctx).V(6).Logf("failed to write door event: %s", err)
+
+					return nil
+				}
+			}
+		}
+	}
+
+	grp.GET("v1/events", handler)
+	grp.GET("v1/:door/events", handler)
+}
+
+func writeEvent(res *echo.Response, evt door.Event) error {
+	blob, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(res, "event: %s\ndata: %s\n\n", evt.Type, blob); err != nil {
+		return err
+	}
+
+	res.Flush()
+
+	return nil
+}
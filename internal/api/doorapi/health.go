@@ -0,0 +1,32 @@
+package doorapi
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/labstack/echo/v4"
+	"github.com/tierklinik-dobersberg/cis/internal/app"
+	"github.com/tierklinik-dobersberg/cis/internal/door"
+)
+
+// HealthEndpoint returns the health of the door scheduler.
+func HealthEndpoint(grp *app.Router) {
+	handler := func(ctx context.Context, app *app.App, c echo.Context) error {
+		dc, err := getAuthorizedDoor(ctx, app, c, door.ActionView)
+		if err != nil {
+			return err
+		}
+
+		health := dc.Health()
+
+		return c.JSON(http.StatusOK, gin.H{
+			"door":    dc.ID(),
+			"healthy": health.Healthy(),
+			"health":  health,
+		})
+	}
+
+	grp.GET("v1/health", handler)
+	grp.GET("v1/:door/health", handler)
+}
@@ -0,0 +1,103 @@
+package doorapi
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tierklinik-dobersberg/cis/internal/app"
+	"github.com/tierklinik-dobersberg/cis/internal/door"
+	"github.com/tierklinik-dobersberg/cis/pkg/httperr"
+)
+
+// HistoryResponse is returned by the HistoryEndpoint.
+type HistoryResponse struct {
+	Records []door.AuditRecord `json:"records"`
+	Total   int64              `json:"total"`
+}
+
+// HistoryEndpoint returns the door audit log. Results may be
+// filtered by door, time range and actor and support pagination
+// using offset= and limit=.
+func HistoryEndpoint(grp *app.Router) {
+	handler := func(ctx context.Context, app *app.App, c echo.Context) error {
+		query := door.AuditQuery{
+			Door:  c.QueryParam("door"),
+			Actor: c.QueryParam("actor"),
+			Limit: 50,
+		}
+
+		if c.Param("door") != "" {
+			dc, err := getDoor(app, c)
+			if err != nil {
+				return err
+			}
+
+			query.Door = dc.ID()
+		}
+
+		// without a door filter the history of all doors is returned
+		// so the user must be permitted to view each of them.
+		names := []string{query.Door}
+		if query.Door == "" {
+			names = names[:0]
+			for _, dc := range app.Doors.List() {
+				names = append(names, dc.ID())
+			}
+		}
+
+		for _, name := range names {
+			if err := authorize(ctx, app, name, door.ActionView); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		if from := c.QueryParam("from"); from != "" {
+			query.From, err = time.Parse(time.RFC3339, from)
+			if err != nil {
+				return httperr.InvalidParameter("from", err.Error())
+			}
+		}
+
+		if to := c.QueryParam("to"); to != "" {
+			query.To, err = time.Parse(time.RFC3339, to)
+			if err != nil {
+				return httperr.InvalidParameter("to", err.Error())
+			}
+		}
+
+		if offset := c.QueryParam("offset"); offset != "" {
+			query.Offset, err = strconv.ParseInt(offset, 10, 64)
+			if err != nil || query.Offset < 0 {
+				return httperr.InvalidParameter("offset")
+			}
+		}
+
+		if limit := c.QueryParam("limit"); limit != "" {
+			query.Limit, err = strconv.ParseInt(limit, 10, 64)
+			if err != nil || query.Limit <= 0 {
+				return httperr.InvalidParameter("limit")
+			}
+		}
+
+		records, total, err := app.Doors.History(ctx, query)
+		if err != nil {
+			return err
+		}
+
+		if records == nil {
+			records = []door.AuditRecord{}
+		}
+
+		return c.JSON(http.StatusOK, HistoryResponse{
+			Records: records,
+			Total:   total,
+		})
+	}
+
+	grp.GET("v1/history", handler)
+	grp.GET("v1/:door/history", handler)
+}
@@ -0,0 +1,97 @@
+package doorapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tierklinik-dobersberg/cis/internal/app"
+	"github.com/tierklinik-dobersberg/cis/internal/door"
+	"github.com/tierklinik-dobersberg/cis/pkg/httperr"
+	"github.com/tierklinik-dobersberg/cis/runtime/session"
+)
+
+// Door describes a configured door.
+type Door struct {
+	ID           string     `json:"id"`
+	DisplayName  string     `json:"displayName"`
+	OpeningHours []string   `json:"openingHours,omitempty"`
+	State        door.State `json:"state"`
+	Until        string     `json:"until"`
+}
+
+// ListDoorsEndpoint returns all doors the user is permitted to
+// view together with their current state.
+func ListDoorsEndpoint(grp *app.Router) {
+	grp.GET(
+		"v1/doors",
+		func(ctx context.Context, app *app.App, c echo.Context) error {
+			user := session.UserFromCtx(ctx)
+
+			res := make([]Door, 0)
+			for _, dc := range app.Doors.List() {
+				if !app.Doors.Allowed(user, dc.ID(), door.ActionView) {
+					continue
+				}
+
+				state, until, _ := dc.Current(ctx)
+
+				res = append(res, Door{
+					ID:           dc.ID(),
+					DisplayName:  dc.DisplayName(),
+					OpeningHours: dc.OpeningHours(),
+					State:        state,
+					Until:        until.Format(time.RFC3339),
+				})
+			}
+
+			return c.JSON(http.StatusOK, res)
+		},
+	)
+}
+
+// getDoor returns the door controller selected by the :door path
+// parameter. If the parameter is not set, the default door is returned.
+func getDoor(app *app.App, c echo.Context) (*door.Controller, error) {
+	dc, err := app.Doors.Get(c.Param("door"))
+	if err != nil {
+		if errors.Is(err, door.ErrUnknownDoor) {
+			return nil, httperr.NotFound("door", c.Param("door")).SetInternal(err)
+		}
+
+		return nil, err
+	}
+
+	return dc, nil
+}
+
+// getAuthorizedDoor is like getDoor but returns 403 Forbidden if the
+// user is not permitted to perform action on the selected door.
+func getAuthorizedDoor(ctx context.Context, app *app.App, c echo.Context, action door.Action) (*door.Controller, error) {
+	dc, err := getDoor(app, c)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorize(ctx, app, dc.ID(), action); err != nil {
+		return nil, err
+	}
+
+	return dc, nil
+}
+
+// authorize returns 403 Forbidden if the user is not permitted to
+// perform action on the door with the given name.
+func authorize(ctx context.Context, app *app.App, name string, action door.Action) error {
+	if err := app.Doors.Authorize(ctx, name, action); err != nil {
+		if errors.Is(err, door.ErrPermissionDenied) {
+			return httperr.Forbidden(err.Error()).SetInternal(err)
+		}
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package doorapi
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tierklinik-dobersberg/cis/internal/app"
+	"github.com/tierklinik-dobersberg/cis/internal/door"
+)
+
+// ListOverwritesEndpoint returns all active and upcoming
+// overwrites of a door.
+func ListOverwritesEndpoint(grp *app.Router) {
+	handler := func(ctx context.Context, app *app.App, c echo.Context) error {
+		dc, err := getAuthorizedDoor(ctx, app, c, door.ActionView)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, dc.Overwrites(dc.Now()))
+	}
+
+	grp.GET("v1/overwrites", handler)
+	grp.GET("v1/:door/overwrites", handler)
+}
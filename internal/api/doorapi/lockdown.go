@@ -0,0 +1,102 @@
+package doorapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/labstack/echo/v4"
+	"github.com/tierklinik-dobersberg/cis/internal/app"
+	"github.com/tierklinik-dobersberg/cis/internal/door"
+	"github.com/tierklinik-dobersberg/cis/pkg/httperr"
+)
+
+// LockdownEndpoint puts the door into lockdown. The door is locked
+// until the lockdown is released, regardless of opening hours and
+// manual overwrites. Doors without LockdownReleaseRoles cannot be
+// put into lockdown.
+func LockdownEndpoint(grp *app.Router) {
+	handler := func(ctx context.Context, app *app.App, c echo.Context) error {
+		dc, err := getAuthorizedDoor(ctx, app, c, door.ActionLockdown)
+		if err != nil {
+			return err
+		}
+
+		var body struct {
+			Reason string `json:"reason"`
+		}
+		if err := json.NewDecoder(c.Request().Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+			return httperr.BadRequest("invalid body").SetInternal(err)
+		}
+
+		lockdown, err := dc.StartLockdown(ctx, body.Reason)
+		if err != nil {
+			if errors.Is(err, door.ErrNoLockdownReleaseRoles) {
+				return httperr.PreconditionFailed(err.Error()).SetInternal(err)
+			}
+
+			return err
+		}
+
+		current, until, resetInProgress := dc.Current(ctx)
+
+		return c.JSON(http.StatusOK, gin.H{
+			"door":            dc.ID(),
+			"state":           current,
+			"until":           until,
+			"resetInProgress": resetInProgress,
+			"lockdown":        lockdown,
+		})
+	}
+
+	grp.POST("v1/lockdown", handler)
+	grp.POST("v1/:door/lockdown", handler)
+}
+
+// ReleaseLockdownEndpoint releases the lockdown of the door. Only
+// users with one of the configured LockdownReleaseRoles are permitted
+// to do so.
+func ReleaseLockdownEndpoint(grp *app.Router) {
+	handler := func(ctx context.Context, app *app.App, c echo.Context) error {
+		dc, err := getAuthorizedDoor(ctx, app, c, door.ActionLockdown)
+		if err != nil {
+			return err
+		}
+
+		if err := dc.ReleaseLockdown(ctx); err != nil {
+			switch {
+			case errors.Is(err, door.ErrLockdownReleaseDenied):
+				return httperr.Forbidden(err.Error()).SetInternal(err)
+			case errors.Is(err, door.ErrNoLockdown):
+				return httperr.NotFound("lockdown", dc.ID()).SetInternal(err)
+			}
+
+			return err
+		}
+
+		current, until, resetInProgress := dc.Current(ctx)
+
+		return c.JSON(http.StatusOK, gin.H{
+			"door":            dc.ID(),
+			"state":           current,
+			"until":           until,
+			"resetInProgress": resetInProgress,
+		})
+	}
+
+	grp.DELETE("v1/lockdown", handler)
+	grp.DELETE("v1/:door/lockdown", handler)
+}
+
+// lockdownConflict converts door.ErrLockdownActive into a
+// 409 Conflict error.
+func lockdownConflict(err error) error {
+	if errors.Is(err, door.ErrLockdownActive) {
+		return httperr.Conflict(err.Error()).SetInternal(err)
+	}
+
+	return err
+}
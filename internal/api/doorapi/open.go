@@ -0,0 +1,80 @@
+package doorapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/labstack/echo/v4"
+	"github.com/tierklinik-dobersberg/cis/internal/app"
+	"github.com/tierklinik-dobersberg/cis/internal/door"
+	"github.com/tierklinik-dobersberg/cis/pkg/httperr"
+)
+
+// openTimeout is the time the door interfacer may take to
+// accept an open command.
+const openTimeout = 10 * time.Second
+
+// OpenEndpoint opens the door for the next visitor. The request body
+// may contain the duration the door should be kept open. If omitted,
+// the configured default is used.
+func OpenEndpoint(grp *app.Router) {
+	handler := func(ctx context.Context, app *app.App, c echo.Context) error {
+		dc, err := getAuthorizedDoor(ctx, app, c, door.ActionOpen)
+		if err != nil {
+			return err
+		}
+
+		var body struct {
+			Duration string `json:"duration"`
+		}
+		if err := json.NewDecoder(c.Request().Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+			return httperr.BadRequest("invalid body").SetInternal(err)
+		}
+
+		return openDoor(ctx, c, dc, body.Duration)
+	}
+
+	grp.POST("v1/open", handler)
+	grp.POST("v1/:door/open", handler)
+}
+
+// openDoor opens dc for the requested duration and writes the
+// response.
+func openDoor(ctx context.Context, c echo.Context, dc *door.Controller, duration string) error {
+	var requested time.Duration
+	if duration != "" {
+		var err error
+		requested, err = time.ParseDuration(duration)
+		if err != nil {
+			return httperr.InvalidField("duration")
+		}
+	}
+
+	d, err := dc.OpenDuration(requested)
+	if err != nil {
+		return httperr.InvalidField("duration").SetInternal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, openTimeout)
+	defer cancel()
+
+	if err := dc.Open(ctx, d); err != nil {
+		if errors.Is(err, door.ErrLockdownActive) {
+			return lockdownConflict(err)
+		}
+
+		return httperr.InternalError(err.Error()).SetInternal(err)
+	}
+
+	return c.JSON(http.StatusOK, gin.H{
+		"door":     dc.ID(),
+		"state":    door.Open,
+		"duration": d.String(),
+		"until":    dc.Now().Add(d),
+	})
+}
@@ -3,6 +3,7 @@ package doorapi
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"time"
 
@@ -15,72 +16,123 @@ import (
 )
 
 // OverwriteEndpoint allows to overwrite the door state
-// for a specified amount of time.
+// for a specified amount of time. The overwrite may either start
+// immediately and last for duration or be scheduled using from
+// and until. Alternatively, mode may be set to until-next-change,
+// until-next-opening or until-end-of-day to resolve the end of the
+// overwrite from the opening hours of the door. The resolved end
+// time is returned as overwriteUntil.
 func OverwriteEndpoint(grp *app.Router) {
-	grp.POST(
-		"v1/overwrite",
-		func(ctx context.Context, app *app.App, c echo.Context) error {
-			// parse the request body
-			var body struct {
-				State    string `json:"state"`
-				Duration string `json:"duration"`
+	handler := func(ctx context.Context, app *app.App, c echo.Context) error {
+		dc, err := getAuthorizedDoor(ctx, app, c, door.ActionOverwrite)
+		if err != nil {
+			return err
+		}
+
+		// parse the request body
+		var body struct {
+			State    string `json:"state"`
+			Duration string `json:"duration"`
+			From     string `json:"from"`
+			Until    string `json:"until"`
+			Mode     string `json:"mode"`
+			Priority int    `json:"priority"`
+		}
+		if err := json.NewDecoder(c.Request().Body).Decode(&body); err != nil {
+			return httperr.BadRequest("invalid body").SetInternal(err)
+		}
+
+		// convert the command to the expected door state.
+		switch body.State {
+		case "lock":
+			body.State = "locked"
+		case "unlock":
+			body.State = "unlocked"
+		case "open":
+			// open is not actually a overwrite but rather
+			// a short term action. duration is the time the
+			// door is kept open. It requires the same
+			// permission as the open endpoint.
+			if err := authorize(ctx, app, dc.ID(), door.ActionOpen); err != nil {
+				return err
 			}
-			if err := json.NewDecoder(c.Request().Body).Decode(&body); err != nil {
-				return httperr.BadRequest("invalid body").SetInternal(err)
+
+			return openDoor(ctx, c, dc, body.Duration)
+
+		default:
+			return httperr.InvalidField("state")
+		}
+
+		from := dc.Now()
+		if body.From != "" {
+			from, err = time.Parse(time.RFC3339, body.From)
+			if err != nil {
+				return httperr.InvalidField("from")
 			}
+		}
 
-			// convert the command to the expected door state.
-			switch body.State {
-			case "lock":
-				body.State = "locked"
-			case "unlock":
-				body.State = "unlocked"
-			case "open":
-				// open is not actually a overwrite but rather
-				// a short term action
-				ctx, cancel := context.WithTimeout(ctx, time.Second*2)
-				defer cancel()
-
-				if err := app.Door.Open(ctx); err != nil {
-					return httperr.InternalError(err.Error()).SetInternal(err)
+		var until time.Time
+		switch {
+		case body.Mode != "":
+			until, err = dc.ResolveOverwriteUntil(ctx, door.OverwriteMode(body.Mode), from)
+			if err != nil {
+				if errors.Is(err, door.ErrInvalidOverwriteMode) {
+					return httperr.InvalidField("mode").SetInternal(err)
 				}
 
-				return c.NoContent(http.StatusOK)
+				return httperr.Conflict(err.Error()).SetInternal(err)
+			}
 
-			default:
-				return httperr.InvalidField("state")
+		case body.Until != "":
+			until, err = time.Parse(time.RFC3339, body.Until)
+			if err != nil || !until.After(from) {
+				return httperr.InvalidField("until")
 			}
 
+		default:
 			// ensure it contains a valid duration
 			setDuration, err := time.ParseDuration(body.Duration)
 			if err != nil {
 				return httperr.InvalidField("duration")
 			}
-			if setDuration < 0 {
+			if setDuration <= 0 {
 				return httperr.InvalidField("duration")
 			}
 
-			until := time.Now().Add(setDuration)
+			until = from.Add(setDuration)
+		}
 
-			log.From(ctx).WithFields(logger.Fields{
-				"duration": setDuration.String(),
-				"state":    body.State,
-				"until":    until.String(),
-			}).V(6).Logf("received manual door overwrite request")
+		if !until.After(dc.Now()) {
+			return httperr.InvalidField("until")
+		}
 
-			// overwrite the current state
-			err = app.Door.Overwrite(ctx, door.State(body.State), until)
-			if err != nil {
-				return err
-			}
+		log.From(ctx).WithFields(logger.Fields{
+			"door":     dc.ID(),
+			"from":     from.String(),
+			"until":    until.String(),
+			"priority": body.Priority,
+			"state":    body.State,
+			"mode":     body.Mode,
+		}).V(6).Logf("received manual door overwrite request")
+
+		// overwrite the current state
+		overwrite, err := dc.ScheduleOverwrite(ctx, door.State(body.State), from, until, body.Priority)
+		if err != nil {
+			return err
+		}
+
+		current, next, resetInProgress := dc.Current(ctx)
 
-			current, next, resetInProgress := app.Door.Current(ctx)
+		return c.JSON(http.StatusOK, gin.H{
+			"door":            dc.ID(),
+			"state":           current,
+			"until":           next,
+			"resetInProgress": resetInProgress,
+			"overwrite":       overwrite,
+			"overwriteUntil":  until,
+		})
+	}
 
-			return c.JSON(http.StatusOK, gin.H{
-				"state":           current,
-				"until":           next,
-				"resetInProgress": resetInProgress,
-			})
-		},
-	)
+	grp.POST("v1/overwrite", handler)
+	grp.POST("v1/:door/overwrite", handler)
 }
@@ -0,0 +1,153 @@
+package doorapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/ppacher/system-conf/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	idmv1 "github.com/tierklinik-dobersberg/apis/gen/go/tkd/idm/v1"
+	"github.com/tierklinik-dobersberg/cis/internal/app"
+	"github.com/tierklinik-dobersberg/cis/internal/cfgspec"
+	"github.com/tierklinik-dobersberg/cis/internal/door"
+	"github.com/tierklinik-dobersberg/cis/internal/holidays"
+	"github.com/tierklinik-dobersberg/cis/internal/openinghours"
+	"github.com/tierklinik-dobersberg/cis/pkg/clock"
+	"github.com/tierklinik-dobersberg/cis/runtime"
+	"github.com/tierklinik-dobersberg/cis/runtime/configprovider/memoryprovider"
+	"github.com/tierklinik-dobersberg/cis/runtime/session"
+)
+
+type noHolidays struct{}
+
+func (noHolidays) ForYear(context.Context, int) ([]holidays.Holiday, error) {
+	return nil, nil
+}
+
+func (noHolidays) IsHoliday(context.Context, time.Time) (bool, error) {
+	return false, nil
+}
+
+func permissionSection(role string, actions ...string) conf.Section {
+	sec := conf.Section{
+		Name: "DoorPermission",
+		Options: conf.Options{
+			{Name: "Roles", Value: role},
+		},
+	}
+
+	for _, action := range actions {
+		sec.Options = append(sec.Options, conf.Option{Name: "Actions", Value: action})
+	}
+
+	return sec
+}
+
+// newTestServer returns an echo server that serves the door API for a
+// single disabled door using sections as the configuration.
+func newTestServer(t *testing.T, sections ...conf.Section) *echo.Echo {
+	t.Helper()
+
+	ctx := context.Background()
+
+	ohCtrl, err := openinghours.NewController(cfgspec.Config{
+		TimeZone: "Europe/Vienna",
+	}, noHolidays{}, clock.System)
+	require.NoError(t, err)
+
+	sections = append(sections, conf.Section{
+		Name: "Door",
+		Options: conf.Options{
+			{Name: "Name", Value: door.DefaultDoorName},
+			{Name: "Type", Value: door.DisabledType},
+		},
+	})
+
+	cs := new(runtime.ConfigSchema)
+	cs.SetProvider(memoryprovider.New(sections...))
+
+	mng, err := door.NewManager(ctx, ohCtrl, cs, nil, nil, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, mng.Start())
+	t.Cleanup(func() {
+		_ = mng.Stop()
+	})
+
+	e := echo.New()
+	Setup(&app.App{
+		Doors:        mng,
+		OpeningHours: ohCtrl,
+	}, e.Group("/api/door/"))
+
+	return e
+}
+
+func doRequest(e *echo.Echo, user *idmv1.Profile, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req = req.WithContext(session.WithUser(req.Context(), user))
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestOverwriteOpenRequiresOpenPermission(t *testing.T) {
+	t.Parallel()
+
+	e := newTestServer(t,
+		permissionSection("operator", "overwrite"),
+		permissionSection("admin", "overwrite", "open"),
+	)
+
+	operator := &idmv1.Profile{
+		Roles: []*idmv1.Role{{Id: "1", Name: "operator"}},
+	}
+	admin := &idmv1.Profile{
+		Roles: []*idmv1.Role{{Id: "2", Name: "admin"}},
+	}
+
+	// the operator may overwrite the door state but not open the door.
+	rec := doRequest(e, operator, http.MethodPost, "/api/door/v1/overwrite", `{"state": "lock", "duration": "1h"}`)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = doRequest(e, operator, http.MethodPost, "/api/door/v1/overwrite", `{"state": "open"}`)
+	assert.Equal(t, http.StatusForbidden, rec.Code, rec.Body.String())
+
+	rec = doRequest(e, operator, http.MethodPost, "/api/door/v1/open", `{}`)
+	assert.Equal(t, http.StatusForbidden, rec.Code, rec.Body.String())
+
+	rec = doRequest(e, admin, http.MethodPost, "/api/door/v1/overwrite", `{"state": "open"}`)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+}
+
+func TestPermissionsNotConfigured(t *testing.T) {
+	t.Parallel()
+
+	e := newTestServer(t)
+
+	user := &idmv1.Profile{
+		Roles: []*idmv1.Role{{Id: "1", Name: "staff"}},
+	}
+
+	// without any DoorPermission authenticated users may only view
+	// the doors.
+	rec := doRequest(e, user, http.MethodGet, "/api/door/v1/state", "")
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	for _, path := range []string{"/api/door/v1/overwrite", "/api/door/v1/open", "/api/door/v1/reset", "/api/door/v1/lockdown"} {
+		rec = doRequest(e, user, http.MethodPost, path, `{"state": "unlock", "duration": "1h"}`)
+		assert.Equal(t, http.StatusForbidden, rec.Code, path)
+	}
+
+	rec = doRequest(e, nil, http.MethodGet, "/api/door/v1/state", "")
+	assert.Equal(t, http.StatusForbidden, rec.Code, rec.Body.String())
+}
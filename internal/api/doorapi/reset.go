@@ -7,26 +7,35 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/labstack/echo/v4"
 	"github.com/tierklinik-dobersberg/cis/internal/app"
+	"github.com/tierklinik-dobersberg/cis/internal/door"
 )
 
 // ResetDoorEndpoint resets the door controller and the door itself
-// and re-applies the current expected state.
+// and re-applies the current expected state. The response contains
+// the outcome of each step of the reset sequence.
 func ResetDoorEndpoint(grp *app.Router) {
-	grp.POST(
-		"v1/reset",
-		func(ctx context.Context, app *app.App, c echo.Context) error {
-			err := app.Door.Reset(ctx)
-			if err != nil {
-				return err
-			}
+	handler := func(ctx context.Context, app *app.App, c echo.Context) error {
+		dc, err := getAuthorizedDoor(ctx, app, c, door.ActionReset)
+		if err != nil {
+			return err
+		}
 
-			current, until, resetInProgress := app.Door.Current(ctx)
+		steps, err := dc.Reset(ctx)
+		if err != nil {
+			return lockdownConflict(err)
+		}
 
-			return c.JSON(http.StatusOK, gin.H{
-				"state":           current,
-				"until":           until,
-				"resetInProgress": resetInProgress,
-			})
-		},
-	)
+		current, until, resetInProgress := dc.Current(ctx)
+
+		return c.JSON(http.StatusOK, gin.H{
+			"door":            dc.ID(),
+			"state":           current,
+			"until":           until,
+			"resetInProgress": resetInProgress,
+			"steps":           steps,
+		})
+	}
+
+	grp.POST("v1/reset", handler)
+	grp.POST("v1/:door/reset", handler)
 }
@@ -12,15 +12,57 @@ var log = pkglog.New("doorapi")
 func Setup(a *app.App, grp *echo.Group) {
 	router := app.NewRouter(grp, a)
 
+	// Endpoints that operate on a single door are available with and
+	// without the :door parameter. If omitted, the default door is used.
+
+	// GET /api/door/v1/doors
+	ListDoorsEndpoint(router)
+
 	// GET /api/door/v1/test/:year/:month/:day/:hour/:minute
+	// GET /api/door/v1/:door/test/:year/:month/:day/:hour/:minute
 	TestStateEndpoint(router)
 
 	// GET /api/door/v1/state
+	// GET /api/door/v1/:door/state
 	CurrentStateEndpoint(router)
 
 	// POST /api/door/v1/reset
+	// POST /api/door/v1/:door/reset
 	ResetDoorEndpoint(router)
 
 	// POST /api/door/v1/overwrite
+	// POST /api/door/v1/:door/overwrite
 	OverwriteEndpoint(router)
+
+	// POST /api/door/v1/open
+	// POST /api/door/v1/:door/open
+	OpenEndpoint(router)
+
+	// POST /api/door/v1/lockdown
+	// POST /api/door/v1/:door/lockdown
+	LockdownEndpoint(router)
+
+	// DELETE /api/door/v1/lockdown
+	// DELETE /api/door/v1/:door/lockdown
+	ReleaseLockdownEndpoint(router)
+
+	// GET /api/door/v1/overwrites
+	// GET /api/door/v1/:door/overwrites
+	ListOverwritesEndpoint(router)
+
+	// DELETE /api/door/v1/overwrites/:id
+	// DELETE /api/door/v1/:door/overwrites/:id
+	CancelOverwriteEndpoint(router)
+
+	// GET /api/door/v1/history
+	// GET /api/door/v1/:door/history
+	HistoryEndpoint(router)
+
+	// GET /api/door/v1/events
+	// GET /api/door/v1/:door/events
+	EventsEndpoint(router)
+
+	// GET /api/door/v1/health
+	// GET /api/door/v1/:door/health
+	HealthEndpoint(router)
 }
@@ -9,46 +9,53 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/labstack/echo/v4"
 	"github.com/tierklinik-dobersberg/cis/internal/app"
+	"github.com/tierklinik-dobersberg/cis/internal/door"
 	"github.com/tierklinik-dobersberg/cis/pkg/httperr"
 )
 
 // TestStateEndpoint allows to test the desired door state for any
 // point in time.
 func TestStateEndpoint(grp *app.Router) {
-	grp.GET(
-		"v1/test/:year/:month/:day/:hour/:minute",
-		func(ctx context.Context, app *app.App, c echo.Context) error {
-			year, err := getIntParam("year", c)
-			if err != nil {
-				return err
-			}
-			month, err := getIntParam("month", c)
-			if err != nil {
-				return err
-			}
-			day, err := getIntParam("day", c)
-			if err != nil {
-				return err
-			}
-			hour, err := getIntParam("hour", c)
-			if err != nil {
-				return err
-			}
-			minute, err := getIntParam("minute", c)
-			if err != nil {
-				return err
-			}
+	handler := func(ctx context.Context, app *app.App, c echo.Context) error {
+		dc, err := getAuthorizedDoor(ctx, app, c, door.ActionView)
+		if err != nil {
+			return err
+		}
 
-			date := time.Date(year, time.Month(month), day, hour, minute, 0, 0, app.Location())
+		year, err := getIntParam("year", c)
+		if err != nil {
+			return err
+		}
+		month, err := getIntParam("month", c)
+		if err != nil {
+			return err
+		}
+		day, err := getIntParam("day", c)
+		if err != nil {
+			return err
+		}
+		hour, err := getIntParam("hour", c)
+		if err != nil {
+			return err
+		}
+		minute, err := getIntParam("minute", c)
+		if err != nil {
+			return err
+		}
 
-			result, until := app.Door.StateFor(ctx, date)
+		date := time.Date(year, time.Month(month), day, hour, minute, 0, 0, app.Location())
 
-			return c.JSON(http.StatusOK, gin.H{
-				"desiredState": string(result),
-				"until":        until.Format(time.RFC3339),
-			})
-		},
-	)
+		result, until := dc.StateFor(ctx, date)
+
+		return c.JSON(http.StatusOK, gin.H{
+			"door":         dc.ID(),
+			"desiredState": string(result),
+			"until":        until.Format(time.RFC3339),
+		})
+	}
+
+	grp.GET("v1/test/:year/:month/:day/:hour/:minute", handler)
+	grp.GET("v1/:door/test/:year/:month/:day/:hour/:minute", handler)
 }
 
 func getIntParam(name string, c echo.Context) (int, error) {
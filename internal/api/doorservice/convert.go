@@ -0,0 +1,102 @@
+package doorservice
+
+import (
+	"context"
+	"time"
+
+	doorv1 "github.com/tierklinik-dobersberg/cis/gen/go/tkd/door/v1"
+	"github.com/tierklinik-dobersberg/cis/internal/door"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var states = map[door.State]doorv1.DoorState{
+	door.Locked:   doorv1.DoorState_DOOR_STATE_LOCKED,
+	door.Unlocked: doorv1.DoorState_DOOR_STATE_UNLOCKED,
+	door.Open:     doorv1.DoorState_DOOR_STATE_OPEN,
+}
+
+var overwriteModes = map[doorv1.OverwriteMode]door.OverwriteMode{
+	doorv1.OverwriteMode_OVERWRITE_MODE_UNTIL_NEXT_CHANGE:  door.OverwriteUntilNextChange,
+	doorv1.OverwriteMode_OVERWRITE_MODE_UNTIL_NEXT_OPENING: door.OverwriteUntilNextOpening,
+	doorv1.OverwriteMode_OVERWRITE_MODE_UNTIL_END_OF_DAY:   door.OverwriteUntilEndOfDay,
+}
+
+// stateToProto converts state into it's protobuf representation.
+// Unknown states are converted to DOOR_STATE_UNSPECIFIED.
+func stateToProto(state door.State) doorv1.DoorState {
+	return states[state]
+}
+
+// timeToProto converts t into a protobuf timestamp. The zero time is
+// converted to nil.
+func timeToProto(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+
+	return timestamppb.New(t)
+}
+
+// statusToProto returns the current status of dc.
+func statusToProto(ctx context.Context, dc *door.Controller) *doorv1.DoorStatus {
+	state, until, resetInProgress := dc.Current(ctx)
+	actual, _ := dc.Actual()
+
+	status := &doorv1.DoorStatus{
+		Door:            dc.ID(),
+		DisplayName:     dc.DisplayName(),
+		State:           stateToProto(state),
+		Until:           timeToProto(until),
+		Reason:          string(dc.ReasonFor(ctx, dc.Now())),
+		ResetInProgress: resetInProgress,
+		ActualState:     stateToProto(actual),
+	}
+
+	if lockdown := dc.ActiveLockdown(); lockdown != nil {
+		status.Lockdown = &doorv1.Lockdown{
+			Reason:      lockdown.Reason,
+			SessionUser: lockdown.SessionUser,
+			CreatedAt:   timeToProto(lockdown.CreatedAt),
+		}
+	}
+
+	if closure := dc.ClosureFor(ctx, dc.Now()); closure != nil {
+		status.Closure = &doorv1.Closure{
+			Reason:         closure.Reason,
+			KeepDoorLocked: closure.KeepDoorLocked,
+		}
+	}
+
+	return status
+}
+
+func overwriteToProto(overwrite *door.Overwrite) *doorv1.Overwrite {
+	return &doorv1.Overwrite{
+		Id:          overwrite.ID.Hex(),
+		Door:        overwrite.Door,
+		State:       stateToProto(overwrite.State),
+		From:        timeToProto(overwrite.From),
+		Until:       timeToProto(overwrite.Until),
+		Priority:    int32(overwrite.Priority),
+		SessionUser: overwrite.SessionUser,
+		CreatedAt:   timeToProto(overwrite.CreatedAt),
+	}
+}
+
+func transitionToProto(transition door.Transition) *doorv1.Transition {
+	return &doorv1.Transition{
+		Time:   timeToProto(transition.Time),
+		State:  stateToProto(transition.State),
+		Until:  timeToProto(transition.Until),
+		Reason: string(transition.Reason),
+	}
+}
+
+func resetStepToProto(step door.ResetStepResult) *doorv1.ResetStep {
+	return &doorv1.ResetStep{
+		Step:   int32(step.Step),
+		Action: stateToProto(step.Action),
+		Time:   timeToProto(step.Time),
+		Error:  step.Error,
+	}
+}
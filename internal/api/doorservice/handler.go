@@ -0,0 +1,52 @@
+package doorservice
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/tierklinik-dobersberg/cis/gen/go/tkd/door/v1/doorv1connect"
+	"github.com/tierklinik-dobersberg/cis/internal/door"
+	"github.com/tierklinik-dobersberg/cis/runtime/session"
+)
+
+// NewHandler returns the path and the HTTP handler that serve the door
+// service for doors. Requests that are not associated with a session
+// user are rejected with CodeUnauthenticated before reaching the service.
+func NewHandler(doors *door.Manager) (string, http.Handler) {
+	return doorv1connect.NewDoorServiceHandler(
+		New(doors),
+		connect.WithInterceptors(authInterceptor{}),
+	)
+}
+
+// errUnauthenticated is returned for all requests without a session user.
+var errUnauthenticated = errors.New("authentication required")
+
+// authInterceptor rejects all requests without a session user.
+type authInterceptor struct{}
+
+func (authInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if session.UserFromCtx(ctx) == nil {
+			return nil, connect.NewError(connect.CodeUnauthenticated, errUnauthenticated)
+		}
+
+		return next(ctx, req)
+	}
+}
+
+func (authInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (authInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		if session.UserFromCtx(ctx) == nil {
+			return connect.NewError(connect.CodeUnauthenticated, errUnauthenticated)
+		}
+
+		return next(ctx, conn)
+	}
+}
@@ -0,0 +1,289 @@
+// Package doorservice implements the tkd.door.v1.DoorService Connect
+// service on top of the door manager.
+//
+// All calls must be associated with an IDM user, which cisd derives from
+// the X-Remote-User-ID header set by the authenticating reverse proxy.
+// Services that are not operated by a person, like the phone system, use
+// a dedicated IDM user whose roles are granted the required door actions
+// by a DoorPermission.
+package doorservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	doorv1 "github.com/tierklinik-dobersberg/cis/gen/go/tkd/door/v1"
+	"github.com/tierklinik-dobersberg/cis/gen/go/tkd/door/v1/doorv1connect"
+	"github.com/tierklinik-dobersberg/cis/internal/door"
+	"github.com/tierklinik-dobersberg/cis/pkg/pkglog"
+	"github.com/tierklinik-dobersberg/cis/runtime/session"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+var log = pkglog.New("doorservice")
+
+const (
+	// openTimeout is the time the door interfacer may take to
+	// accept an open command.
+	openTimeout = 10 * time.Second
+
+	// defaultTransitionWindow is the time range used by
+	// ListUpcomingTransitions if no end time is requested.
+	defaultTransitionWindow = 7 * 24 * time.Hour
+
+	// maxTransitionWindow is the maximum time range supported by
+	// ListUpcomingTransitions.
+	maxTransitionWindow = 31 * 24 * time.Hour
+)
+
+// Service implements doorv1connect.DoorServiceHandler.
+type Service struct {
+	doorv1connect.UnimplementedDoorServiceHandler
+
+	doors *door.Manager
+}
+
+// New returns a new door service for all doors managed by doors.
+func New(doors *door.Manager) *Service {
+	return &Service{
+		doors: doors,
+	}
+}
+
+// getDoor returns the door controller for name and makes sure the user
+// is permitted to perform action on it.
+func (svc *Service) getDoor(ctx context.Context, name string, action door.Action) (*door.Controller, error) {
+	if session.UserFromCtx(ctx) == nil {
+		return nil, connect.NewError(connect.CodeUnauthenticated, errUnauthenticated)
+	}
+
+	dc, err := svc.doors.Get(name)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	if err := svc.doors.Authorize(ctx, dc.ID(), action); err != nil {
+		return nil, toConnectError(err)
+	}
+
+	return dc, nil
+}
+
+func (svc *Service) GetState(ctx context.Context, req *connect.Request[doorv1.GetStateRequest]) (*connect.Response[doorv1.GetStateResponse], error) {
+	dc, err := svc.getDoor(ctx, req.Msg.Door, door.ActionView)
+	if err != nil {
+		return nil, err
+	}
+
+	return connect.NewResponse(&doorv1.GetStateResponse{
+		Status: statusToProto(ctx, dc),
+	}), nil
+}
+
+func (svc *Service) ListUpcomingTransitions(ctx context.Context, req *connect.Request[doorv1.ListUpcomingTransitionsRequest]) (*connect.Response[doorv1.ListUpcomingTransitionsResponse], error) {
+	dc, err := svc.getDoor(ctx, req.Msg.Door, door.ActionView)
+	if err != nil {
+		return nil, err
+	}
+
+	from := dc.Now()
+	if req.Msg.From != nil {
+		from = req.Msg.From.AsTime()
+	}
+
+	to := from.Add(defaultTransitionWindow)
+	if req.Msg.To != nil {
+		to = req.Msg.To.AsTime()
+	}
+
+	if !to.After(from) {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("to must be after from"))
+	}
+
+	if to.Sub(from) > maxTransitionWindow {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("time range must not exceed 31 days"))
+	}
+
+	transitions := dc.Simulate(ctx, from, to)
+
+	res := &doorv1.ListUpcomingTransitionsResponse{
+		Transitions: make([]*doorv1.Transition, len(transitions)),
+	}
+	for idx, transition := range transitions {
+		res.Transitions[idx] = transitionToProto(transition)
+	}
+
+	return connect.NewResponse(res), nil
+}
+
+func (svc *Service) Overwrite(ctx context.Context, req *connect.Request[doorv1.OverwriteRequest]) (*connect.Response[doorv1.OverwriteResponse], error) {
+	dc, err := svc.getDoor(ctx, req.Msg.Door, door.ActionOverwrite)
+	if err != nil {
+		return nil, err
+	}
+
+	var state door.State
+	switch req.Msg.State {
+	case doorv1.DoorState_DOOR_STATE_LOCKED:
+		state = door.Locked
+	case doorv1.DoorState_DOOR_STATE_UNLOCKED:
+		state = door.Unlocked
+	default:
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("invalid overwrite state: %s", req.Msg.State))
+	}
+
+	from := dc.Now()
+	if req.Msg.From != nil {
+		from = req.Msg.From.AsTime()
+	}
+
+	var until time.Time
+	switch end := req.Msg.End.(type) {
+	case *doorv1.OverwriteRequest_Duration:
+		d := end.Duration.AsDuration()
+		if d <= 0 {
+			return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("invalid duration"))
+		}
+
+		until = from.Add(d)
+
+	case *doorv1.OverwriteRequest_Until:
+		until = end.Until.AsTime()
+
+	case *doorv1.OverwriteRequest_Mode:
+		mode, ok := overwriteModes[end.Mode]
+		if !ok {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("invalid overwrite mode: %s", end.Mode))
+		}
+
+		until, err = dc.ResolveOverwriteUntil(ctx, mode, from)
+		if err != nil {
+			return nil, toConnectError(err)
+		}
+
+	default:
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("one of duration, until or mode must be set"))
+	}
+
+	if !until.After(from) || !until.After(dc.Now()) {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("overwrite must end after it starts and in the future"))
+	}
+
+	overwrite, err := dc.ScheduleOverwrite(ctx, state, from, until, int(req.Msg.Priority))
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	return connect.NewResponse(&doorv1.OverwriteResponse{
+		Overwrite: overwriteToProto(overwrite),
+		Status:    statusToProto(ctx, dc),
+	}), nil
+}
+
+func (svc *Service) Open(ctx context.Context, req *connect.Request[doorv1.OpenRequest]) (*connect.Response[doorv1.OpenResponse], error) {
+	dc, err := svc.getDoor(ctx, req.Msg.Door, door.ActionOpen)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := dc.OpenDuration(req.Msg.Duration.AsDuration())
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	openCtx, cancel := context.WithTimeout(ctx, openTimeout)
+	defer cancel()
+
+	if err := dc.Open(openCtx, d); err != nil {
+		return nil, toConnectError(err)
+	}
+
+	return connect.NewResponse(&doorv1.OpenResponse{
+		Duration: durationpb.New(d),
+		Until:    timeToProto(dc.Now().Add(d)),
+	}), nil
+}
+
+func (svc *Service) Reset(ctx context.Context, req *connect.Request[doorv1.ResetRequest]) (*connect.Response[doorv1.ResetResponse], error) {
+	dc, err := svc.getDoor(ctx, req.Msg.Door, door.ActionReset)
+	if err != nil {
+		return nil, err
+	}
+
+	steps, err := dc.Reset(ctx)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	res := &doorv1.ResetResponse{
+		Steps:  make([]*doorv1.ResetStep, len(steps)),
+		Status: statusToProto(ctx, dc),
+	}
+	for idx, step := range steps {
+		res.Steps[idx] = resetStepToProto(step)
+	}
+
+	return connect.NewResponse(res), nil
+}
+
+func (svc *Service) WatchState(ctx context.Context, req *connect.Request[doorv1.WatchStateRequest], stream *connect.ServerStream[doorv1.WatchStateResponse]) error {
+	dc, err := svc.getDoor(ctx, req.Msg.Door, door.ActionView)
+	if err != nil {
+		return err
+	}
+
+	events, unsubscribe := dc.Subscribe()
+	defer unsubscribe()
+
+	if err := stream.Send(&doorv1.WatchStateResponse{
+		Status: statusToProto(ctx, dc),
+	}); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case evt, ok := <-events:
+			if !ok {
+				return nil
+			}
+
+			if err := stream.Send(&doorv1.WatchStateResponse{
+				Event:  string(evt.Type),
+				Status: statusToProto(ctx, dc),
+			}); err != nil {
+				log.From(ctx).V(6).Logf("failed to send door status: %s", err)
+
+				return err
+			}
+		}
+	}
+}
+
+// toConnectError converts well-known door errors into connect errors
+// with a matching code.
+func toConnectError(err error) error {
+	switch {
+	case errors.Is(err, door.ErrUnknownDoor):
+		return connect.NewError(connect.CodeNotFound, err)
+
+	case errors.Is(err, door.ErrPermissionDenied):
+		return connect.NewError(connect.CodePermissionDenied, err)
+
+	case errors.Is(err, door.ErrLockdownActive),
+		errors.Is(err, door.ErrNoScheduledChange):
+		return connect.NewError(connect.CodeFailedPrecondition, err)
+
+	case errors.Is(err, door.ErrInvalidOpenDuration),
+		errors.Is(err, door.ErrInvalidOverwriteMode):
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	return err
+}
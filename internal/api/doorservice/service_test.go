@@ -0,0 +1,235 @@
+package doorservice
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/labstack/echo/v4"
+	"github.com/ppacher/system-conf/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	idmv1 "github.com/tierklinik-dobersberg/apis/gen/go/tkd/idm/v1"
+	doorv1 "github.com/tierklinik-dobersberg/cis/gen/go/tkd/door/v1"
+	"github.com/tierklinik-dobersberg/cis/gen/go/tkd/door/v1/doorv1connect"
+	"github.com/tierklinik-dobersberg/cis/internal/cfgspec"
+	"github.com/tierklinik-dobersberg/cis/internal/door"
+	"github.com/tierklinik-dobersberg/cis/internal/holidays"
+	"github.com/tierklinik-dobersberg/cis/internal/openinghours"
+	"github.com/tierklinik-dobersberg/cis/pkg/clock"
+	"github.com/tierklinik-dobersberg/cis/runtime"
+	"github.com/tierklinik-dobersberg/cis/runtime/configprovider/memoryprovider"
+	"github.com/tierklinik-dobersberg/cis/runtime/session"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+type noHolidays struct{}
+
+func (noHolidays) ForYear(context.Context, int) ([]holidays.Holiday, error) {
+	return nil, nil
+}
+
+func (noHolidays) IsHoliday(context.Context, time.Time) (bool, error) {
+	return false, nil
+}
+
+// users are the users known to the test server.
+var users = map[string]*idmv1.Profile{
+	"operator": {
+		User:  &idmv1.User{Id: "operator"},
+		Roles: []*idmv1.Role{{Id: "1", Name: "operator"}},
+	},
+	"admin": {
+		User:  &idmv1.User{Id: "admin"},
+		Roles: []*idmv1.Role{{Id: "2", Name: "admin"}},
+	},
+}
+
+type testServer struct {
+	client doorv1connect.DoorServiceClient
+
+	// watchDone receives a value whenever a WatchState call returns.
+	watchDone chan struct{}
+}
+
+// newTestServer serves the door service for a single disabled door the
+// same way cisd does. Operators may only overwrite the door while admins
+// may overwrite and open it.
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+
+	ctx := context.Background()
+
+	ohCtrl, err := openinghours.NewController(cfgspec.Config{
+		TimeZone: "Europe/Vienna",
+	}, noHolidays{}, clock.System)
+	require.NoError(t, err)
+
+	cs := new(runtime.ConfigSchema)
+	cs.SetProvider(memoryprovider.New(
+		conf.Section{
+			Name: "DoorPermission",
+			Options: conf.Options{
+				{Name: "Roles", Value: "operator"},
+				{Name: "Actions", Value: "view"},
+				{Name: "Actions", Value: "overwrite"},
+			},
+		},
+		conf.Section{
+			Name: "DoorPermission",
+			Options: conf.Options{
+				{Name: "Roles", Value: "admin"},
+				{Name: "Actions", Value: "view"},
+				{Name: "Actions", Value: "overwrite"},
+				{Name: "Actions", Value: "open"},
+			},
+		},
+		conf.Section{
+			Name: "Door",
+			Options: conf.Options{
+				{Name: "Name", Value: door.DefaultDoorName},
+				{Name: "Type", Value: door.DisabledType},
+			},
+		},
+	))
+
+	mng, err := door.NewManager(ctx, ohCtrl, cs, nil, nil, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, mng.Start())
+	t.Cleanup(func() {
+		_ = mng.Stop()
+	})
+
+	ts := &testServer{
+		watchDone: make(chan struct{}, 10),
+	}
+
+	path, handler := NewHandler(mng)
+
+	watchHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, r)
+
+		if strings.HasSuffix(r.URL.Path, "/WatchState") {
+			ts.watchDone <- struct{}{}
+		}
+	})
+
+	userProvider := session.UserProviderFunc(func(_ context.Context, id string) (*idmv1.Profile, error) {
+		user, ok := users[id]
+		if !ok {
+			return nil, echo.ErrUnauthorized
+		}
+
+		return user, nil
+	})
+
+	e := echo.New()
+	apis := e.Group("/api/", session.Middleware(userProvider))
+	apis.Any(strings.TrimPrefix(path, "/")+"*", echo.WrapHandler(http.StripPrefix("/api", watchHandler)))
+
+	srv := httptest.NewServer(e)
+	t.Cleanup(srv.Close)
+
+	ts.client = doorv1connect.NewDoorServiceClient(srv.Client(), srv.URL+"/api")
+
+	return ts
+}
+
+// request returns a new connect request for msg on behalf of user.
+func request[T any](user string, msg *T) *connect.Request[T] {
+	req := connect.NewRequest(msg)
+	if user != "" {
+		req.Header().Set("X-Remote-User-ID", user)
+	}
+
+	return req
+}
+
+func TestServiceRequiresAuthentication(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestServer(t)
+	ctx := context.Background()
+
+	// requests without a user are rejected before reaching the service.
+	_, err := ts.client.GetState(ctx, request("", &doorv1.GetStateRequest{}))
+	assert.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+
+	_, err = ts.client.Open(ctx, request("", &doorv1.OpenRequest{}))
+	assert.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+
+	_, err = ts.client.Overwrite(ctx, request("", &doorv1.OverwriteRequest{}))
+	assert.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+
+	stream, err := ts.client.WatchState(ctx, request("", &doorv1.WatchStateRequest{}))
+	require.NoError(t, err)
+	assert.False(t, stream.Receive())
+	assert.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(stream.Err()))
+	_ = stream.Close()
+
+	res, err := ts.client.GetState(ctx, request("operator", &doorv1.GetStateRequest{}))
+	require.NoError(t, err)
+	assert.Equal(t, door.DefaultDoorName, res.Msg.Status.Door)
+
+	// the service itself rejects calls without a user as well.
+	_, err = New(nil).GetState(ctx, connect.NewRequest(&doorv1.GetStateRequest{}))
+	assert.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+}
+
+func TestServiceAuthorizesActions(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestServer(t)
+	ctx := context.Background()
+
+	overwrite := func() *doorv1.OverwriteRequest {
+		return &doorv1.OverwriteRequest{
+			State: doorv1.DoorState_DOOR_STATE_LOCKED,
+			End: &doorv1.OverwriteRequest_Duration{
+				Duration: durationpb.New(time.Hour),
+			},
+		}
+	}
+
+	_, err := ts.client.Overwrite(ctx, request("operator", overwrite()))
+	require.NoError(t, err)
+
+	_, err = ts.client.Open(ctx, request("operator", &doorv1.OpenRequest{}))
+	assert.Equal(t, connect.CodePermissionDenied, connect.CodeOf(err))
+
+	_, err = ts.client.Open(ctx, request("admin", &doorv1.OpenRequest{}))
+	require.NoError(t, err)
+
+	_, err = ts.client.Overwrite(ctx, request("admin", overwrite()))
+	require.NoError(t, err)
+}
+
+func TestServiceWatchStateStopsOnDisconnect(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestServer(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := ts.client.WatchState(ctx, request("operator", &doorv1.WatchStateRequest{}))
+	require.NoError(t, err)
+
+	// the current status is sent immediately.
+	require.True(t, stream.Receive(), stream.Err())
+	assert.Equal(t, door.DefaultDoorName, stream.Msg().Status.Door)
+
+	cancel()
+	_ = stream.Close()
+
+	select {
+	case <-ts.watchDone:
+	case <-time.After(5 * time.Second):
+		t.Fatal("WatchState did not return after the client disconnected")
+	}
+}
@@ -6,13 +6,9 @@ import (
 	"net/http"
 	"time"
 
-	"github.com/bufbuild/connect-go"
 	"github.com/labstack/echo/v4"
-	calendarv1 "github.com/tierklinik-dobersberg/apis/gen/go/tkd/calendar/v1"
-	commonv1 "github.com/tierklinik-dobersberg/apis/gen/go/tkd/common/v1"
-	"github.com/tierklinik-dobersberg/apis/pkg/discovery/consuldiscover"
-	"github.com/tierklinik-dobersberg/apis/pkg/discovery/wellknown"
 	"github.com/tierklinik-dobersberg/cis/internal/app"
+	"github.com/tierklinik-dobersberg/cis/internal/openinghours"
 	"github.com/tierklinik-dobersberg/cis/pkg/daytime"
 	"github.com/tierklinik-dobersberg/cis/pkg/httperr"
 	"github.com/tierklinik-dobersberg/logger"
@@ -26,9 +22,15 @@ type TimeRange struct {
 	daytime.TimeRange
 }
 
+// GetOpeningHoursResponse is returned by GetOpeningHoursEndpoint for a
+// single day. If the clinic is closed, Frames is empty and Closure holds
+// the closure. The entry door only stays locked during the closure if
+// Closure.KeepDoorLocked is set.
 type GetOpeningHoursResponse struct {
-	Frames    []TimeRange `json:"openingHours"`
-	IsHoliday bool        `json:"holiday"`
+	Frames    []TimeRange           `json:"openingHours"`
+	IsHoliday bool                  `json:"holiday"`
+	IsClosed  bool                  `json:"closed"`
+	Closure   *openinghours.Closure `json:"closure,omitempty"`
 }
 
 func GetOpeningHoursEndpoint(router *app.Router) {
@@ -50,7 +52,7 @@ func GetOpeningHoursEndpoint(router *app.Router) {
 			}
 
 			at := c.QueryParam("at")
-			res, err := getSingleDayOpeningHours(ctx, app, at, time.Now())
+			res, err := getSingleDayOpeningHours(ctx, app, at, app.OpeningHours.Now())
 			if err != nil {
 				return err
 			}
@@ -69,10 +71,10 @@ func getSingleDayOpeningHours(ctx context.Context, app *app.App, at string, date
 		}
 	}
 
-	frames := app.Door.ForDate(ctx, date)
-	holiday, err := isHoliday(ctx, date)
+	frames := app.OpeningHours.ForDate(ctx, date)
+	holiday, err := app.OpeningHours.Holidays().IsHoliday(ctx, date)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to check for public holiday: %w", err)
 	}
 
 	timeRanges := make([]TimeRange, len(frames))
@@ -82,37 +84,16 @@ func getSingleDayOpeningHours(ctx context.Context, app *app.App, at string, date
 		}
 	}
 
+	closure := app.OpeningHours.ClosureFor(ctx, date)
+
 	return &GetOpeningHoursResponse{
 		Frames:    timeRanges,
 		IsHoliday: holiday,
+		IsClosed:  closure != nil,
+		Closure:   closure,
 	}, nil
 }
 
-func isHoliday(ctx context.Context, date time.Time) (bool, error) {
-	disc, err := consuldiscover.NewFromEnv()
-	if err != nil {
-		return false, fmt.Errorf("failed to get consul service catalog: %w", err)
-	}
-
-	cli, err := wellknown.HolidayService.Create(ctx, disc)
-	if err != nil {
-		return false, fmt.Errorf("failed to get holiday service client: %w", err)
-	}
-
-	res, err := cli.IsHoliday(ctx, connect.NewRequest(&calendarv1.IsHolidayRequest{
-		Date: &commonv1.Date{
-			Year:  int64(date.Year()),
-			Month: commonv1.Month(date.Month()),
-			Day:   int32(date.Day()),
-		},
-	}))
-	if err != nil {
-		return false, fmt.Errorf("failed to query holiday service: %w", err)
-	}
-
-	return res.Msg.IsHoliday, nil
-}
-
 func getOpeningHoursRangeResponse(ctx context.Context, app *app.App, from, to string) (*GetOpeningHoursRangeResponse, error) {
 	fromTime, err := app.ParseTime("2006-1-2", from)
 	if err != nil {
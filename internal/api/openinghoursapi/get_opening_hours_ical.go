@@ -0,0 +1,176 @@
+package openinghoursapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tierklinik-dobersberg/cis/internal/app"
+	"github.com/tierklinik-dobersberg/cis/internal/holidays"
+	"github.com/tierklinik-dobersberg/cis/pkg/httperr"
+	"github.com/tierklinik-dobersberg/cis/pkg/ical"
+	"github.com/tierklinik-dobersberg/logger"
+)
+
+const (
+	// defaultICalRange is the number of days exported if to= is not set.
+	defaultICalRange = 90
+
+	// maxICalRange is the maximum number of days that can be exported
+	// at once.
+	maxICalRange = 366
+
+	// icalUIDDomain is appended to the UID of all exported events.
+	icalUIDDomain = "openinghours.cisd"
+)
+
+// GetOpeningHoursICalEndpoint exports the opening hours between from= and
+// to= (exclusive) as an iCalendar file. from defaults to the current day
+// and to defaults to 90 days after from. Public holidays and closures are
+// exported as all-day events. UIDs are derived from the opening hour
+// definitions so calendar clients update events instead of duplicating
+// them. The feed does not require authentication, see SetupPublic.
+func GetOpeningHoursICalEndpoint(router *app.Router) {
+	router.GET(
+		"v1/opening-hours.ics",
+		func(ctx context.Context, app *app.App, c echo.Context) error {
+			from, to, err := parseICalRange(app, c.QueryParam("from"), c.QueryParam("to"))
+			if err != nil {
+				return err
+			}
+
+			cal := getOpeningHoursCalendar(ctx, app, from, to)
+
+			c.Response().Header().Set(echo.HeaderContentType, "text/calendar; charset=utf-8")
+			c.Response().Header().Set(echo.HeaderContentDisposition, `inline; filename="opening-hours.ics"`)
+			c.Response().WriteHeader(http.StatusOK)
+
+			_, err = cal.WriteTo(c.Response())
+
+			return err
+		},
+	)
+}
+
+func parseICalRange(app *app.App, from, to string) (time.Time, time.Time, error) {
+	now := app.OpeningHours.Now()
+	fromTime := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, app.Location())
+
+	if from != "" {
+		var err error
+		fromTime, err = app.ParseTime("2006-1-2", from)
+		if err != nil {
+			return time.Time{}, time.Time{}, httperr.InvalidParameter("from", err.Error())
+		}
+	}
+
+	toTime := fromTime.AddDate(0, 0, defaultICalRange)
+	if to != "" {
+		var err error
+		toTime, err = app.ParseTime("2006-1-2", to)
+		if err != nil {
+			return time.Time{}, time.Time{}, httperr.InvalidParameter("to", err.Error())
+		}
+	}
+
+	if !toTime.After(fromTime) {
+		return time.Time{}, time.Time{}, httperr.BadRequest("invalid from/to values")
+	}
+
+	if toTime.After(fromTime.AddDate(0, 0, maxICalRange)) {
+		return time.Time{}, time.Time{}, httperr.BadRequest(fmt.Sprintf("time range must not exceed %d days", maxICalRange))
+	}
+
+	return fromTime, toTime, nil
+}
+
+func getOpeningHoursCalendar(ctx context.Context, app *app.App, from, to time.Time) *ical.Calendar {
+	holidayNames := loadHolidayNames(ctx, app, from, to)
+
+	cal := &ical.Calendar{
+		ProdID:    "-//tierklinik-dobersberg//cisd//EN",
+		Name:      "Opening hours",
+		Timestamp: app.OpeningHours.Now(),
+	}
+
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		dayKey := day.Format("20060102")
+		nextDay := day.AddDate(0, 0, 1)
+
+		if name, ok := holidayNames[day.Format(holidays.DateFormat)]; ok {
+			cal.Events = append(cal.Events, ical.Event{
+				UID:         fmt.Sprintf("holiday-%s@%s", dayKey, icalUIDDomain),
+				Summary:     fmt.Sprintf("Public holiday: %s", name),
+				Start:       day,
+				End:         nextDay,
+				AllDay:      true,
+				Categories:  []string{"holiday"},
+				Transparent: true,
+			})
+		}
+
+		if closure := app.OpeningHours.ClosureFor(ctx, day); closure != nil {
+			summary := "Closed"
+			if closure.Reason != "" {
+				summary = fmt.Sprintf("Closed: %s", closure.Reason)
+			}
+
+			cal.Events = append(cal.Events, ical.Event{
+				UID:         fmt.Sprintf("closure-%s-%s@%s", closure.ID, dayKey, icalUIDDomain),
+				Summary:     summary,
+				Description: closure.Reason,
+				Start:       day,
+				End:         nextDay,
+				AllDay:      true,
+				Categories:  []string{"closure"},
+				Transparent: true,
+			})
+		}
+
+		frames, source := app.OpeningHours.ForDateWithSource(ctx, day, nil)
+
+		// a single definition may contain multiple time ranges so we
+		// number them per definition to get stable UIDs.
+		counts := make(map[string]int)
+		for _, frame := range frames {
+			idx := counts[frame.ID]
+			counts[frame.ID]++
+
+			tr := frame.At(day, app.Location())
+
+			cal.Events = append(cal.Events, ical.Event{
+				UID:        fmt.Sprintf("openinghour-%s-%s-%d@%s", frame.ID, dayKey, idx, icalUIDDomain),
+				Summary:    "Open",
+				Start:      tr.From,
+				End:        tr.To,
+				Categories: []string{string(source)},
+			})
+		}
+	}
+
+	return cal
+}
+
+// loadHolidayNames returns the names of all public holidays between from
+// and to indexed by date. Years for which public holidays cannot be loaded
+// are skipped so the feed stays available.
+func loadHolidayNames(ctx context.Context, app *app.App, from, to time.Time) map[string]string {
+	names := make(map[string]string)
+
+	for year := from.Year(); year <= to.Year(); year++ {
+		res, err := app.OpeningHours.Holidays().ForYear(ctx, year)
+		if err != nil {
+			logger.From(ctx).Errorf("failed to load public holidays for %d, omitting holiday events: %s", year, err)
+
+			continue
+		}
+
+		for _, h := range res {
+			names[h.Date] = h.Name
+		}
+	}
+
+	return names
+}
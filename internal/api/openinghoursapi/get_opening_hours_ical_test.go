@@ -0,0 +1,221 @@
+package openinghoursapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/ppacher/system-conf/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tierklinik-dobersberg/cis/internal/app"
+	"github.com/tierklinik-dobersberg/cis/internal/cfgspec"
+	"github.com/tierklinik-dobersberg/cis/internal/holidays"
+	"github.com/tierklinik-dobersberg/cis/internal/openinghours"
+	"github.com/tierklinik-dobersberg/cis/pkg/clock"
+	"github.com/tierklinik-dobersberg/cis/runtime/session"
+)
+
+// easterMonday is the only public holiday known to the tests.
+type easterMonday struct {
+	err error
+}
+
+func (em easterMonday) ForYear(_ context.Context, year int) ([]holidays.Holiday, error) {
+	if em.err != nil {
+		return nil, em.err
+	}
+
+	if year != 2026 {
+		return nil, nil
+	}
+
+	return []holidays.Holiday{{Date: "2026-04-06", Name: "Ostermontag"}}, nil
+}
+
+func (em easterMonday) IsHoliday(ctx context.Context, date time.Time) (bool, error) {
+	res, err := em.ForYear(ctx, date.Year())
+	if err != nil {
+		return false, err
+	}
+
+	return len(res) > 0 && res[0].Date == date.Format(holidays.DateFormat), nil
+}
+
+// newICalTestServer serves the opening hours API with regular opening
+// hours on weekdays and a closure on 2026-04-08. The clock is set to
+// Wednesday, 2026-04-01 10:00 in Europe/Vienna.
+func newICalTestServer(t *testing.T, provider holidays.Provider) *echo.Echo {
+	t.Helper()
+
+	ctx := context.Background()
+
+	loc, err := time.LoadLocation("Europe/Vienna")
+	require.NoError(t, err)
+
+	cfg := cfgspec.Config{
+		TimeZone: "Europe/Vienna",
+	}
+
+	ohCtrl, err := openinghours.NewController(cfg, provider, clock.NewFake(time.Date(2026, time.April, 1, 10, 0, 0, 0, loc)))
+	require.NoError(t, err)
+
+	require.NoError(t, ohCtrl.NotifyChange(ctx, "create", "weekdays", &conf.Section{
+		Name: "OpeningHour",
+		Options: conf.Options{
+			{Name: "OnWeekday", Value: "Mon"},
+			{Name: "OnWeekday", Value: "Tue"},
+			{Name: "OnWeekday", Value: "Wed"},
+			{Name: "OnWeekday", Value: "Thu"},
+			{Name: "OnWeekday", Value: "Fri"},
+			{Name: "TimeRanges", Value: "08:00 - 12:00"},
+			{Name: "TimeRanges", Value: "14:00 - 18:00"},
+			{Name: "Holiday", Value: "no"},
+		},
+	}))
+
+	require.NoError(t, ohCtrl.AddClosures(ctx, openinghours.Closure{
+		ID:     "team-event",
+		Dates:  []string{"2026-04-08"},
+		Reason: "Team event",
+	}))
+
+	a := &app.App{
+		Config:       &app.Config{Config: cfg},
+		OpeningHours: ohCtrl,
+	}
+
+	// the API is mounted like in cisd with the authenticated group
+	// rejecting all requests without a user.
+	e := echo.New()
+	Setup(a, e.Group("/api/openinghours/", requireUser))
+	SetupPublic(a, e.Group("/api/openinghours/"))
+
+	return e
+}
+
+func requireUser(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if session.UserFromCtx(c.Request().Context()) == nil {
+			return echo.ErrUnauthorized
+		}
+
+		return next(c)
+	}
+}
+
+func getICal(e *echo.Echo, query string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/openinghours/v1/opening-hours.ics"+query, nil)
+	rec := httptest.NewRecorder()
+
+	e.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestGetOpeningHoursICal(t *testing.T) {
+	t.Parallel()
+
+	e := newICalTestServer(t, easterMonday{})
+
+	rec := getICal(e, "?from=2026-04-06&to=2026-04-09")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "text/calendar; charset=utf-8", rec.Header().Get(echo.HeaderContentType))
+
+	body := rec.Body.String()
+
+	// DTSTAMP is taken from the clock of the opening hour controller.
+	assert.Contains(t, body, "DTSTAMP:20260401T080000Z\r\n")
+
+	// public holidays are exported as all-day events and there are no
+	// opening hours on Easter Monday.
+	assert.Contains(t, body, "UID:holiday-20260406@openinghours.cisd\r\n")
+	assert.Contains(t, body, "SUMMARY:Public holiday: Ostermontag\r\n")
+	assert.NotContains(t, body, "UID:openinghour-weekdays-20260406")
+
+	// regular days contain one event per time range.
+	assert.Contains(t, body, "UID:openinghour-weekdays-20260407-0@openinghours.cisd\r\n")
+	assert.Contains(t, body, "UID:openinghour-weekdays-20260407-1@openinghours.cisd\r\n")
+	assert.Contains(t, body, "DTSTART:20260407T060000Z\r\n")
+	assert.Contains(t, body, "DTEND:20260407T160000Z\r\n")
+
+	// closures are exported as all-day events without opening hours.
+	assert.Contains(t, body, "UID:closure-team-event-20260408@openinghours.cisd\r\n")
+	assert.Contains(t, body, "SUMMARY:Closed: Team event\r\n")
+	assert.NotContains(t, body, "UID:openinghour-weekdays-20260408")
+
+	// to is exclusive.
+	assert.NotContains(t, body, "UID:openinghour-weekdays-20260409")
+
+	// UIDs do not change between requests so calendar clients update
+	// the events instead of duplicating them.
+	assert.Equal(t, body, getICal(e, "?from=2026-04-06&to=2026-04-09").Body.String())
+
+	rec = getICal(e, "?from=2026-04-07&to=2026-04-08")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), "UID:openinghour-weekdays-20260407-0@openinghours.cisd\r\n")
+
+	// from defaults to the current day and to to 90 days later.
+	rec = getICal(e, "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), "UID:openinghour-weekdays-20260401-0@openinghours.cisd\r\n")
+	assert.Contains(t, rec.Body.String(), "UID:openinghour-weekdays-20260629-0@openinghours.cisd\r\n")
+	assert.NotContains(t, rec.Body.String(), "UID:openinghour-weekdays-20260630-0")
+}
+
+func TestGetOpeningHoursICalRange(t *testing.T) {
+	t.Parallel()
+
+	e := newICalTestServer(t, easterMonday{})
+
+	for _, query := range []string{
+		"?from=2026-04-09&to=2026-04-06",
+		"?from=2026-04-06&to=2026-04-06",
+		"?from=yesterday",
+		"?from=2026-04-06&to=2026-13-01",
+		"?from=2026-01-01&to=2027-01-03",
+	} {
+		rec := getICal(e, query)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+	}
+
+	rec := getICal(e, "?from=2026-01-01&to=2027-01-02")
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+}
+
+func TestGetOpeningHoursICalWithoutHolidays(t *testing.T) {
+	t.Parallel()
+
+	e := newICalTestServer(t, easterMonday{
+		err: errors.New("holiday service unavailable"),
+	})
+
+	// the feed stays available but does not contain public holidays.
+	rec := getICal(e, "?from=2026-04-06&to=2026-04-09")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	body := rec.Body.String()
+	assert.NotContains(t, body, "UID:holiday-")
+	assert.Contains(t, body, "UID:closure-team-event-20260408@openinghours.cisd\r\n")
+}
+
+func TestGetOpeningHoursICalWithoutUser(t *testing.T) {
+	t.Parallel()
+
+	e := newICalTestServer(t, easterMonday{})
+
+	// calendar applications cannot authenticate so the feed is public ...
+	rec := getICal(e, "?from=2026-04-06&to=2026-04-09")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), "UID:openinghour-weekdays-20260407-0@openinghours.cisd\r\n")
+
+	// ... while the remaining opening hours API is not.
+	req := httptest.NewRequest(http.MethodGet, "/api/openinghours/v1/opening-hours", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code, rec.Body.String())
+}
@@ -10,3 +10,12 @@ func Setup(a *app.App, grp *echo.Group) {
 
 	GetOpeningHoursEndpoint(router)
 }
+
+// SetupPublic registers all endpoints that must be available without
+// authentication, like the iCalendar feed that calendar applications
+// and the public website subscribe to.
+func SetupPublic(a *app.App, grp *echo.Group) {
+	router := app.NewRouter(grp, a)
+
+	GetOpeningHoursICalEndpoint(router)
+}
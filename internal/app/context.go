@@ -12,6 +12,7 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/tierklinik-dobersberg/cis/internal/door"
 	"github.com/tierklinik-dobersberg/cis/internal/idm"
+	"github.com/tierklinik-dobersberg/cis/internal/openinghours"
 	"github.com/tierklinik-dobersberg/cis/pkg/httperr"
 	"github.com/tierklinik-dobersberg/logger"
 )
@@ -22,9 +23,10 @@ const appContextKey = contextKey("app:context")
 
 // App holds dependencies for cis API request handlers.
 type App struct {
-	Config *Config
-	IDM    *idm.Provider
-	Door   *door.Controller
+	Config       *Config
+	IDM          *idm.Provider
+	Doors        *door.Manager
+	OpeningHours *openinghours.Controller
 
 	RosterdServer string
 }
@@ -36,13 +38,15 @@ func (app *App) String() string {
 // NewApp context creates a new application context.
 func NewApp(
 	cfg *Config,
-	door *door.Controller,
+	doors *door.Manager,
+	openingHours *openinghours.Controller,
 	RosterdServer string,
 	idmProvider *idm.Provider,
 ) *App {
 	return &App{
 		Config:        cfg,
-		Door:          door,
+		Doors:         doors,
+		OpeningHours:  openingHours,
 		RosterdServer: RosterdServer,
 		IDM:           idmProvider,
 	}
@@ -18,6 +18,13 @@ type Config struct {
 	TimeZone string
 	LogLevel string
 
+	// HolidayProvider selects the provider for public holidays. Either
+	// "service" or "builtin".
+	HolidayProvider string
+	// HolidayFallback enables the built-in holiday provider as a fallback
+	// if the holiday service is unavailable.
+	HolidayFallback bool
+
 	// Service is the name of the service. It's used when reporting
 	// metrics and traces.
 	Service string
@@ -64,6 +71,18 @@ var ConfigSpec = conf.SectionSpec{
 		Default:     "AT",
 		Type:        conf.StringType,
 	},
+	{
+		Name:        "HolidayProvider",
+		Description: "The provider for public holidays. Either 'service' to query the holiday service or 'builtin' to calculate nationwide public holidays of Country= in-process (AT and DE only)",
+		Default:     "service",
+		Type:        conf.StringType,
+	},
+	{
+		Name:        "HolidayFallback",
+		Description: "Whether or not the built-in holiday calculation should be used if the holiday service is unavailable",
+		Default:     "yes",
+		Type:        conf.BoolType,
+	},
 	{
 		Name:        "DefaultOpenBefore",
 		Type:        conf.DurationType,
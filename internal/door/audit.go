@@ -0,0 +1,171 @@
+package door
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AuditCollection is the name of the mongo-db collection used
+// to store door audit records.
+const AuditCollection = "cis:door:audit"
+
+// AuditAction describes the action of an audit record.
+type AuditAction string
+
+// Possible audit actions.
+const (
+	AuditSchedule  = AuditAction("schedule")
+	AuditOverwrite = AuditAction("overwrite")
+	AuditCancel    = AuditAction("cancel-overwrite")
+	AuditOpen      = AuditAction("open")
+	AuditReset     = AuditAction("reset")
+
+	AuditLockdown        = AuditAction("lockdown")
+	AuditReleaseLockdown = AuditAction("release-lockdown")
+)
+
+// AuditRecord describes a single action performed on the door.
+type AuditRecord struct {
+	// ID is the unique ID of the audit record.
+	ID primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	// Door is the ID of the door the action has been performed on.
+	Door string `json:"door" bson:"door"`
+	// Action is the action that has been performed.
+	Action AuditAction `json:"action" bson:"action"`
+	// Actor is the ID of the user that triggered the action. It's empty
+	// for actions performed by the door scheduler.
+	Actor string `json:"actor,omitempty" bson:"actor,omitempty"`
+	// Time is the time the action has been performed.
+	Time time.Time `json:"time" bson:"time"`
+	// DesiredState is the door state requested by the action.
+	DesiredState State `json:"desiredState,omitempty" bson:"desiredState,omitempty"`
+	// PreviousState is the door state before the action has been performed.
+	PreviousState State `json:"previousState,omitempty" bson:"previousState,omitempty"`
+	// From and Until are set for overwrites and when opening the door
+	// and hold the time range the overwrite is active or the door is
+	// kept open.
+	From  time.Time `json:"from,omitempty" bson:"from,omitempty"`
+	Until time.Time `json:"until,omitempty" bson:"until,omitempty"`
+	// Error holds the error message if the action failed.
+	Error string `json:"error,omitempty" bson:"error,omitempty"`
+}
+
+// AuditQuery is used to filter audit records.
+type AuditQuery struct {
+	// From and To limit the time range of returned records.
+	// Zero values are ignored.
+	From time.Time
+	To   time.Time
+	// Door limits the returned records to those of a specific door.
+	Door string
+	// Actor limits the returned records to those of a specific user.
+	Actor string
+	// Offset and Limit are used for pagination.
+	Offset int64
+	Limit  int64
+}
+
+// AuditLog stores and queries door audit records.
+type AuditLog interface {
+	// Record stores a new audit record.
+	Record(ctx context.Context, record AuditRecord) error
+
+	// Query returns all audit records matching query, sorted by
+	// time in descending order, together with the total number
+	// of matching records.
+	Query(ctx context.Context, query AuditQuery) ([]AuditRecord, int64, error)
+}
+
+type auditLog struct {
+	col *mongo.Collection
+}
+
+// NewAuditLog returns a new audit log that stores records in db.
+func NewAuditLog(ctx context.Context, db *mongo.Database) (AuditLog, error) {
+	al := &auditLog{
+		col: db.Collection(AuditCollection),
+	}
+
+	if _, err := al.col.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "time", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "door", Value: 1},
+				{Key: "time", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "actor", Value: 1},
+				{Key: "time", Value: -1},
+			},
+		},
+	}); err != nil {
+		return nil, err
+	}
+
+	return al, nil
+}
+
+func (al *auditLog) Record(ctx context.Context, record AuditRecord) error {
+	_, err := al.col.InsertOne(ctx, record)
+
+	return err
+}
+
+func (al *auditLog) Query(ctx context.Context, query AuditQuery) ([]AuditRecord, int64, error) {
+	filter := bson.M{}
+
+	timeFilter := bson.M{}
+	if !query.From.IsZero() {
+		timeFilter["$gte"] = query.From
+	}
+	if !query.To.IsZero() {
+		timeFilter["$lt"] = query.To
+	}
+	if len(timeFilter) > 0 {
+		filter["time"] = timeFilter
+	}
+
+	if query.Door != "" {
+		filter["door"] = query.Door
+	}
+
+	if query.Actor != "" {
+		filter["actor"] = query.Actor
+	}
+
+	total, err := al.col.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "time", Value: -1}}).
+		SetSkip(query.Offset)
+
+	if query.Limit > 0 {
+		opts.SetLimit(query.Limit)
+	}
+
+	cursor, err := al.col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var records []AuditRecord
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, 0, err
+	}
+
+	return records, total, nil
+}
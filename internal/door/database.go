@@ -0,0 +1,153 @@
+package door
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// OverwriteCollection is the name of the mongo-db collection used
+// to persist manual door overwrites.
+const OverwriteCollection = "cis:door:overwrites"
+
+// Overwrite describes a manual overwrite of the door state.
+type Overwrite struct {
+	// ID is the unique ID of the overwrite.
+	ID primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	// Door is the ID of the door the overwrite applies to.
+	Door string `json:"door" bson:"door"`
+	// State is the door state that should be enforced.
+	State State `json:"state" bson:"state"`
+	// From holds the time the overwrite becomes active.
+	From time.Time `json:"from" bson:"from"`
+	// Until holds the time until the overwrite is active.
+	Until time.Time `json:"until" bson:"until"`
+	// Priority is used to select an overwrite if multiple overwrites
+	// are active at the same time. The overwrite with the highest
+	// priority wins. If priorities are equal the most recently
+	// created overwrite is used.
+	Priority int `json:"priority" bson:"priority"`
+	// SessionUser is the ID of the user that created the overwrite.
+	SessionUser string `json:"sessionUser" bson:"sessionUser"`
+	// CreatedAt holds the time the overwrite has been created.
+	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
+}
+
+// Covers returns true if the overwrite is active at t.
+func (ov Overwrite) Covers(t time.Time) bool {
+	return !ov.From.After(t) && ov.Until.After(t)
+}
+
+// outranks returns true if ov takes precedence over other.
+func (ov Overwrite) outranks(other Overwrite) bool {
+	if ov.Priority != other.Priority {
+		return ov.Priority > other.Priority
+	}
+
+	return ov.CreatedAt.After(other.CreatedAt)
+}
+
+// OverwriteDatabase persists manual door overwrites so they
+// survive restarts of cisd.
+type OverwriteDatabase interface {
+	// Save stores a new overwrite. If ov does not have an ID
+	// yet, it is populated by Save.
+	Save(ctx context.Context, ov *Overwrite) error
+
+	// List returns all overwrites stored for door.
+	List(ctx context.Context, door string) ([]Overwrite, error)
+
+	// Delete deletes the overwrite with the given ID. If there
+	// is no such overwrite ErrUnknownOverwrite is returned.
+	Delete(ctx context.Context, door string, id primitive.ObjectID) error
+}
+
+// ErrUnknownOverwrite is returned if an overwrite does not exist.
+var ErrUnknownOverwrite = errors.New("unknown overwrite")
+
+type overwriteDatabase struct {
+	col *mongo.Collection
+}
+
+// NewOverwriteDatabase returns a new overwrite database that stores
+// door overwrites in db.
+func NewOverwriteDatabase(ctx context.Context, db *mongo.Database) (OverwriteDatabase, error) {
+	ovdb := &overwriteDatabase{
+		col: db.Collection(OverwriteCollection),
+	}
+
+	if _, err := ovdb.col.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "door", Value: 1},
+			{Key: "from", Value: 1},
+		},
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create index: %w", err)
+	}
+
+	// overwrites persisted before multiple doors have been supported
+	// do not have a door assigned. They belong to the default door.
+	res, err := ovdb.col.UpdateMany(ctx,
+		bson.M{"door": bson.M{"$in": bson.A{nil, ""}}},
+		bson.M{"$set": bson.M{"door": DefaultDoorName}},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate door overwrites: %w", err)
+	}
+
+	if res.ModifiedCount > 0 {
+		log.From(ctx).Infof("assigned %d door overwrites without a door to %q", res.ModifiedCount, DefaultDoorName)
+	}
+
+	return ovdb, nil
+}
+
+func (db *overwriteDatabase) Save(ctx context.Context, ov *Overwrite) error {
+	res, err := db.col.InsertOne(ctx, ov)
+	if err != nil {
+		return err
+	}
+
+	if id, ok := res.InsertedID.(primitive.ObjectID); ok {
+		ov.ID = id
+	}
+
+	return nil
+}
+
+func (db *overwriteDatabase) List(ctx context.Context, door string) ([]Overwrite, error) {
+	opts := options.Find().SetSort(bson.D{
+		{Key: "from", Value: 1},
+	})
+
+	cursor, err := db.col.Find(ctx, bson.M{"door": door}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Overwrite
+	if err := cursor.All(ctx, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (db *overwriteDatabase) Delete(ctx context.Context, door string, id primitive.ObjectID) error {
+	res, err := db.col.DeleteOne(ctx, bson.M{"_id": id, "door": door})
+	if err != nil {
+		return err
+	}
+
+	if res.DeletedCount == 0 {
+		return ErrUnknownOverwrite
+	}
+
+	return nil
+}
@@ -13,7 +13,6 @@ import (
 	"github.com/tevino/abool"
 	"github.com/tierklinik-dobersberg/cis/internal/openinghours"
 	"github.com/tierklinik-dobersberg/cis/pkg/pkglog"
-	"github.com/tierklinik-dobersberg/cis/runtime"
 	"github.com/tierklinik-dobersberg/cis/runtime/session"
 	"go.opentelemetry.io/otel"
 )
@@ -34,52 +33,167 @@ type Interfacer interface {
 	// Unlock the door.
 	Unlock(context.Context) error
 
-	// Open the door for the next visitor to enter.
-	Open(context.Context) error
+	// Open the door for the next visitor to enter. The door
+	// should be kept open for d.
+	Open(ctx context.Context, d time.Duration) error
 
 	// Release is called to release and shut-down the door
 	// interfacer.
 	Release()
 }
 
+// StateReporter may be implemented by an Interfacer that is able
+// to sense the actual, physical state of the door (e.g. using a
+// reed contact).
+type StateReporter interface {
+	// ReportState returns the state of the door as sensed by
+	// the interfacer. If the state cannot be determined, Unknown
+	// should be returned.
+	ReportState(context.Context) (State, error)
+}
+
+// ErrUnknownDoor is returned when a door is requested that
+// is not configured.
+var ErrUnknownDoor = errors.New("unknown door")
+
+// ErrDoorBusy is returned by door interfacers that cannot accept
+// a new command while a previous one is still in progress. The
+// scheduler does not count it as a failed attempt but retries later.
+var ErrDoorBusy = errors.New("door is busy")
+
+// ErrInvalidOpenDuration is returned when the door should be opened
+// for a negative duration or longer than allowed.
+var ErrInvalidOpenDuration = errors.New("invalid open duration")
+
+// Defaults for opening the door if not configured otherwise.
+const (
+	defaultOpenDuration    = 10 * time.Second
+	defaultMaxOpenDuration = 5 * time.Minute
+)
+
+// actualStateTimeout is the time the scheduler waits for a
+// StateReporter to report the actual door state.
+const actualStateTimeout = 5 * time.Second
+
 // Possible door states.
 const (
 	Locked   = State("locked")
 	Unlocked = State("unlocked")
 )
 
-// Reset types.
-var (
-	resetSoft = (*struct{})(nil)
-	resetHard = &struct{}{}
+// Additional door states that may be reported by a StateReporter.
+const (
+	Open    = State("open")
+	Unknown = State("unknown")
 )
 
-type stateOverwrite struct {
-	state       State
-	until       time.Time
-	sessionUser string
+// resetRequest is sent to the scheduler to request a hard reset
+// of the door.
+type resetRequest struct {
+	// actor is the ID of the user that requested the reset.
+	actor string
+
+	// result receives the outcome of each reset step once the
+	// reset sequence finished. It may be nil.
+	result chan []ResetStepResult
 }
 
-// Controller interacts with the entry door controller via the configured interfacer
+// Reset types.
+var (
+	resetSoft = (*resetRequest)(nil)
+)
+
+// Controller interacts with a door controller via the configured interfacer
 // and locks/unlocks the door depending on the opening hours.
 type Controller struct {
 	*openinghours.Controller
 
-	// overwriteLock protects access to manualOverwrite.
+	// id is the unique ID of the door.
+	id string
+
+	// configLock protects access to displayName, openingHours,
+	// failureWebhook, openDuration, maxOpenDuration, resetSequence,
+	// lockdownReleaseRoles, doorType and section.
+	configLock sync.RWMutex
+
+	// displayName is the human readable name of the door.
+	displayName string
+
+	// openingHours holds the IDs of the opening hour definitions
+	// used for this door. If empty, all opening hours are used.
+	openingHours []string
+
+	// failureWebhook is called when the scheduler gave up applying
+	// the desired door state.
+	failureWebhook string
+
+	// openDuration is the time the door is kept open if no
+	// duration is requested.
+	openDuration time.Duration
+
+	// maxOpenDuration is the maximum time the door may be
+	// kept open.
+	maxOpenDuration time.Duration
+
+	// resetSequence holds the steps performed when resetting
+	// the door.
+	resetSequence []ResetStep
+
+	// lockdownReleaseRoles holds the IDs or names of roles that
+	// are permitted to release a lockdown.
+	lockdownReleaseRoles []string
+
+	// doorType is the configured door Type.
+	doorType string
+
+	// section holds the door configuration the controller has been
+	// configured with.
+	section conf.Section
+
+	// onFailure is called when the scheduler gave up applying the
+	// desired door state. It may be nil.
+	onFailure FailureHook
+
+	// healthLock protects access to health.
+	healthLock sync.Mutex
+
+	// health describes how well the scheduler is able to apply
+	// the desired door state.
+	health Health
+
+	// overwriteLock protects access to manualOverwrites.
 	overwriteLock sync.Mutex
 
-	// manualOverwrite is set when a user has manually overwritten
-	// the current state of the entry door.
-	manualOverwrite *stateOverwrite
+	// manualOverwrites holds all active and scheduled overwrites
+	// of the door state.
+	manualOverwrites []Overwrite
+
+	// overwrites is used to persist manual overwrites. It may
+	// be nil in which case overwrites are only kept in memory.
+	overwrites OverwriteDatabase
+
+	// lockdownLock protects access to lockdown.
+	lockdownLock sync.Mutex
+
+	// lockdown is set while the door is in lockdown.
+	lockdown *Lockdown
+
+	// lockdowns is used to persist the lockdown of the door. It may
+	// be nil in which case the lockdown is only kept in memory.
+	lockdowns LockdownDatabase
+
+	// audit is used to record door actions. It may be nil
+	// in which case no audit records are written.
+	audit AuditLog
 
 	// stop is closed when the scheduler should stop.
 	stop chan struct{}
 
 	// reset triggers a reset of the scheduler.
-	// A nil value means soft-reset while struct{}{}
-	// is interpreted as a hard-reset causing a unlock-lock-unlock
-	// sequence
-	reset chan *struct{}
+	// A nil value means soft-reset while a non-nil resetRequest
+	// is interpreted as a hard-reset causing the reset sequence
+	// to be performed.
+	reset chan *resetRequest
 
 	// Whether or not a door reset is currently in progress.
 	resetInProgress *abool.AtomicBool
@@ -92,66 +206,107 @@ type Controller struct {
 
 	// door is the actual interface to control the door.
 	door Interfacer
+
+	// actualStateLock protects access to actualState and actualStateTime.
+	actualStateLock sync.Mutex
+
+	// actualState holds the last state reported by the door interfacer
+	// if it implements StateReporter.
+	actualState State
+
+	// actualStateTime holds the time the actualState has been reported.
+	actualStateTime time.Time
+
+	// events is used to publish door events to subscribers.
+	events *eventHub
 }
 
-// NewDoorController returns a new door controller.
-func NewDoorController(ctx context.Context, ohCtrl *openinghours.Controller, cs *runtime.ConfigSchema) (*Controller, error) {
+// newController returns a new door controller for the door id. If overwrites is
+// non-nil, manual overwrites are persisted and the active one is restored. The
+// same applies to lockdowns. If audit is non-nil, all door actions are recorded
+// there.
+func newController(ctx context.Context, id string, ohCtrl *openinghours.Controller, overwrites OverwriteDatabase, lockdowns LockdownDatabase, audit AuditLog) (*Controller, error) {
 	dc := &Controller{
 		Controller:      ohCtrl,
+		id:              id,
+		overwrites:      overwrites,
+		lockdowns:       lockdowns,
+		audit:           audit,
 		stop:            make(chan struct{}),
-		reset:           make(chan *struct{}),
+		reset:           make(chan *resetRequest),
 		resetInProgress: abool.NewBool(false),
+		openDuration:    defaultOpenDuration,
+		maxOpenDuration: defaultMaxOpenDuration,
+		resetSequence:   defaultResetSequence,
 		door:            NoOp{},
+		actualState:     Unknown,
+		events:          newEventHub(),
 	}
 
-	cs.AddNotifier(dc, "Door")
-	cs.AddValidator(dc, "Door")
-
-	// initialize now
-	all, err := cs.All(ctx, "Door")
-	if err != nil {
-		return nil, err
+	if err := dc.loadOverwrites(ctx); err != nil {
+		return nil, fmt.Errorf("failed to load door overwrites: %w", err)
 	}
 
-	if len(all) > 0 {
-		if err := dc.NotifyChange(ctx, "create", all[0].ID, &all[0].Section); err != nil {
-			return nil, err
-		}
+	if err := dc.loadLockdown(ctx); err != nil {
+		return nil, fmt.Errorf("failed to load door lockdown: %w", err)
 	}
 
-	// reset the scheduler whenever new opening hours got configured.
-	dc.Controller.OnChange(func() {
-		select {
-		case dc.reset <- resetSoft:
-		default:
-		}
-	})
-
 	return dc, nil
 }
 
-func (dc *Controller) Validate(ctx context.Context, sec runtime.Section) error {
-	var cfg DoorConfig
-	if err := conf.DecodeSections([]conf.Section{sec.Section}, Spec, &cfg); err != nil {
-		return err
+// ID returns the ID of the door controlled by dc.
+func (dc *Controller) ID() string {
+	return dc.id
+}
+
+// DisplayName returns the human readable name of the door.
+func (dc *Controller) DisplayName() string {
+	dc.configLock.RLock()
+	defer dc.configLock.RUnlock()
+
+	if dc.displayName == "" {
+		return dc.id
 	}
 
-	switch cfg.Type {
-	case "shelly-script":
-		if cfg.ShellyScriptURL == "" {
-			return fmt.Errorf("ShellScriptURL must be configured")
-		}
+	return dc.displayName
+}
 
-		return nil
+// OpeningHours returns the IDs of the opening hour definitions that are
+// used for the door. If empty, all opening hours are used.
+func (dc *Controller) OpeningHours() []string {
+	dc.configLock.RLock()
+	defer dc.configLock.RUnlock()
 
-	case "disabled":
-		return nil
+	return dc.openingHours
+}
+
+// configure applies cfg to the door controller and replaces the door
+// interfacer using the driver for cfg.Type. Driver options are decoded
+// from sec.
+func (dc *Controller) configure(cfg DoorConfig, sec conf.Section) error {
+	resetSequence, err := ParseResetSequence(cfg.ResetSequence)
+	if err != nil {
+		return err
 	}
 
-	return fmt.Errorf("unsupport door interface type: %q", cfg.Type)
-}
+	dc.configLock.Lock()
+	dc.displayName = cfg.DisplayName
+	dc.openingHours = cfg.OpeningHours
+	dc.failureWebhook = cfg.FailureWebhookURL
+	dc.openDuration = cfg.OpenDuration
+	if dc.openDuration <= 0 {
+		dc.openDuration = defaultOpenDuration
+	}
+	dc.maxOpenDuration = cfg.MaxOpenDuration
+	if dc.maxOpenDuration <= 0 {
+		dc.maxOpenDuration = defaultMaxOpenDuration
+	}
+	dc.resetSequence = resetSequence
+	dc.lockdownReleaseRoles = cfg.LockdownReleaseRoles
+	dc.doorType = cfg.Type
+	dc.section = sec
+	dc.configLock.Unlock()
 
-func (dc *Controller) NotifyChange(ctx context.Context, changeType, id string, sec *conf.Section) error {
 	dc.interfacerLock.Lock()
 	defer dc.interfacerLock.Unlock()
 
@@ -161,62 +316,39 @@ func (dc *Controller) NotifyChange(ctx context.Context, changeType, id string, s
 	}
 
 	dc.door = NoOp{}
+	dc.setActualState(Unknown)
 
-	switch changeType {
-	case "update", "create":
-		var cfg DoorConfig
-		if err := conf.DecodeSections([]conf.Section{*sec}, Spec, &cfg); err != nil {
-			return err
-		}
-
-		switch cfg.Type {
-		case "shelly-script":
-			dc.door = &ShellyScriptDoor{
-				url: cfg.ShellyScriptURL,
-			}
-
-		case "disabled":
-			return nil
-
-		default:
-			return fmt.Errorf("invalid door interface type: %q", cfg.Type)
-		}
+	door, err := newInterfacer(cfg, sec)
+	if err != nil {
+		return err
 	}
 
+	dc.door = door
+
 	return nil
 }
 
-// Overwrite overwrites the current door state with state until untilTime.
-func (dc *Controller) Overwrite(ctx context.Context, state State, untilTime time.Time) error {
-	log.From(ctx).V(7).Logf("overwritting door state to %s until %s", state, untilTime)
+// release releases the door interfacer.
+func (dc *Controller) release() {
+	dc.interfacerLock.Lock()
+	defer dc.interfacerLock.Unlock()
 
-	if err := isValidState(state); err != nil {
-		return err
+	if dc.door != nil {
+		dc.door.Release()
 	}
 
-	dc.overwriteLock.Lock()
-	{
-		dc.manualOverwrite = &stateOverwrite{
-			state:       state,
-			sessionUser: session.UserFromCtx(ctx).User.Id,
-			until:       untilTime,
-		}
-	}
-	dc.overwriteLock.Unlock()
+	dc.door = NoOp{}
+
+	dc.events.close()
+}
 
-	// trigger a soft reset, unlocking above is REQUIRED
-	// to avoid deadlocking with getManualOverwrite() in
-	// scheduler() (which triggers immediately)
+// softReset triggers a soft reset of the scheduler so the desired
+// door state is re-evaluated and applied.
+func (dc *Controller) softReset() {
 	select {
-	case <-ctx.Done():
-		return ctx.Err()
-	case <-dc.stop:
-		return errors.New("stopped")
 	case dc.reset <- resetSoft:
-		log.From(ctx).V(6).Logf("door overwrite forcing %s until %s done", state, untilTime)
+	default:
 	}
-
-	return nil
 }
 
 // Lock implements DoorInterfacer.
@@ -255,11 +387,40 @@ func (dc *Controller) Unlock(ctx context.Context) error {
 	return dc.door.Unlock(ctx)
 }
 
-// Open implements DoorInterfacer.
-func (dc *Controller) Open(ctx context.Context) error {
+// OpenDuration returns the time the door is kept open when requesting
+// d. A zero duration selects the configured default. ErrInvalidOpenDuration
+// is returned if d is negative or exceeds the configured maximum.
+func (dc *Controller) OpenDuration(d time.Duration) (time.Duration, error) {
+	dc.configLock.RLock()
+	defer dc.configLock.RUnlock()
+
+	switch {
+	case d == 0:
+		return dc.openDuration, nil
+	case d < 0:
+		return 0, fmt.Errorf("%w: %s", ErrInvalidOpenDuration, d)
+	case d > dc.maxOpenDuration:
+		return 0, fmt.Errorf("%w: %s exceeds the maximum of %s", ErrInvalidOpenDuration, d, dc.maxOpenDuration)
+	}
+
+	return d, nil
+}
+
+// Open implements DoorInterfacer. The door is kept open for d or
+// the configured default if d is zero.
+func (dc *Controller) Open(ctx context.Context, d time.Duration) error {
 	ctx, sp := otel.Tracer("").Start(ctx, "door.Controller.Open")
 	defer sp.End()
 
+	if dc.ActiveLockdown() != nil {
+		return ErrLockdownActive
+	}
+
+	d, err := dc.OpenDuration(d)
+	if err != nil {
+		return err
+	}
+
 	dc.wg.Add(1)
 	defer dc.wg.Done()
 
@@ -270,7 +431,63 @@ func (dc *Controller) Open(ctx context.Context) error {
 		return fmt.Errorf("unconfigured door interfacer")
 	}
 
-	return dc.door.Open(ctx)
+	previous, _, _ := dc.Current(ctx)
+	now := dc.Now()
+
+	err = dc.door.Open(ctx, d)
+	dc.publishError(Open, err)
+
+	dc.record(ctx, AuditRecord{
+		Action:        AuditOpen,
+		Actor:         session.UserFromCtx(ctx).GetUser().GetId(),
+		DesiredState:  Open,
+		PreviousState: previous,
+		From:          now,
+		Until:         now.Add(d),
+	}, err)
+
+	return err
+}
+
+// Actual returns the door state as last reported by the door interfacer
+// together with the time it has been reported. If the interfacer does
+// not implement StateReporter, Unknown is returned.
+func (dc *Controller) Actual() (State, time.Time) {
+	dc.actualStateLock.Lock()
+	defer dc.actualStateLock.Unlock()
+
+	return dc.actualState, dc.actualStateTime
+}
+
+// refreshActualState queries the door interfacer for the actual door state,
+// if supported, and returns it.
+func (dc *Controller) refreshActualState(ctx context.Context) State {
+	dc.interfacerLock.Lock()
+	reporter, ok := dc.door.(StateReporter)
+	dc.interfacerLock.Unlock()
+
+	if !ok {
+		return Unknown
+	}
+
+	state, err := reporter.ReportState(ctx)
+	if err != nil {
+		log.From(ctx).Errorf("failed to get actual door state: %s", err)
+
+		state = Unknown
+	}
+
+	dc.setActualState(state)
+
+	return state
+}
+
+func (dc *Controller) setActualState(state State) {
+	dc.actualStateLock.Lock()
+	defer dc.actualStateLock.Unlock()
+
+	dc.actualState = state
+	dc.actualStateTime = dc.Now()
 }
 
 // Start starts the scheduler for the door controller.
@@ -291,52 +508,30 @@ func (dc *Controller) Stop() error {
 	return nil
 }
 
-// Reset triggers a reset of the door scheduler.
-func (dc *Controller) Reset(ctx context.Context) error {
-	select {
-	case <-ctx.Done():
-		return ctx.Err()
-
-	case <-dc.stop:
-		return errors.New("stopped")
-
-	// trigger a hard-reset
-	case dc.reset <- resetHard:
-		return nil
+// record writes an audit record for a door action. If err is non-nil
+// the action is recorded as failed.
+func (dc *Controller) record(ctx context.Context, record AuditRecord, err error) {
+	if dc.audit == nil {
+		return
 	}
-}
 
-// resetDoor resets the entry door by unlocking, locking and unlocking
-// it again. For whatever reason, this proved to work best when the door
-// does not behave as it should.
-func (dc *Controller) resetDoor(ctx context.Context) {
-	dc.wg.Add(1)
-	defer dc.wg.Done()
-
-	dc.resetInProgress.Set()
-	defer dc.resetInProgress.UnSet()
-
-	// remove any manual overwrite when we do a reset.
-	dc.overwriteLock.Lock()
-	dc.manualOverwrite = nil
-	dc.overwriteLock.Unlock()
-
-	log := log.From(ctx)
-	ctx, cancel := context.WithTimeout(ctx, time.Second*10)
-	defer cancel()
+	record.Door = dc.id
 
-	if err := dc.door.Unlock(ctx); err != nil {
-		log.Errorf("failed to unlock door: %s", err)
+	if record.Time.IsZero() {
+		record.Time = dc.Now()
 	}
 
-	time.Sleep(time.Second * 2)
-	if err := dc.door.Lock(ctx); err != nil {
-		log.Errorf("failed to unlock door: %s", err)
+	if err != nil {
+		record.Error = err.Error()
 	}
 
-	time.Sleep(time.Second * 2)
-	if err := dc.door.Unlock(ctx); err != nil {
-		log.Errorf("failed to unlock door: %s", err)
+	// audit records should be written even if ctx has already
+	// been cancelled.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	if err := dc.audit.Record(ctx, record); err != nil {
+		log.From(ctx).Errorf("failed to write door audit record: %s", err)
 	}
 }
 
@@ -349,31 +544,62 @@ func (dc *Controller) scheduler() {
 	const maxTriesLocked = 60
 	const maxTriesUnlocked = 20
 
+	clk := dc.Clock()
+
 	retries := 0
 	maxTries := maxTriesLocked
+
+	// drifting is set while the door reports a state other than the
+	// one applied last so retries are only reset when a drift starts.
+	drifting := false
+
+	// wait is the time to wait before re-sending the current state.
+	// It is increased exponentially if applying the state fails.
+	wait := time.Minute
 	// trigger immediately
-	until := time.Now().Add(time.Second)
+	until := clk.Now().Add(time.Second)
 
 	for {
 		ctx := context.Background()
 
+		untilTimer := clk.NewTimer(until.Sub(clk.Now()))
+
+		// resend lock commands periodically as the door
+		// might be open and may thus miss commands.
+		resendTimer := clk.NewTimer(wait)
+
+		var req *resetRequest
+		stopped := false
+		hasReset := false
+
 		select {
 		case <-dc.stop:
+			stopped = true
+		case req = <-dc.reset:
+			hasReset = true
+		case <-untilTimer.C():
+		case <-resendTimer.C():
+		}
+
+		untilTimer.Stop()
+		resendTimer.Stop()
+
+		if stopped {
 			return
-		case hard := <-dc.reset:
-			if hard != resetSoft {
+		}
+
+		if hasReset {
+			if req != resetSoft {
 				// reset the door state. it will unlock for a second or so.
-				dc.resetDoor(ctx)
+				dc.resetDoor(ctx, req)
 			}
 			// force applying the door state.
 			lastState = State("")
-		case <-time.After(time.Until(until)):
-
-		// resend lock commands periodically as the door
-		// might be open and may thus miss commands.
-		case <-time.After(time.Minute):
 		}
 
+		// drop all overwrites that expired in the meantime.
+		dc.pruneOverwrites(ctx, clk.Now())
+
 		ctx, cancel := context.WithTimeout(ctx, time.Second)
 
 		var resetInProgress bool
@@ -386,11 +612,18 @@ func (dc *Controller) scheduler() {
 		}
 
 		if until.IsZero() {
-			until = time.Now().Add(time.Minute * 5)
+			until = clk.Now().Add(time.Minute * 5)
 		}
 
+		// the actual state is not queried using ctx as that would
+		// leave no time to apply the desired state.
+		stateCtx, cancelState := context.WithTimeout(context.Background(), actualStateTimeout)
+		actual := dc.refreshActualState(stateCtx)
+		cancelState()
+
 		if state != lastState {
 			retries = 0
+			drifting = false
 
 			switch state {
 			case Locked:
@@ -398,6 +631,15 @@ func (dc *Controller) scheduler() {
 			case Unlocked:
 				maxTries = maxTriesUnlocked
 			}
+		} else if !hasDrifted(state, actual) {
+			drifting = false
+		} else if !drifting {
+			// the door does not report the state we applied last time
+			// so make sure we re-issue the command.
+			log.From(ctx).Infof("door state drifted: desired %s but door reports %s, re-applying", state, actual)
+
+			retries = 0
+			drifting = true
 		}
 
 		// only trigger when we need to change state.
@@ -417,9 +659,65 @@ func (dc *Controller) scheduler() {
 				continue
 			}
 
+			// the door is still busy with a previous command (like
+			// being held open) so this is not a failed attempt. Try
+			// again shortly.
+			if errors.Is(err, ErrDoorBusy) {
+				log.From(ctx).V(1).Logf("door is busy, applying %s later", state)
+
+				retries--
+				wait = busyRetryDelay
+				cancel()
+
+				continue
+			}
+
+			health := dc.recordAttempt(state, err)
+
+			// record the first attempt to apply a new state and the
+			// first failure of a series of failed attempts. Retries
+			// are not recorded to keep the audit log readable.
+			if retries == 1 || (err != nil && health.ConsecutiveFailures == 1) {
+				dc.record(ctx, AuditRecord{
+					Action:        AuditSchedule,
+					DesiredState:  state,
+					PreviousState: lastState,
+				}, err)
+			}
+
 			if err != nil {
 				log.From(ctx).Errorf("failed to set desired door state %s: %s", string(state), err)
+
+				dc.publishError(state, err)
+
+				wait = retryBackoff(health.ConsecutiveFailures)
+
+				// give up if the door could not be controlled within the
+				// failure budget so staff is alerted in time. Stop retrying
+				// until the desired state changes or the door is reset.
+				if retries >= maxTries || clk.Now().Sub(health.FailingSince) >= failureBudget {
+					retries = maxTries
+
+					dc.record(ctx, AuditRecord{
+						Action:        AuditSchedule,
+						DesiredState:  state,
+						PreviousState: lastState,
+					}, fmt.Errorf("giving up after %d failed attempts: %w", health.ConsecutiveFailures, err))
+
+					dc.retriesExhausted(ctx)
+				}
 			} else {
+				wait = time.Minute
+
+				if state != lastState {
+					stateUntil := until
+					dc.publish(Event{
+						Type:  EventStateChanged,
+						State: state,
+						Until: &stateUntil,
+					})
+				}
+
 				lastState = state
 			}
 		}
@@ -429,7 +727,7 @@ func (dc *Controller) scheduler() {
 
 // Current returns the current door state.
 func (dc *Controller) Current(ctx context.Context) (State, time.Time, bool) {
-	state, until := dc.stateFor(ctx, time.Now().In(dc.Location()))
+	state, until := dc.stateFor(ctx, dc.Now())
 
 	return state, until, dc.resetInProgress.IsSet()
 }
@@ -444,19 +742,37 @@ func (dc *Controller) StateFor(ctx context.Context, t time.Time) (State, time.Ti
 
 func (dc *Controller) stateFor(ctx context.Context, t time.Time) (State, time.Time) {
 	log := log.From(ctx)
+
+	// a lockdown takes precedence over everything else and lasts
+	// until it is released.
+	if dc.lockdownAt(t) != nil {
+		return Locked, time.Time{}
+	}
+
+	overwrites := dc.getManualOverwrites()
+
 	// if we have an active overwrite we need to return it
 	// together with it's end time.
-	if overwrite := dc.getManualOverwrite(); overwrite != nil && overwrite.until.After(t) {
-		log.Infof("using manual door overwrite %q by %q until %s", overwrite.state, overwrite.sessionUser, overwrite.until)
+	if overwrite := activeOverwrite(overwrites, t); overwrite != nil {
+		log.Infof("using manual door overwrite %q by %q until %s", overwrite.State, overwrite.SessionUser, overwrite.Until)
 
-		return overwrite.state, overwrite.until
+		return overwrite.State, nextOverwriteChange(overwrites, overwrite, t, overwrite.Until)
 	}
 
 	// we need one frame because we might be in the middle
 	// of it or before it.
-	upcoming := dc.UpcomingFrames(ctx, t, 1)
+	upcoming := dc.UpcomingDoorFramesFor(ctx, t, 1, dc.OpeningHours())
+
+	// frames include their end time. If the frame ends exactly at t
+	// we must use the next one, otherwise the door would be reported
+	// as unlocked until t.
+	if len(upcoming) == 1 && upcoming[0].To.Equal(t) {
+		upcoming = dc.UpcomingDoorFramesFor(ctx, t, 2, dc.OpeningHours())[1:]
+	}
+
 	if len(upcoming) == 0 {
-		return Locked, time.Time{} // forever locked as there are no frames ...
+		// forever locked as there are no frames ...
+		return Locked, nextOverwriteChange(overwrites, nil, t, time.Time{})
 	}
 
 	f := upcoming[0]
@@ -464,19 +780,26 @@ func (dc *Controller) stateFor(ctx context.Context, t time.Time) (State, time.Ti
 	// if we are t is covered by f than should be unlocked
 	// until the end of f.
 	if f.Covers(t) {
-		return Unlocked, f.To
+		return Unlocked, nextOverwriteChange(overwrites, nil, t, f.To)
 	}
 
 	// Otherwise there's no active frame so we are locked until
 	// f starts.
-	return Locked, f.From
+	return Locked, nextOverwriteChange(overwrites, nil, t, f.From)
 }
 
-func (dc *Controller) getManualOverwrite() *stateOverwrite {
-	dc.overwriteLock.Lock()
-	defer dc.overwriteLock.Unlock()
+// hasDrifted reports whether the actual state sensed by the door
+// interfacer does not match the desired state. An unknown actual state
+// is never treated as a drift. An open door is expected to be unlocked.
+func hasDrifted(desired, actual State) bool {
+	switch actual {
+	case Unknown:
+		return false
+	case Open:
+		return desired != Unlocked
+	}
 
-	return dc.manualOverwrite
+	return desired != actual
 }
 
 func isValidState(state State) error {
@@ -0,0 +1,228 @@
+package door
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ppacher/system-conf/conf"
+	"github.com/tierklinik-dobersberg/cis/runtime"
+)
+
+// DisabledType is the door type used if door control is disabled.
+// It is always available and does not require a driver.
+const DisabledType = "disabled"
+
+// Driver describes a door interfacer implementation that can be
+// selected using the Type option of a door configuration.
+type Driver struct {
+	// Type is the unique name of the driver as used in the Type
+	// option.
+	Type string
+
+	// DisplayName is a human readable name of the driver.
+	DisplayName string
+
+	// Spec defines the configuration options of the driver. The
+	// options are added to the Door schema and are only relevant
+	// if Type is set to the driver. Option names must not conflict
+	// with other drivers so they should be prefixed with the type.
+	Spec conf.SectionSpec
+
+	// Validate is called to validate the door configuration in sec.
+	// Driver options can be decoded from sec using Spec. Validate
+	// is optional.
+	Validate func(sec conf.Section) error
+
+	// Unique lists driver options whose values must not be shared
+	// by multiple doors of the same Type (like a MQTT client ID).
+	// Options that are not set are not compared.
+	Unique []string
+
+	// New returns a new door interfacer for the door configuration
+	// cfg. Driver options can be decoded from sec using Spec.
+	New func(cfg DoorConfig, sec conf.Section) (Interfacer, error)
+}
+
+var (
+	driversLock sync.RWMutex
+	drivers     = make(map[string]Driver)
+
+	// driverSpec holds the generated Door schema. It is reset
+	// whenever a new driver is registered.
+	driverSpec conf.SectionSpec
+)
+
+// RegisterDriver registers a new door driver. Drivers are expected to
+// register themself during init. The built-in drivers live in the
+// sub-packages of internal/door/drivers and must be imported for their
+// side effects to be available.
+func RegisterDriver(driver Driver) error {
+	if driver.Type == "" {
+		return fmt.Errorf("driver type must be set")
+	}
+
+	if driver.Type == DisabledType {
+		return fmt.Errorf("driver type %q is reserved", driver.Type)
+	}
+
+	if driver.New == nil {
+		return fmt.Errorf("driver %s: New must be set", driver.Type)
+	}
+
+	if driver.DisplayName == "" {
+		driver.DisplayName = driver.Type
+	}
+
+	driversLock.Lock()
+	defer driversLock.Unlock()
+
+	if _, ok := drivers[driver.Type]; ok {
+		return fmt.Errorf("driver %s: already registered", driver.Type)
+	}
+
+	// copy the driver spec so we don't modify the annotations of
+	// the caller.
+	spec := make(conf.SectionSpec, len(driver.Spec))
+	for idx, opt := range driver.Spec {
+		if _, ok := buildSpec().GetOption(strings.ToLower(opt.Name)); ok {
+			return fmt.Errorf("driver %s: option %s is already defined", driver.Type, opt.Name)
+		}
+
+		annotations := make(conf.Annotation, len(opt.Annotations)+1)
+		for key, value := range opt.Annotations {
+			annotations[key] = value
+		}
+		annotations.With(runtime.DependsOn("Type", driver.Type))
+
+		opt.Annotations = annotations
+		spec[idx] = opt
+	}
+	driver.Spec = spec
+
+	drivers[driver.Type] = driver
+	driverSpec = nil
+
+	return nil
+}
+
+// MustRegisterDriver is like RegisterDriver but panics on error.
+func MustRegisterDriver(driver Driver) {
+	runtime.Must(RegisterDriver(driver))
+}
+
+// Drivers returns all registered door drivers sorted by their display
+// name.
+func Drivers() []Driver {
+	driversLock.RLock()
+	defer driversLock.RUnlock()
+
+	return sortedDrivers()
+}
+
+// GetDriver returns the door driver registered for typ.
+func GetDriver(typ string) (Driver, bool) {
+	driversLock.RLock()
+	defer driversLock.RUnlock()
+
+	driver, ok := drivers[typ]
+
+	return driver, ok
+}
+
+func sortedDrivers() []Driver {
+	result := make([]Driver, 0, len(drivers))
+	for _, driver := range drivers {
+		result = append(result, driver)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].DisplayName < result[j].DisplayName
+	})
+
+	return result
+}
+
+// buildSpec returns the Door schema consisting of the common door
+// options, the Type option and the options of all registered drivers.
+// The caller must hold driversLock.
+func buildSpec() conf.SectionSpec {
+	if driverSpec != nil {
+		return driverSpec
+	}
+
+	sorted := sortedDrivers()
+
+	values := make([]runtime.PossibleValue, 0, len(sorted)+1)
+	for _, driver := range sorted {
+		values = append(values, runtime.PossibleValue{
+			Display: driver.DisplayName,
+			Value:   driver.Type,
+		})
+	}
+	values = append(values, runtime.PossibleValue{
+		Display: "Disabled",
+		Value:   DisabledType,
+	})
+
+	spec := make(conf.SectionSpec, 0, len(commonSpec)+1)
+	spec = append(spec, commonSpec...)
+	spec = append(spec, conf.OptionSpec{
+		Name:        "Type",
+		Required:    true,
+		Default:     DisabledType,
+		Description: "The type of door interface to use",
+		Type:        conf.StringType,
+		Annotations: new(conf.Annotation).With(
+			runtime.OneOf(values...),
+		),
+	})
+
+	for _, driver := range sorted {
+		spec = append(spec, driver.Spec...)
+	}
+
+	driverSpec = spec
+
+	return spec
+}
+
+// doorSpec implements conf.OptionRegistry for the Door schema based
+// on the registered drivers.
+type doorSpec struct{}
+
+func (doorSpec) HasOption(optName string) bool {
+	_, ok := doorSpec{}.GetOption(optName)
+
+	return ok
+}
+
+func (doorSpec) GetOption(optName string) (conf.OptionSpec, bool) {
+	driversLock.Lock()
+	defer driversLock.Unlock()
+
+	return buildSpec().GetOption(optName)
+}
+
+func (doorSpec) All() []conf.OptionSpec {
+	driversLock.Lock()
+	defer driversLock.Unlock()
+
+	return buildSpec()
+}
+
+// newInterfacer returns the door interfacer for cfg using the driver
+// registered for cfg.Type.
+func newInterfacer(cfg DoorConfig, sec conf.Section) (Interfacer, error) {
+	if cfg.Type == DisabledType {
+		return NoOp{}, nil
+	}
+
+	driver, ok := GetDriver(cfg.Type)
+	if !ok {
+		return nil, fmt.Errorf("invalid door interface type: %q", cfg.Type)
+	}
+
+	return driver.New(cfg, sec)
+}
@@ -0,0 +1,111 @@
+package door_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ppacher/system-conf/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tierklinik-dobersberg/cis/internal/door"
+	_ "github.com/tierklinik-dobersberg/cis/internal/door/drivers/httpdoor"
+	_ "github.com/tierklinik-dobersberg/cis/internal/door/drivers/mqttdoor"
+	_ "github.com/tierklinik-dobersberg/cis/internal/door/drivers/shellyrpc"
+	_ "github.com/tierklinik-dobersberg/cis/internal/door/drivers/shellyscript"
+	"github.com/tierklinik-dobersberg/cis/runtime"
+)
+
+type testInterfacer struct{}
+
+func (testInterfacer) Lock(context.Context) error                { return nil }
+func (testInterfacer) Unlock(context.Context) error              { return nil }
+func (testInterfacer) Open(context.Context, time.Duration) error { return nil }
+func (testInterfacer) Release()                                  {}
+
+// TestRegisterDriver modifies the global driver registry and must not
+// run in parallel to tests that use the Door schema.
+func TestRegisterDriver(t *testing.T) {
+	t.Cleanup(func() {
+		door.UnregisterDriver("registry-test")
+	})
+
+	newFn := func(door.DoorConfig, conf.Section) (door.Interfacer, error) {
+		return testInterfacer{}, nil
+	}
+
+	require.NoError(t, door.RegisterDriver(door.Driver{
+		Type:        "registry-test",
+		DisplayName: "Registry Test",
+		Spec: conf.SectionSpec{
+			{
+				Name: "RegistryTestAddress",
+				Type: conf.StringType,
+			},
+		},
+		New: newFn,
+	}))
+
+	driver, ok := door.GetDriver("registry-test")
+	require.True(t, ok)
+	assert.Equal(t, "Registry Test", driver.DisplayName)
+
+	// the Type option lists the new driver
+	typeOpt, ok := door.Spec.GetOption("type")
+	require.True(t, ok)
+
+	oneOf, ok := typeOpt.Annotations.Get("vet.dobersberg.cis:schema/oneOf").(runtime.OneOfAnnotation)
+	require.True(t, ok)
+	assert.Contains(t, oneOf.Values, runtime.PossibleValue{Display: "Registry Test", Value: "registry-test"})
+	assert.Equal(t, door.DisabledType, oneOf.Values[len(oneOf.Values)-1].Value)
+
+	// driver options are part of the door schema and depend on the type
+	opt, ok := door.Spec.GetOption("registrytestaddress")
+	require.True(t, ok)
+	assert.Equal(t, runtime.DependsOnAnnotation{
+		Option: "Type",
+		Values: []string{"registry-test"},
+	}, opt.Annotations.Get("vet.dobersberg.cis:schema/dependsOn"))
+
+	// duplicate types are rejected
+	assert.Error(t, door.RegisterDriver(door.Driver{
+		Type: "registry-test",
+		New:  newFn,
+	}))
+
+	// option names must not conflict with other drivers
+	assert.Error(t, door.RegisterDriver(door.Driver{
+		Type: "registry-conflict",
+		Spec: conf.SectionSpec{
+			{
+				Name: "HTTPURL",
+				Type: conf.StringType,
+			},
+		},
+		New: newFn,
+	}))
+
+	// the disabled type is reserved and New is required
+	assert.Error(t, door.RegisterDriver(door.Driver{Type: door.DisabledType, New: newFn}))
+	assert.Error(t, door.RegisterDriver(door.Driver{Type: "registry-no-constructor"}))
+}
+
+func TestBuiltinDrivers(t *testing.T) {
+	t.Parallel()
+
+	for _, typ := range []string{"shelly-script", "shelly-rpc", "mqtt", "http"} {
+		_, ok := door.GetDriver(typ)
+		assert.True(t, ok, typ)
+	}
+}
+
+func TestUnregisterDriver(t *testing.T) {
+	t.Parallel()
+
+	// drivers registered by tests are removed from the Door schema.
+	_, ok := door.GetDriver("registry-test")
+	assert.False(t, ok)
+
+	_, ok = door.Spec.GetOption("registrytestaddress")
+	assert.False(t, ok)
+}
@@ -0,0 +1,649 @@
+package httpdoor
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/ppacher/system-conf/conf"
+	"github.com/tierklinik-dobersberg/cis/internal/door"
+)
+
+// defaultBody is the request body used if no body is configured.
+const defaultBody = `{"action": "{{ .Action }}"}`
+
+// httpAction describes the request sent for a single door action.
+type httpAction struct {
+	method  string
+	url     string
+	headers http.Header
+	body    *template.Template
+	client  *http.Client
+
+	username    string
+	password    string
+	bearerToken string
+
+	expectedStatus []int
+}
+
+// BodyContext is passed to the body template of HTTP door
+// requests.
+type BodyContext struct {
+	// Door is the name of the door.
+	Door string
+	// Action is the action to perform (lock, unlock or open).
+	Action string
+	// Time is the time the request is sent.
+	Time time.Time
+	// Duration is the time the door should be kept open. It is
+	// only set for the open action.
+	Duration time.Duration
+}
+
+// Door controls the door by sending configurable HTTP requests
+// for each door action. It can be used to drive relay boards or
+// home-automation bridges that expose a HTTP API.
+type Door struct {
+	name    string
+	actions map[string]httpAction
+}
+
+// Config holds the configuration of the http door driver.
+type Config struct {
+	HTTPMethod                   string
+	HTTPURL                      string
+	HTTPBody                     string
+	HTTPHeaders                  []string
+	HTTPLockMethod               string
+	HTTPLockURL                  string
+	HTTPLockBody                 string
+	HTTPLockHeaders              []string
+	HTTPLockUser                 string
+	HTTPLockPassword             string
+	HTTPLockBearerToken          string
+	HTTPLockExpectedStatus       []int
+	HTTPLockTimeout              time.Duration
+	HTTPLockInsecureSkipVerify   *bool
+	HTTPLockCACertificate        string
+	HTTPUnlockMethod             string
+	HTTPUnlockURL                string
+	HTTPUnlockBody               string
+	HTTPUnlockHeaders            []string
+	HTTPUnlockUser               string
+	HTTPUnlockPassword           string
+	HTTPUnlockBearerToken        string
+	HTTPUnlockExpectedStatus     []int
+	HTTPUnlockTimeout            time.Duration
+	HTTPUnlockInsecureSkipVerify *bool
+	HTTPUnlockCACertificate      string
+	HTTPOpenMethod               string
+	HTTPOpenURL                  string
+	HTTPOpenBody                 string
+	HTTPOpenHeaders              []string
+	HTTPOpenUser                 string
+	HTTPOpenPassword             string
+	HTTPOpenBearerToken          string
+	HTTPOpenExpectedStatus       []int
+	HTTPOpenTimeout              time.Duration
+	HTTPOpenInsecureSkipVerify   *bool
+	HTTPOpenCACertificate        string
+	HTTPUser                     string
+	HTTPPassword                 string
+	HTTPBearerToken              string
+	HTTPExpectedStatus           []int
+	HTTPTimeout                  time.Duration
+	HTTPInsecureSkipVerify       bool
+	HTTPCACertificate            string
+}
+
+// Spec defines the options of the http door driver.
+var Spec = conf.SectionSpec{
+	{
+		Name:        "HTTPMethod",
+		Type:        conf.StringType,
+		Description: "The default HTTP method used for door requests",
+		Default:     "POST",
+	},
+	{
+		Name:        "HTTPURL",
+		Type:        conf.StringType,
+		Description: "The default URL used for door requests",
+	},
+	{
+		Name:        "HTTPBody",
+		Type:        conf.StringType,
+		Description: "The default request body as a Go template. Available fields are {{ .Door }}, {{ .Action }}, {{ .Time }} and {{ .Duration }} (open only). The built-in default is sent with Content-Type application/json",
+		Default:     defaultBody,
+	},
+	{
+		Name:        "HTTPHeaders",
+		Type:        conf.StringSliceType,
+		Description: "Additional headers sent with each door request in the format \"Name: Value\"",
+	},
+	{
+		Name:        "HTTPLockMethod",
+		Type:        conf.StringType,
+		Description: "The HTTP method used to lock the door. Defaults to HTTPMethod",
+	},
+	{
+		Name:        "HTTPLockURL",
+		Type:        conf.StringType,
+		Description: "The URL used to lock the door. Defaults to HTTPURL",
+	},
+	{
+		Name:        "HTTPLockBody",
+		Type:        conf.StringType,
+		Description: "The request body template used to lock the door. Defaults to HTTPBody",
+	},
+	{
+		Name:        "HTTPLockHeaders",
+		Type:        conf.StringSliceType,
+		Description: "Additional headers sent when the door should lock",
+	},
+	{
+		Name:        "HTTPLockUser",
+		Type:        conf.StringType,
+		Description: "The username used for HTTP basic authentication when the door should lock. If HTTPLockUser or HTTPLockBearerToken is set, the default authentication is not used",
+	},
+	{
+		Name:        "HTTPLockPassword",
+		Type:        conf.StringType,
+		Description: "The password used for HTTP basic authentication when the door should lock",
+	},
+	{
+		Name:        "HTTPLockBearerToken",
+		Type:        conf.StringType,
+		Description: "A bearer token sent in the Authorization header when the door should lock",
+	},
+	{
+		Name:        "HTTPLockExpectedStatus",
+		Type:        conf.IntSliceType,
+		Description: "A list of status codes that indicate the door has been locked. Defaults to HTTPExpectedStatus",
+	},
+	{
+		Name:        "HTTPLockTimeout",
+		Type:        conf.DurationType,
+		Description: "The timeout for requests to lock the door. Defaults to HTTPTimeout",
+	},
+	{
+		Name:        "HTTPLockInsecureSkipVerify",
+		Type:        conf.BoolType,
+		Description: "If set, the TLS certificate of the server is not verified when the door should lock. Defaults to HTTPInsecureSkipVerify",
+	},
+	{
+		Name:        "HTTPLockCACertificate",
+		Type:        conf.StringType,
+		Description: "Path to a PEM encoded CA certificate used to verify the TLS certificate of the server when the door should lock. Defaults to HTTPCACertificate",
+	},
+	{
+		Name:        "HTTPUnlockMethod",
+		Type:        conf.StringType,
+		Description: "The HTTP method used to unlock the door. Defaults to HTTPMethod",
+	},
+	{
+		Name:        "HTTPUnlockURL",
+		Type:        conf.StringType,
+		Description: "The URL used to unlock the door. Defaults to HTTPURL",
+	},
+	{
+		Name:        "HTTPUnlockBody",
+		Type:        conf.StringType,
+		Description: "The request body template used to unlock the door. Defaults to HTTPBody",
+	},
+	{
+		Name:        "HTTPUnlockHeaders",
+		Type:        conf.StringSliceType,
+		Description: "Additional headers sent when the door should unlock",
+	},
+	{
+		Name:        "HTTPUnlockUser",
+		Type:        conf.StringType,
+		Description: "The username used for HTTP basic authentication when the door should unlock. If HTTPUnlockUser or HTTPUnlockBearerToken is set, the default authentication is not used",
+	},
+	{
+		Name:        "HTTPUnlockPassword",
+		Type:        conf.StringType,
+		Description: "The password used for HTTP basic authentication when the door should unlock",
+	},
+	{
+		Name:        "HTTPUnlockBearerToken",
+		Type:        conf.StringType,
+		Description: "A bearer token sent in the Authorization header when the door should unlock",
+	},
+	{
+		Name:        "HTTPUnlockExpectedStatus",
+		Type:        conf.IntSliceType,
+		Description: "A list of status codes that indicate the door has been unlocked. Defaults to HTTPExpectedStatus",
+	},
+	{
+		Name:        "HTTPUnlockTimeout",
+		Type:        conf.DurationType,
+		Description: "The timeout for requests to unlock the door. Defaults to HTTPTimeout",
+	},
+	{
+		Name:        "HTTPUnlockInsecureSkipVerify",
+		Type:        conf.BoolType,
+		Description: "If set, the TLS certificate of the server is not verified when the door should unlock. Defaults to HTTPInsecureSkipVerify",
+	},
+	{
+		Name:        "HTTPUnlockCACertificate",
+		Type:        conf.StringType,
+		Description: "Path to a PEM encoded CA certificate used to verify the TLS certificate of the server when the door should unlock. Defaults to HTTPCACertificate",
+	},
+	{
+		Name:        "HTTPOpenMethod",
+		Type:        conf.StringType,
+		Description: "The HTTP method used to open the door. Defaults to HTTPMethod",
+	},
+	{
+		Name:        "HTTPOpenURL",
+		Type:        conf.StringType,
+		Description: "The URL used to open the door. Defaults to HTTPURL",
+	},
+	{
+		Name:        "HTTPOpenBody",
+		Type:        conf.StringType,
+		Description: "The request body template used to open the door. Defaults to HTTPBody",
+	},
+	{
+		Name:        "HTTPOpenHeaders",
+		Type:        conf.StringSliceType,
+		Description: "Additional headers sent when the door should open",
+	},
+	{
+		Name:        "HTTPOpenUser",
+		Type:        conf.StringType,
+		Description: "The username used for HTTP basic authentication when the door should open. If HTTPOpenUser or HTTPOpenBearerToken is set, the default authentication is not used",
+	},
+	{
+		Name:        "HTTPOpenPassword",
+		Type:        conf.StringType,
+		Description: "The password used for HTTP basic authentication when the door should open",
+	},
+	{
+		Name:        "HTTPOpenBearerToken",
+		Type:        conf.StringType,
+		Description: "A bearer token sent in the Authorization header when the door should open",
+	},
+	{
+		Name:        "HTTPOpenExpectedStatus",
+		Type:        conf.IntSliceType,
+		Description: "A list of status codes that indicate the door has been opened. Defaults to HTTPExpectedStatus",
+	},
+	{
+		Name:        "HTTPOpenTimeout",
+		Type:        conf.DurationType,
+		Description: "The timeout for requests to open the door. Defaults to HTTPTimeout",
+	},
+	{
+		Name:        "HTTPOpenInsecureSkipVerify",
+		Type:        conf.BoolType,
+		Description: "If set, the TLS certificate of the server is not verified when the door should open. Defaults to HTTPInsecureSkipVerify",
+	},
+	{
+		Name:        "HTTPOpenCACertificate",
+		Type:        conf.StringType,
+		Description: "Path to a PEM encoded CA certificate used to verify the TLS certificate of the server when the door should open. Defaults to HTTPCACertificate",
+	},
+	{
+		Name:        "HTTPUser",
+		Type:        conf.StringType,
+		Description: "The default username used for HTTP basic authentication",
+	},
+	{
+		Name:        "HTTPPassword",
+		Type:        conf.StringType,
+		Description: "The default password used for HTTP basic authentication",
+	},
+	{
+		Name:        "HTTPBearerToken",
+		Type:        conf.StringType,
+		Description: "A default bearer token sent in the Authorization header. Takes precedence over basic authentication",
+	},
+	{
+		Name:        "HTTPExpectedStatus",
+		Type:        conf.IntSliceType,
+		Description: "A list of status codes that indicate success. If empty, any 2xx status code is accepted",
+	},
+	{
+		Name:        "HTTPTimeout",
+		Type:        conf.DurationType,
+		Description: "The default timeout for door requests",
+		Default:     "10s",
+	},
+	{
+		Name:        "HTTPInsecureSkipVerify",
+		Type:        conf.BoolType,
+		Description: "If set, the TLS certificate of the server is not verified. Use with care",
+		Default:     "no",
+	},
+	{
+		Name:        "HTTPCACertificate",
+		Type:        conf.StringType,
+		Description: "Path to a PEM encoded CA certificate used to verify the TLS certificate of the server",
+	},
+}
+
+// actionConfig holds the configuration of a single door action.
+// Empty values fall back to the defaults of Config.
+type actionConfig struct {
+	action             string
+	method             string
+	url                string
+	body               string
+	headers            []string
+	user               string
+	password           string
+	bearerToken        string
+	expectedStatus     []int
+	timeout            time.Duration
+	insecureSkipVerify *bool
+	caCertificate      string
+}
+
+// New returns a new HTTP door interfacer for the door name
+// using cfg.
+func New(name string, cfg Config) (*Door, error) {
+	hd := &Door{
+		name:    name,
+		actions: make(map[string]httpAction),
+	}
+
+	commonHeaders, err := parseHeaders(cfg.HTTPHeaders)
+	if err != nil {
+		return nil, fmt.Errorf("HTTPHeaders: %w", err)
+	}
+
+	for _, a := range []actionConfig{
+		{
+			action:             "lock",
+			method:             cfg.HTTPLockMethod,
+			url:                cfg.HTTPLockURL,
+			body:               cfg.HTTPLockBody,
+			headers:            cfg.HTTPLockHeaders,
+			user:               cfg.HTTPLockUser,
+			password:           cfg.HTTPLockPassword,
+			bearerToken:        cfg.HTTPLockBearerToken,
+			expectedStatus:     cfg.HTTPLockExpectedStatus,
+			timeout:            cfg.HTTPLockTimeout,
+			insecureSkipVerify: cfg.HTTPLockInsecureSkipVerify,
+			caCertificate:      cfg.HTTPLockCACertificate,
+		},
+		{
+			action:             "unlock",
+			method:             cfg.HTTPUnlockMethod,
+			url:                cfg.HTTPUnlockURL,
+			body:               cfg.HTTPUnlockBody,
+			headers:            cfg.HTTPUnlockHeaders,
+			user:               cfg.HTTPUnlockUser,
+			password:           cfg.HTTPUnlockPassword,
+			bearerToken:        cfg.HTTPUnlockBearerToken,
+			expectedStatus:     cfg.HTTPUnlockExpectedStatus,
+			timeout:            cfg.HTTPUnlockTimeout,
+			insecureSkipVerify: cfg.HTTPUnlockInsecureSkipVerify,
+			caCertificate:      cfg.HTTPUnlockCACertificate,
+		},
+		{
+			action:             "open",
+			method:             cfg.HTTPOpenMethod,
+			url:                cfg.HTTPOpenURL,
+			body:               cfg.HTTPOpenBody,
+			headers:            cfg.HTTPOpenHeaders,
+			user:               cfg.HTTPOpenUser,
+			password:           cfg.HTTPOpenPassword,
+			bearerToken:        cfg.HTTPOpenBearerToken,
+			expectedStatus:     cfg.HTTPOpenExpectedStatus,
+			timeout:            cfg.HTTPOpenTimeout,
+			insecureSkipVerify: cfg.HTTPOpenInsecureSkipVerify,
+			caCertificate:      cfg.HTTPOpenCACertificate,
+		},
+	} {
+		action, err := newAction(cfg, a, commonHeaders)
+		if err != nil {
+			return nil, err
+		}
+
+		hd.actions[a.action] = action
+	}
+
+	return hd, nil
+}
+
+// newAction returns the request configuration for the door action a
+// using the defaults of cfg for all values that are not set in a.
+func newAction(cfg Config, a actionConfig, commonHeaders http.Header) (httpAction, error) {
+	action := httpAction{
+		method:         firstNonEmpty(a.method, cfg.HTTPMethod, http.MethodPost),
+		url:            firstNonEmpty(a.url, cfg.HTTPURL),
+		headers:        commonHeaders.Clone(),
+		username:       cfg.HTTPUser,
+		password:       cfg.HTTPPassword,
+		bearerToken:    cfg.HTTPBearerToken,
+		expectedStatus: cfg.HTTPExpectedStatus,
+	}
+
+	if action.url == "" {
+		return action, fmt.Errorf("no URL configured for action %q", a.action)
+	}
+
+	headers, err := parseHeaders(a.headers)
+	if err != nil {
+		return action, fmt.Errorf("headers for action %q: %w", a.action, err)
+	}
+	for key, values := range headers {
+		action.headers[key] = values
+	}
+
+	body := firstNonEmpty(a.body, cfg.HTTPBody, defaultBody)
+	action.body, err = template.New(a.action).Parse(body)
+	if err != nil {
+		return action, fmt.Errorf("invalid body template for action %q: %w", a.action, err)
+	}
+
+	if body == defaultBody && action.headers.Get("Content-Type") == "" {
+		action.headers.Set("Content-Type", "application/json")
+	}
+
+	// credentials of the action replace the default ones as a
+	// whole so they are never mixed.
+	if a.user != "" || a.bearerToken != "" {
+		action.username = a.user
+		action.password = a.password
+		action.bearerToken = a.bearerToken
+	}
+
+	if len(a.expectedStatus) > 0 {
+		action.expectedStatus = a.expectedStatus
+	}
+
+	timeout := cfg.HTTPTimeout
+	if a.timeout > 0 {
+		timeout = a.timeout
+	}
+
+	insecureSkipVerify := cfg.HTTPInsecureSkipVerify
+	if a.insecureSkipVerify != nil {
+		insecureSkipVerify = *a.insecureSkipVerify
+	}
+
+	action.client, err = newClient(timeout, insecureSkipVerify, firstNonEmpty(a.caCertificate, cfg.HTTPCACertificate))
+	if err != nil {
+		return action, fmt.Errorf("action %q: %w", a.action, err)
+	}
+
+	return action, nil
+}
+
+// newClient returns a HTTP client that uses timeout and verifies
+// server certificates using caCertificate, if set.
+func newClient(timeout time.Duration, insecureSkipVerify bool, caCertificate string) (*http.Client, error) {
+	tlsConfig := &tls.Config{
+		// trunk-ignore(golangci-lint/gosec): explicitly requested by the user
+		InsecureSkipVerify: insecureSkipVerify,
+	}
+
+	if caCertificate != "" {
+		pem, err := os.ReadFile(caCertificate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA certificate %s does not contain any valid certificates", caCertificate)
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+	}, nil
+}
+
+func (hd *Door) Lock(ctx context.Context) error {
+	return hd.doRequest(ctx, "lock", 0)
+}
+
+func (hd *Door) Unlock(ctx context.Context) error {
+	return hd.doRequest(ctx, "unlock", 0)
+}
+
+func (hd *Door) Open(ctx context.Context, d time.Duration) error {
+	return hd.doRequest(ctx, "open", d)
+}
+
+func (hd *Door) doRequest(ctx context.Context, name string, d time.Duration) error {
+	action, ok := hd.actions[name]
+	if !ok {
+		return fmt.Errorf("no request configured for action %q", name)
+	}
+
+	body := new(bytes.Buffer)
+	if err := action.body.Execute(body, BodyContext{
+		Door:     hd.name,
+		Action:   name,
+		Time:     time.Now(),
+		Duration: d,
+	}); err != nil {
+		return fmt.Errorf("failed to render request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, action.method, action.url, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	for key, values := range action.headers {
+		req.Header[key] = values
+	}
+
+	switch {
+	case action.bearerToken != "":
+		req.Header.Set("Authorization", "Bearer "+action.bearerToken)
+	case action.username != "":
+		req.SetBasicAuth(action.username, action.password)
+	}
+
+	res, err := action.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to perform request: %w", err)
+	}
+	defer res.Body.Close()
+
+	// drain the body so the connection can be reused.
+	_, _ = io.Copy(io.Discard, res.Body)
+
+	if len(action.expectedStatus) > 0 {
+		if !slices.Contains(action.expectedStatus, res.StatusCode) {
+			return fmt.Errorf("unexpected status code: %s", res.Status)
+		}
+
+		return nil
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("unexpected status code: %s", res.Status)
+	}
+
+	return nil
+}
+
+func (hd *Door) Release() {
+	for _, action := range hd.actions {
+		action.client.CloseIdleConnections()
+	}
+}
+
+// parseHeaders parses a list of headers in the format "Name: Value".
+func parseHeaders(lines []string) (http.Header, error) {
+	headers := make(http.Header)
+
+	for _, line := range lines {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("invalid header %q, expected \"Name: Value\"", line)
+		}
+
+		headers.Add(strings.TrimSpace(key), strings.TrimSpace(value))
+	}
+
+	return headers, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
+
+var _ door.Interfacer = (*Door)(nil)
+
+func init() {
+	door.MustRegisterDriver(door.Driver{
+		Type:        "http",
+		DisplayName: "HTTP Webhook",
+		Spec:        Spec,
+		Validate: func(sec conf.Section) error {
+			var cfg Config
+			if err := conf.DecodeSections([]conf.Section{sec}, Spec, &cfg); err != nil {
+				return err
+			}
+
+			_, err := New("", cfg)
+
+			return err
+		},
+		New: func(doorCfg door.DoorConfig, sec conf.Section) (door.Interfacer, error) {
+			var cfg Config
+			if err := conf.DecodeSections([]conf.Section{sec}, Spec, &cfg); err != nil {
+				return nil, err
+			}
+
+			hd, err := New(doorCfg.Name, cfg)
+			if err != nil {
+				return nil, err
+			}
+
+			return hd, nil
+		},
+	})
+}
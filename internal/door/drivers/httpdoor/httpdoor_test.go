@@ -0,0 +1,343 @@
+package httpdoor_test
+
+import (
+	"context"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ppacher/system-conf/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tierklinik-dobersberg/cis/internal/door/drivers/httpdoor"
+)
+
+type recordedRequest struct {
+	Method string
+	Path   string
+	Header http.Header
+	Body   string
+}
+
+func newRecordingServer(t *testing.T, tls bool, status int) (*httptest.Server, *[]recordedRequest) {
+	t.Helper()
+
+	var (
+		lock     sync.Mutex
+		requests []recordedRequest
+	)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		lock.Lock()
+		defer lock.Unlock()
+
+		requests = append(requests, recordedRequest{
+			Method: r.Method,
+			Path:   r.URL.Path,
+			Header: r.Header.Clone(),
+			Body:   string(body),
+		})
+
+		w.WriteHeader(status)
+	})
+
+	var srv *httptest.Server
+	if tls {
+		srv = httptest.NewTLSServer(handler)
+	} else {
+		srv = httptest.NewServer(handler)
+	}
+	t.Cleanup(srv.Close)
+
+	return srv, &requests
+}
+
+func TestHTTPDoorActions(t *testing.T) {
+	t.Parallel()
+
+	srv, requests := newRecordingServer(t, false, http.StatusOK)
+
+	d, err := httpdoor.New("entry", httpdoor.Config{
+		HTTPMethod:      http.MethodPost,
+		HTTPURL:         srv.URL + "/door",
+		HTTPBody:        `{"door": "{{ .Door }}", "action": "{{ .Action }}"}`,
+		HTTPHeaders:     []string{"Content-Type: application/json"},
+		HTTPOpenMethod:  http.MethodPut,
+		HTTPOpenURL:     srv.URL + "/open",
+		HTTPOpenBody:    "pulse {{ .Duration.Milliseconds }}",
+		HTTPOpenHeaders: []string{"X-Pulse: 1s"},
+		HTTPBearerToken: "secret",
+		HTTPTimeout:     time.Second,
+	})
+	require.NoError(t, err)
+	defer d.Release()
+
+	ctx := context.Background()
+	require.NoError(t, d.Lock(ctx))
+	require.NoError(t, d.Unlock(ctx))
+	require.NoError(t, d.Open(ctx, 3*time.Second))
+
+	require.Len(t, *requests, 3)
+
+	lock := (*requests)[0]
+	assert.Equal(t, http.MethodPost, lock.Method)
+	assert.Equal(t, "/door", lock.Path)
+	assert.JSONEq(t, `{"door": "entry", "action": "lock"}`, lock.Body)
+	assert.Equal(t, "application/json", lock.Header.Get("Content-Type"))
+	assert.Equal(t, "Bearer secret", lock.Header.Get("Authorization"))
+
+	unlock := (*requests)[1]
+	assert.JSONEq(t, `{"door": "entry", "action": "unlock"}`, unlock.Body)
+
+	open := (*requests)[2]
+	assert.Equal(t, http.MethodPut, open.Method)
+	assert.Equal(t, "/open", open.Path)
+	assert.Equal(t, "pulse 3000", open.Body)
+	assert.Equal(t, "application/json", open.Header.Get("Content-Type"))
+	assert.Equal(t, "1s", open.Header.Get("X-Pulse"))
+}
+
+func TestHTTPDoorBasicAuth(t *testing.T) {
+	t.Parallel()
+
+	srv, requests := newRecordingServer(t, false, http.StatusOK)
+
+	d, err := httpdoor.New("", httpdoor.Config{
+		HTTPURL:      srv.URL,
+		HTTPUser:     "admin",
+		HTTPPassword: "password",
+	})
+	require.NoError(t, err)
+	defer d.Release()
+
+	require.NoError(t, d.Lock(context.Background()))
+	require.Len(t, *requests, 1)
+
+	req := &http.Request{Header: (*requests)[0].Header}
+	user, password, ok := req.BasicAuth()
+	assert.True(t, ok)
+	assert.Equal(t, "admin", user)
+	assert.Equal(t, "password", password)
+}
+
+func TestHTTPDoorExpectedStatus(t *testing.T) {
+	t.Parallel()
+
+	srv, _ := newRecordingServer(t, false, http.StatusAccepted)
+
+	d, err := httpdoor.New("", httpdoor.Config{
+		HTTPURL:            srv.URL,
+		HTTPExpectedStatus: []int{http.StatusAccepted},
+	})
+	require.NoError(t, err)
+	assert.NoError(t, d.Lock(context.Background()))
+
+	d, err = httpdoor.New("", httpdoor.Config{
+		HTTPURL:            srv.URL,
+		HTTPExpectedStatus: []int{http.StatusNoContent},
+	})
+	require.NoError(t, err)
+	assert.Error(t, d.Lock(context.Background()))
+
+	failing, _ := newRecordingServer(t, false, http.StatusInternalServerError)
+
+	d, err = httpdoor.New("", httpdoor.Config{
+		HTTPURL: failing.URL,
+	})
+	require.NoError(t, err)
+	assert.Error(t, d.Lock(context.Background()))
+}
+
+func TestHTTPDoorTimeout(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	d, err := httpdoor.New("", httpdoor.Config{
+		HTTPURL:     srv.URL,
+		HTTPTimeout: 50 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	assert.Error(t, d.Lock(context.Background()))
+}
+
+func TestHTTPDoorTLS(t *testing.T) {
+	t.Parallel()
+
+	srv, _ := newRecordingServer(t, true, http.StatusOK)
+
+	// the self-signed certificate of the test server is rejected by default.
+	d, err := httpdoor.New("", httpdoor.Config{
+		HTTPURL: srv.URL,
+	})
+	require.NoError(t, err)
+	assert.Error(t, d.Lock(context.Background()))
+
+	// skipping verification accepts the certificate.
+	d, err = httpdoor.New("", httpdoor.Config{
+		HTTPURL:                srv.URL,
+		HTTPInsecureSkipVerify: true,
+	})
+	require.NoError(t, err)
+	assert.NoError(t, d.Lock(context.Background()))
+
+	// as does trusting the certificate of the test server.
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: srv.Certificate().Raw,
+	}), 0o600))
+
+	d, err = httpdoor.New("", httpdoor.Config{
+		HTTPURL:           srv.URL,
+		HTTPCACertificate: caFile,
+	})
+	require.NoError(t, err)
+	assert.NoError(t, d.Lock(context.Background()))
+}
+
+func TestHTTPDoorDefaultBody(t *testing.T) {
+	t.Parallel()
+
+	srv, requests := newRecordingServer(t, false, http.StatusOK)
+
+	d, err := httpdoor.New("", httpdoor.Config{
+		HTTPURL:         srv.URL,
+		HTTPOpenBody:    "open",
+		HTTPOpenHeaders: []string{"Content-Type: text/plain"},
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, d.Lock(ctx))
+	require.NoError(t, d.Open(ctx, time.Second))
+	require.Len(t, *requests, 2)
+
+	// the default body is sent as JSON.
+	lock := (*requests)[0]
+	assert.JSONEq(t, `{"action": "lock"}`, lock.Body)
+	assert.Equal(t, "application/json", lock.Header.Get("Content-Type"))
+
+	open := (*requests)[1]
+	assert.Equal(t, "open", open.Body)
+	assert.Equal(t, "text/plain", open.Header.Get("Content-Type"))
+}
+
+func TestHTTPDoorPerAction(t *testing.T) {
+	t.Parallel()
+
+	srv, requests := newRecordingServer(t, true, http.StatusAccepted)
+
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(slow.Close)
+
+	insecure := true
+
+	d, err := httpdoor.New("", httpdoor.Config{
+		HTTPURL:                      srv.URL,
+		HTTPUser:                     "admin",
+		HTTPPassword:                 "password",
+		HTTPExpectedStatus:           []int{http.StatusOK},
+		HTTPTimeout:                  time.Second,
+		HTTPLockBearerToken:          "lock-token",
+		HTTPLockExpectedStatus:       []int{http.StatusAccepted},
+		HTTPLockInsecureSkipVerify:   &insecure,
+		HTTPUnlockInsecureSkipVerify: &insecure,
+		HTTPOpenURL:                  slow.URL,
+		HTTPOpenTimeout:              50 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	defer d.Release()
+
+	ctx := context.Background()
+
+	// lock uses its own credentials, status codes and TLS settings.
+	require.NoError(t, d.Lock(ctx))
+	require.Len(t, *requests, 1)
+	assert.Equal(t, "Bearer lock-token", (*requests)[0].Header.Get("Authorization"))
+
+	// unlock falls back to basic auth and the default status codes.
+	assert.Error(t, d.Unlock(ctx))
+	require.Len(t, *requests, 2)
+
+	req := &http.Request{Header: (*requests)[1].Header}
+	user, password, ok := req.BasicAuth()
+	assert.True(t, ok)
+	assert.Equal(t, "admin", user)
+	assert.Equal(t, "password", password)
+
+	// open uses a shorter timeout.
+	assert.Error(t, d.Open(ctx, time.Second))
+
+	// the certificate of the test server is still verified for open.
+	d, err = httpdoor.New("", httpdoor.Config{
+		HTTPURL:                    srv.URL,
+		HTTPLockInsecureSkipVerify: &insecure,
+	})
+	require.NoError(t, err)
+	assert.NoError(t, d.Lock(ctx))
+	assert.Error(t, d.Open(ctx, time.Second))
+}
+
+func TestHTTPDoorDecodeActionOptions(t *testing.T) {
+	t.Parallel()
+
+	var cfg httpdoor.Config
+	require.NoError(t, conf.DecodeSections([]conf.Section{
+		{
+			Name: "Door",
+			Options: conf.Options{
+				{Name: "HTTPURL", Value: "http://localhost"},
+				{Name: "HTTPOpenInsecureSkipVerify", Value: "yes"},
+				{Name: "HTTPOpenTimeout", Value: "2s"},
+				{Name: "HTTPOpenExpectedStatus", Value: "202"},
+			},
+		},
+	}, httpdoor.Spec, &cfg))
+
+	// unset boolean options are nil so they fall back to the default.
+	assert.Nil(t, cfg.HTTPLockInsecureSkipVerify)
+	require.NotNil(t, cfg.HTTPOpenInsecureSkipVerify)
+	assert.True(t, *cfg.HTTPOpenInsecureSkipVerify)
+	assert.Equal(t, 2*time.Second, cfg.HTTPOpenTimeout)
+	assert.Equal(t, []int{http.StatusAccepted}, cfg.HTTPOpenExpectedStatus)
+}
+
+func TestHTTPDoorInvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	_, err := httpdoor.New("", httpdoor.Config{})
+	assert.Error(t, err)
+
+	_, err = httpdoor.New("", httpdoor.Config{
+		HTTPURL:  "http://localhost",
+		HTTPBody: "{{ .Action",
+	})
+	assert.Error(t, err)
+
+	_, err = httpdoor.New("", httpdoor.Config{
+		HTTPURL:     "http://localhost",
+		HTTPHeaders: []string{"invalid"},
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,359 @@
+package mqttdoor
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/ppacher/system-conf/conf"
+	"github.com/tierklinik-dobersberg/cis/internal/door"
+	"github.com/tierklinik-dobersberg/cis/pkg/pkglog"
+)
+
+var log = pkglog.New("mqttdoor")
+
+// newClient creates the MQTT client used by a door. It is replaced
+// in tests.
+var newClient = mqtt.NewClient
+
+// Door controls the entry door by publishing lock, unlock and open
+// commands to a MQTT broker. If a state topic is configured, each command
+// waits until the door controller acknowledges the new state on that topic.
+type Door struct {
+	client mqtt.Client
+
+	// connected completes once the client is connected to
+	// the broker for the first time.
+	connected mqtt.Token
+
+	qos         byte
+	lockTopic   string
+	unlockTopic string
+	openTopic   string
+	stateTopic  string
+
+	// stateLock protects access to waiters and lastState.
+	stateLock sync.Mutex
+
+	// lastState holds the last state received on the state topic.
+	lastState door.State
+
+	// waiters holds a list of channels that are notified
+	// whenever a new message is received on the state topic.
+	waiters []chan string
+}
+
+// Config holds the configuration of the mqtt door driver.
+type Config struct {
+	MQTTServer           string
+	MQTTClientID         string
+	MQTTUser             string
+	MQTTPassword         string
+	MQTTQualityOfService int
+	MQTTLockTopic        string
+	MQTTUnlockTopic      string
+	MQTTOpenTopic        string
+	MQTTStateTopic       string
+}
+
+// Spec defines the options of the mqtt door driver.
+var Spec = conf.SectionSpec{
+	{
+		Name:        "MQTTServer",
+		Type:        conf.StringType,
+		Description: "The address of the MQTT broker (like tcp://mosquitto:1883)",
+	},
+	{
+		Name:        "MQTTClientID",
+		Type:        conf.StringType,
+		Description: "The client ID used when connecting to the MQTT broker. It must be unique for each door. If unset, a random client ID starting with cisd-door-<name>- is used",
+	},
+	{
+		Name:        "MQTTUser",
+		Type:        conf.StringType,
+		Description: "The username used to authenticate at the MQTT broker",
+	},
+	{
+		Name:        "MQTTPassword",
+		Type:        conf.StringType,
+		Description: "The password used to authenticate at the MQTT broker",
+	},
+	{
+		Name:        "MQTTQualityOfService",
+		Type:        conf.IntType,
+		Description: "The MQTT quality of service (0, 1 or 2) used for door commands",
+		Default:     "1",
+	},
+	{
+		Name:        "MQTTLockTopic",
+		Type:        conf.StringType,
+		Description: "The MQTT topic to publish lock commands to",
+		Default:     "cis/door/lock",
+	},
+	{
+		Name:        "MQTTUnlockTopic",
+		Type:        conf.StringType,
+		Description: "The MQTT topic to publish unlock commands to",
+		Default:     "cis/door/unlock",
+	},
+	{
+		Name:        "MQTTOpenTopic",
+		Type:        conf.StringType,
+		Description: "The MQTT topic to publish open commands to",
+		Default:     "cis/door/open",
+	},
+	{
+		Name:        "MQTTStateTopic",
+		Type:        conf.StringType,
+		Description: "An optional MQTT topic on which the door controller acknowledges state changes (locked, unlocked, open). If set, commands wait for the acknowledgement",
+	},
+}
+
+func (cfg Config) validate() error {
+	if cfg.MQTTServer == "" {
+		return fmt.Errorf("MQTTServer must be configured")
+	}
+
+	if cfg.MQTTQualityOfService < 0 || cfg.MQTTQualityOfService > 2 {
+		return fmt.Errorf("invalid MQTTQualityOfService: %d", cfg.MQTTQualityOfService)
+	}
+
+	return nil
+}
+
+// New returns a new MQTT door interfacer for cfg and starts
+// connecting to the configured broker. Connection errors are not fatal
+// as the client will continue to re-connect in the background. Commands
+// wait until the first connection has been established.
+func New(cfg Config) (*Door, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	md := newDoor(cfg)
+
+	opts := mqtt.NewClientOptions().
+		AddBroker(cfg.MQTTServer).
+		SetClientID(cfg.MQTTClientID).
+		SetUsername(cfg.MQTTUser).
+		SetPassword(cfg.MQTTPassword).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetOnConnectHandler(md.onConnect)
+
+	md.connect(newClient(opts))
+
+	return md, nil
+}
+
+// defaultClientID returns a client ID for the door name that is unique
+// for each call so multiple doors (or the door test of the configuration
+// UI) do not take over each other's broker session.
+func defaultClientID(name string) string {
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+
+	return "cisd-door-" + name + "-" + hex.EncodeToString(suffix)
+}
+
+func newDoor(cfg Config) *Door {
+	return &Door{
+		qos:         byte(cfg.MQTTQualityOfService),
+		lockTopic:   cfg.MQTTLockTopic,
+		unlockTopic: cfg.MQTTUnlockTopic,
+		openTopic:   cfg.MQTTOpenTopic,
+		stateTopic:  cfg.MQTTStateTopic,
+		lastState:   door.Unknown,
+	}
+}
+
+// connect starts connecting cli to the broker. We don't wait for the
+// connection to be established here because SetConnectRetry() causes
+// the client to retry in the background. Instead, publish waits for
+// the connect token.
+func (md *Door) connect(cli mqtt.Client) {
+	md.client = cli
+	md.connected = cli.Connect()
+}
+
+func (md *Door) onConnect(cli mqtt.Client) {
+	if md.stateTopic == "" {
+		return
+	}
+
+	// (re-)subscribe to the state topic whenever we (re-)connect
+	// to the broker.
+	token := cli.Subscribe(md.stateTopic, md.qos, md.onStateMessage)
+	if token.WaitTimeout(10*time.Second) && token.Error() != nil {
+		log.From(context.Background()).Errorf("failed to subscribe to %s: %s", md.stateTopic, token.Error())
+	}
+}
+
+func (md *Door) onStateMessage(_ mqtt.Client, msg mqtt.Message) {
+	state := strings.ToLower(strings.TrimSpace(string(msg.Payload())))
+
+	md.stateLock.Lock()
+	defer md.stateLock.Unlock()
+
+	switch door.State(state) {
+	case door.Locked, door.Unlocked, door.Open:
+		md.lastState = door.State(state)
+	default:
+		md.lastState = door.Unknown
+	}
+
+	for _, ch := range md.waiters {
+		select {
+		case ch <- state:
+		default:
+		}
+	}
+}
+
+func (md *Door) Lock(ctx context.Context) error {
+	return md.publish(ctx, md.lockTopic, string(door.Locked), map[string]any{
+		"action": "lock",
+	})
+}
+
+func (md *Door) Unlock(ctx context.Context) error {
+	return md.publish(ctx, md.unlockTopic, string(door.Unlocked), map[string]any{
+		"action": "unlock",
+	})
+}
+
+func (md *Door) Open(ctx context.Context, d time.Duration) error {
+	// like the shelly script, the duration is sent in milliseconds.
+	return md.publish(ctx, md.openTopic, string(door.Open), map[string]any{
+		"action":   "open",
+		"duration": d.Milliseconds(),
+	})
+}
+
+func (md *Door) publish(ctx context.Context, topic, expectedState string, payload map[string]any) error {
+	if topic == "" {
+		return fmt.Errorf("no MQTT topic configured for action %q", payload["action"])
+	}
+
+	// the client might not be connected yet if this is the first
+	// command after the door has been configured.
+	select {
+	case <-md.connected.Done():
+		if err := md.connected.Error(); err != nil {
+			return fmt.Errorf("failed to connect to MQTT broker: %w", err)
+		}
+	case <-ctx.Done():
+		return fmt.Errorf("not connected to MQTT broker: %w", ctx.Err())
+	}
+
+	blob, _ := json.Marshal(payload)
+
+	// register a waiter before publishing the command so we
+	// cannot miss the acknowledgement.
+	var ack chan string
+	if md.stateTopic != "" {
+		ack = make(chan string, 1)
+
+		md.stateLock.Lock()
+		md.waiters = append(md.waiters, ack)
+		md.stateLock.Unlock()
+
+		defer md.removeWaiter(ack)
+	}
+
+	token := md.client.Publish(topic, md.qos, false, blob)
+	select {
+	case <-token.Done():
+		if err := token.Error(); err != nil {
+			return fmt.Errorf("failed to publish to %s: %w", topic, err)
+		}
+	case <-ctx.Done():
+		return fmt.Errorf("failed to publish to %s: %w", topic, ctx.Err())
+	}
+
+	if ack == nil {
+		return nil
+	}
+
+	for {
+		select {
+		case state := <-ack:
+			if state == expectedState {
+				return nil
+			}
+		case <-ctx.Done():
+			return fmt.Errorf("door did not acknowledge state %q: %w", expectedState, ctx.Err())
+		}
+	}
+}
+
+func (md *Door) removeWaiter(ch chan string) {
+	md.stateLock.Lock()
+	defer md.stateLock.Unlock()
+
+	for idx, w := range md.waiters {
+		if w == ch {
+			md.waiters = append(md.waiters[:idx], md.waiters[idx+1:]...)
+
+			return
+		}
+	}
+}
+
+// ReportState implements StateReporter and returns the last state
+// received on the state topic.
+func (md *Door) ReportState(context.Context) (door.State, error) {
+	md.stateLock.Lock()
+	defer md.stateLock.Unlock()
+
+	return md.lastState, nil
+}
+
+func (md *Door) Release() {
+	md.client.Disconnect(250)
+}
+
+var (
+	_ door.Interfacer    = (*Door)(nil)
+	_ door.StateReporter = (*Door)(nil)
+)
+
+func init() {
+	door.MustRegisterDriver(door.Driver{
+		Type:        "mqtt",
+		DisplayName: "MQTT",
+		Spec:        Spec,
+		Validate: func(sec conf.Section) error {
+			var cfg Config
+			if err := conf.DecodeSections([]conf.Section{sec}, Spec, &cfg); err != nil {
+				return err
+			}
+
+			return cfg.validate()
+		},
+		Unique: []string{"MQTTClientID"},
+		New: func(doorCfg door.DoorConfig, sec conf.Section) (door.Interfacer, error) {
+			var cfg Config
+			if err := conf.DecodeSections([]conf.Section{sec}, Spec, &cfg); err != nil {
+				return nil, err
+			}
+
+			if cfg.MQTTClientID == "" {
+				cfg.MQTTClientID = defaultClientID(doorCfg.Name)
+			}
+
+			md, err := New(cfg)
+			if err != nil {
+				return nil, err
+			}
+
+			return md, nil
+		},
+	})
+}
@@ -0,0 +1,438 @@
+package mqttdoor
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/ppacher/system-conf/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tierklinik-dobersberg/cis/internal/cfgspec"
+	"github.com/tierklinik-dobersberg/cis/internal/door"
+	"github.com/tierklinik-dobersberg/cis/internal/holidays"
+	"github.com/tierklinik-dobersberg/cis/internal/openinghours"
+	"github.com/tierklinik-dobersberg/cis/pkg/clock"
+	"github.com/tierklinik-dobersberg/cis/runtime"
+	"github.com/tierklinik-dobersberg/cis/runtime/configprovider/memoryprovider"
+)
+
+// fakeToken is a mqtt.Token that completes when complete is called.
+type fakeToken struct {
+	done chan struct{}
+	err  error
+}
+
+func newFakeToken() *fakeToken {
+	return &fakeToken{done: make(chan struct{})}
+}
+
+func completedToken(err error) *fakeToken {
+	t := newFakeToken()
+	t.complete(err)
+
+	return t
+}
+
+func (t *fakeToken) complete(err error) {
+	t.err = err
+	close(t.done)
+}
+
+func (t *fakeToken) Wait() bool {
+	<-t.done
+
+	return true
+}
+
+func (t *fakeToken) WaitTimeout(d time.Duration) bool {
+	select {
+	case <-t.done:
+		return true
+	case <-time.After(d):
+		return false
+	}
+}
+
+func (t *fakeToken) Done() <-chan struct{} { return t.done }
+func (t *fakeToken) Error() error          { return t.err }
+
+type fakeMessage struct {
+	mqtt.Message
+
+	topic   string
+	payload []byte
+}
+
+func (m *fakeMessage) Topic() string   { return m.topic }
+func (m *fakeMessage) Payload() []byte { return m.payload }
+
+type fakePublish struct {
+	topic   string
+	payload map[string]any
+}
+
+// fakeMqttClient is a mqtt.Client that records all published messages.
+// If acknowledge is set, each command is acknowledged on the state topic
+// like a real door controller would do.
+type fakeMqttClient struct {
+	mqtt.Client
+
+	connected   *fakeToken
+	stateTopic  string
+	published   chan fakePublish
+	onConnect   func(mqtt.Client)
+	acknowledge map[string]door.State
+
+	lock          sync.Mutex
+	subscriptions map[string]mqtt.MessageHandler
+}
+
+func newFakeClient(stateTopic string, onConnect mqtt.OnConnectHandler) *fakeMqttClient {
+	return &fakeMqttClient{
+		connected:     newFakeToken(),
+		stateTopic:    stateTopic,
+		published:     make(chan fakePublish, 200),
+		onConnect:     onConnect,
+		acknowledge:   make(map[string]door.State),
+		subscriptions: make(map[string]mqtt.MessageHandler),
+	}
+}
+
+// newFakeMqttClient connects md to a new fake client.
+func newFakeMqttClient(md *Door) *fakeMqttClient {
+	cli := newFakeClient(md.stateTopic, md.onConnect)
+
+	md.connect(cli)
+
+	return cli
+}
+
+// establish completes the connection to the fake broker.
+func (cli *fakeMqttClient) establish() {
+	cli.connected.complete(nil)
+	cli.onConnect(cli)
+}
+
+func (cli *fakeMqttClient) Connect() mqtt.Token { return cli.connected }
+func (cli *fakeMqttClient) Disconnect(uint)     {}
+
+func (cli *fakeMqttClient) Publish(topic string, _ byte, _ bool, payload interface{}) mqtt.Token {
+	var msg map[string]any
+	if err := json.Unmarshal(payload.([]byte), &msg); err != nil {
+		return completedToken(err)
+	}
+
+	cli.published <- fakePublish{topic: topic, payload: msg}
+
+	if state, ok := cli.acknowledge[topic]; ok {
+		cli.deliver(cli.stateTopic, string(state))
+	}
+
+	return completedToken(nil)
+}
+
+func (cli *fakeMqttClient) Subscribe(topic string, _ byte, callback mqtt.MessageHandler) mqtt.Token {
+	cli.lock.Lock()
+	defer cli.lock.Unlock()
+
+	cli.subscriptions[topic] = callback
+
+	return completedToken(nil)
+}
+
+// deliver delivers payload to the subscriber of topic, if any.
+func (cli *fakeMqttClient) deliver(topic string, payload string) {
+	cli.lock.Lock()
+	callback := cli.subscriptions[topic]
+	cli.lock.Unlock()
+
+	if callback != nil {
+		callback(cli, &fakeMessage{topic: topic, payload: []byte(payload)})
+	}
+}
+
+func (cli *fakeMqttClient) expectPublish(t *testing.T, topic string) map[string]any {
+	t.Helper()
+
+	select {
+	case msg := <-cli.published:
+		assert.Equal(t, topic, msg.topic)
+
+		return msg.payload
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected a message on %s but got none", topic)
+	}
+
+	return nil
+}
+
+func (cli *fakeMqttClient) expectNoPublish(t *testing.T) {
+	t.Helper()
+
+	select {
+	case msg := <-cli.published:
+		t.Fatalf("unexpected message on %s", msg.topic)
+	default:
+	}
+}
+
+type noHolidays struct{}
+
+func (noHolidays) ForYear(context.Context, int) ([]holidays.Holiday, error) {
+	return nil, nil
+}
+
+func (noHolidays) IsHoliday(context.Context, time.Time) (bool, error) {
+	return false, nil
+}
+
+var testMqttConfig = Config{
+	MQTTServer:           "tcp://localhost:1883",
+	MQTTQualityOfService: 1,
+	MQTTLockTopic:        "door/lock",
+	MQTTUnlockTopic:      "door/unlock",
+	MQTTOpenTopic:        "door/open",
+	MQTTStateTopic:       "door/state",
+}
+
+func TestMqttDoorWaitsForConnection(t *testing.T) {
+	t.Parallel()
+
+	cfg := testMqttConfig
+	cfg.MQTTStateTopic = ""
+
+	md := newDoor(cfg)
+	cli := newFakeMqttClient(md)
+
+	// commands are not published before the client is connected
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, md.Lock(ctx), context.DeadlineExceeded)
+	cli.expectNoPublish(t)
+
+	cli.establish()
+
+	require.NoError(t, md.Lock(context.Background()))
+	assert.Equal(t, map[string]any{"action": "lock"}, cli.expectPublish(t, "door/lock"))
+
+	// without a state topic the actual state is unknown
+	state, err := md.ReportState(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, door.Unknown, state)
+}
+
+func TestMqttDoorAcknowledge(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	md := newDoor(testMqttConfig)
+	cli := newFakeMqttClient(md)
+	cli.acknowledge["door/lock"] = door.Locked
+	cli.acknowledge["door/unlock"] = door.Unlocked
+	cli.establish()
+
+	require.NoError(t, md.Unlock(ctx))
+	assert.Equal(t, map[string]any{"action": "unlock"}, cli.expectPublish(t, "door/unlock"))
+
+	state, err := md.ReportState(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, door.Unlocked, state)
+
+	require.NoError(t, md.Lock(ctx))
+	cli.expectPublish(t, "door/lock")
+
+	state, err = md.ReportState(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, door.Locked, state)
+
+	// the door controller does not acknowledge open commands
+	openCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+
+	err = md.Open(openCtx, 2*time.Second)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, map[string]any{"action": "open", "duration": float64(2000)}, cli.expectPublish(t, "door/open"))
+
+	// unknown states are reported as such
+	cli.deliver("door/state", "jammed")
+
+	state, err = md.ReportState(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, door.Unknown, state)
+}
+
+// TestMqttDoorDriftReconciliation runs the door scheduler against a
+// MQTT door that acknowledges all commands. It cannot run in parallel
+// as it replaces newClient.
+func TestMqttDoorDriftReconciliation(t *testing.T) {
+	clients := make(chan *fakeMqttClient, 1)
+
+	newClient = func(opts *mqtt.ClientOptions) mqtt.Client {
+		cli := newFakeClient("door/state", opts.OnConnect)
+		cli.acknowledge["door/lock"] = door.Locked
+		cli.acknowledge["door/unlock"] = door.Unlocked
+
+		clients <- cli
+
+		return cli
+	}
+	t.Cleanup(func() {
+		newClient = mqtt.NewClient
+	})
+
+	// the scheduler waits on two timers while idle.
+	const schedulerTimers = 2
+
+	ctx := context.Background()
+
+	loc, err := time.LoadLocation("Europe/Vienna")
+	require.NoError(t, err)
+
+	clk := clock.NewFake(time.Date(2024, time.January, 8, 7, 0, 0, 0, loc))
+
+	ohCtrl, err := openinghours.NewController(cfgspec.Config{
+		TimeZone: "Europe/Vienna",
+	}, noHolidays{}, clk)
+	require.NoError(t, err)
+
+	cs := new(runtime.ConfigSchema)
+	cs.SetProvider(memoryprovider.New(conf.Section{
+		Name: "Door",
+		Options: conf.Options{
+			{Name: "Name", Value: door.DefaultDoorName},
+			{Name: "Type", Value: "mqtt"},
+			{Name: "MQTTServer", Value: testMqttConfig.MQTTServer},
+			{Name: "MQTTQualityOfService", Value: "1"},
+			{Name: "MQTTLockTopic", Value: testMqttConfig.MQTTLockTopic},
+			{Name: "MQTTUnlockTopic", Value: testMqttConfig.MQTTUnlockTopic},
+			{Name: "MQTTOpenTopic", Value: testMqttConfig.MQTTOpenTopic},
+			{Name: "MQTTStateTopic", Value: testMqttConfig.MQTTStateTopic},
+		},
+	}))
+
+	mng, err := door.NewManager(ctx, ohCtrl, cs, nil, nil, nil)
+	require.NoError(t, err)
+
+	dc, err := mng.Get(door.DefaultDoorName)
+	require.NoError(t, err)
+
+	cli := <-clients
+	cli.establish()
+
+	require.NoError(t, mng.Start())
+	t.Cleanup(func() {
+		_ = mng.Stop()
+	})
+
+	advance := func(d time.Duration) {
+		clk.BlockUntil(schedulerTimers)
+		clk.Advance(d)
+		clk.BlockUntil(schedulerTimers)
+	}
+
+	// without opening hours the door is locked all day. The scheduler
+	// triggers one second after being started.
+	advance(time.Second)
+	cli.expectPublish(t, "door/lock")
+
+	// the lock command is re-sent every minute until the maximum
+	// number of tries is reached.
+	for i := 1; i < 60; i++ {
+		advance(time.Minute)
+		cli.expectPublish(t, "door/lock")
+	}
+
+	// the actual state is sensed before each attempt.
+	actual, _ := dc.Actual()
+	assert.Equal(t, door.Locked, actual)
+
+	advance(time.Minute)
+	cli.expectNoPublish(t)
+
+	// someone unlocked the door manually.
+	cli.deliver("door/state", string(door.Unlocked))
+
+	advance(time.Minute)
+	cli.expectPublish(t, "door/lock")
+
+	actual, _ = dc.Actual()
+	assert.Equal(t, door.Unlocked, actual)
+
+	// retries start over after a drift and the lock has been acknowledged.
+	advance(time.Minute)
+	cli.expectPublish(t, "door/lock")
+
+	actual, _ = dc.Actual()
+	assert.Equal(t, door.Locked, actual)
+}
+
+// TestMqttDoorClientIDs ensures doors do not share a MQTT client ID. It
+// cannot run in parallel as it replaces newClient.
+func TestMqttDoorClientIDs(t *testing.T) {
+	clientIDs := make(chan string, 10)
+
+	newClient = func(opts *mqtt.ClientOptions) mqtt.Client {
+		clientIDs <- opts.ClientID
+
+		return newFakeClient("", opts.OnConnect)
+	}
+	t.Cleanup(func() {
+		newClient = mqtt.NewClient
+	})
+
+	ctx := context.Background()
+
+	ohCtrl, err := openinghours.NewController(cfgspec.Config{
+		TimeZone: "Europe/Vienna",
+	}, noHolidays{}, clock.NewFake(time.Now()))
+	require.NoError(t, err)
+
+	section := func(name, clientID string) conf.Section {
+		sec := conf.Section{
+			Name: "Door",
+			Options: conf.Options{
+				{Name: "Name", Value: name},
+				{Name: "Type", Value: "mqtt"},
+				{Name: "MQTTServer", Value: testMqttConfig.MQTTServer},
+			},
+		}
+
+		if clientID != "" {
+			sec.Options = append(sec.Options, conf.Option{Name: "MQTTClientID", Value: clientID})
+		}
+
+		return sec
+	}
+
+	// without an explicit client ID each door uses its own one.
+	cs := new(runtime.ConfigSchema)
+	cs.SetProvider(memoryprovider.New(section("entry", ""), section("garage", "")))
+
+	mng, err := door.NewManager(ctx, ohCtrl, cs, nil, nil, nil)
+	require.NoError(t, err)
+
+	first, second := <-clientIDs, <-clientIDs
+	assert.NotEqual(t, first, second)
+	for _, id := range []string{first, second} {
+		assert.True(t, strings.HasPrefix(id, "cisd-door-entry-") || strings.HasPrefix(id, "cisd-door-garage-"), id)
+	}
+
+	assert.NoError(t, mng.Validate(ctx, runtime.Section{ID: "new", Section: section("office", "cisd-office")}))
+
+	// explicit client IDs must be unique.
+	cs = new(runtime.ConfigSchema)
+	cs.SetProvider(memoryprovider.New(section("entry", "cisd-entry")))
+
+	mng, err = door.NewManager(ctx, ohCtrl, cs, nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "cisd-entry", <-clientIDs)
+
+	assert.ErrorContains(t, mng.Validate(ctx, runtime.Section{ID: "new", Section: section("garage", "cisd-entry")}), "already used")
+}
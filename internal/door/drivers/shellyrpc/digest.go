@@ -0,0 +1,172 @@
+package shellyrpc
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// digestTransport is a http.RoundTripper that implements HTTP digest
+// authentication (RFC 7616) as used by Shelly Gen2 devices. It supports
+// the MD5 and SHA-256 algorithms with qop=auth.
+type digestTransport struct {
+	username string
+	password string
+	next     http.RoundTripper
+
+	// lock protects access to nc.
+	lock sync.Mutex
+	nc   int
+}
+
+func (t *digestTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// we need to be able to replay the request body after
+	// receiving the authentication challenge.
+	retry := req.Clone(req.Context())
+	if req.Body != nil && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		retry.Body = body
+	}
+
+	res, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode != http.StatusUnauthorized {
+		return res, nil
+	}
+
+	challenge := res.Header.Get("WWW-Authenticate")
+	if !strings.HasPrefix(strings.ToLower(challenge), "digest ") {
+		return res, nil
+	}
+
+	_, _ = io.Copy(io.Discard, res.Body)
+	res.Body.Close()
+
+	authorization, err := t.authorize(retry, parseDigestChallenge(challenge[len("digest "):]))
+	if err != nil {
+		return nil, err
+	}
+
+	retry.Header.Set("Authorization", authorization)
+
+	return t.next.RoundTrip(retry)
+}
+
+func (t *digestTransport) authorize(req *http.Request, params map[string]string) (string, error) {
+	var newHash func() hash.Hash
+
+	algorithm := params["algorithm"]
+	switch strings.ToUpper(algorithm) {
+	case "", "MD5":
+		newHash = md5.New
+	case "SHA-256":
+		newHash = sha256.New
+	default:
+		return "", fmt.Errorf("unsupported digest algorithm %q", algorithm)
+	}
+
+	digest := func(parts ...string) string {
+		h := newHash()
+		_, _ = io.WriteString(h, strings.Join(parts, ":"))
+
+		return hex.EncodeToString(h.Sum(nil))
+	}
+
+	t.lock.Lock()
+	t.nc++
+	nc := fmt.Sprintf("%08x", t.nc)
+	t.lock.Unlock()
+
+	cnonceBytes := make([]byte, 8)
+	if _, err := rand.Read(cnonceBytes); err != nil {
+		return "", err
+	}
+	cnonce := hex.EncodeToString(cnonceBytes)
+
+	uri := req.URL.RequestURI()
+	ha1 := digest(t.username, params["realm"], t.password)
+	ha2 := digest(req.Method, uri)
+
+	var response string
+	if params["qop"] == "" {
+		response = digest(ha1, params["nonce"], ha2)
+	} else {
+		response = digest(ha1, params["nonce"], nc, cnonce, "auth", ha2)
+	}
+
+	fields := []string{
+		fmt.Sprintf("username=%q", t.username),
+		fmt.Sprintf("realm=%q", params["realm"]),
+		fmt.Sprintf("nonce=%q", params["nonce"]),
+		fmt.Sprintf("uri=%q", uri),
+		fmt.Sprintf("response=%q", response),
+	}
+
+	if algorithm != "" {
+		fields = append(fields, "algorithm="+algorithm)
+	}
+
+	if params["qop"] != "" {
+		fields = append(fields, "qop=auth", "nc="+nc, fmt.Sprintf("cnonce=%q", cnonce))
+	}
+
+	if opaque, ok := params["opaque"]; ok {
+		fields = append(fields, fmt.Sprintf("opaque=%q", opaque))
+	}
+
+	return "Digest " + strings.Join(fields, ", "), nil
+}
+
+// parseDigestChallenge parses the parameters of a digest
+// WWW-Authenticate header.
+func parseDigestChallenge(s string) map[string]string {
+	params := make(map[string]string)
+
+	for _, part := range splitDigestParams(s) {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+
+		params[strings.ToLower(strings.TrimSpace(key))] = strings.Trim(strings.TrimSpace(value), `"`)
+	}
+
+	return params
+}
+
+// splitDigestParams splits s at commas that are not part of
+// a quoted string.
+func splitDigestParams(s string) []string {
+	var (
+		parts  []string
+		quoted bool
+		start  int
+	)
+
+	for idx, r := range s {
+		switch r {
+		case '"':
+			quoted = !quoted
+		case ',':
+			if !quoted {
+				parts = append(parts, s[start:idx])
+				start = idx + 1
+			}
+		}
+	}
+
+	return append(parts, s[start:])
+}
@@ -0,0 +1,380 @@
+package shellyrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ppacher/system-conf/conf"
+	"github.com/tierklinik-dobersberg/cis/internal/door"
+	"github.com/tierklinik-dobersberg/cis/pkg/pkglog"
+)
+
+var log = pkglog.New("shellyrpc")
+
+// Door controls the door using the Gen2 RPC API of a Shelly
+// relay (like the Shelly Pro 2). It implements the same behaviour as
+// contrib/door/shelly-door-controller.js without requiring a script to
+// be installed on the device.
+//
+// Lock and unlock each pulse a dedicated switch. If interlock is enabled
+// the other switch is turned off first. Open keeps the unlock switch
+// turned on for the requested duration.
+type Door struct {
+	url    string
+	client *http.Client
+
+	lockSwitch   int
+	unlockSwitch int
+	pulse        time.Duration
+	interlock    bool
+
+	// lock protects access to openTimer and ensures only one
+	// command is sent to the device at a time.
+	lock sync.Mutex
+
+	// openTimer is set while the door is held open and turns
+	// the unlock switch off once it fires.
+	openTimer *time.Timer
+
+	// requestID is used as the ID of JSON-RPC requests.
+	requestID int
+}
+
+// Config holds the configuration of the shelly-rpc door
+// driver.
+type Config struct {
+	ShellyRPCAddress      string
+	ShellyRPCUser         string
+	ShellyRPCPassword     string
+	ShellyRPCLockSwitch   int
+	ShellyRPCUnlockSwitch int
+	ShellyRPCPulse        time.Duration
+	ShellyRPCInterlock    bool
+	ShellyRPCTimeout      time.Duration
+}
+
+// Spec defines the options of the shelly-rpc door driver.
+var Spec = conf.SectionSpec{
+	{
+		Name:        "ShellyRPCAddress",
+		Type:        conf.StringType,
+		Description: "The address of the Shelly Gen2 device (like http://192.168.0.10)",
+	},
+	{
+		Name:        "ShellyRPCUser",
+		Type:        conf.StringType,
+		Description: "The username used for digest authentication at the Shelly device",
+		Default:     "admin",
+	},
+	{
+		Name:        "ShellyRPCPassword",
+		Type:        conf.StringType,
+		Description: "The password used for digest authentication at the Shelly device. Leave empty if authentication is disabled",
+	},
+	{
+		Name:        "ShellyRPCLockSwitch",
+		Type:        conf.IntType,
+		Description: "The ID of the switch that is pulsed to lock the door",
+		Default:     "1",
+	},
+	{
+		Name:        "ShellyRPCUnlockSwitch",
+		Type:        conf.IntType,
+		Description: "The ID of the switch that is pulsed to unlock the door",
+		Default:     "0",
+	},
+	{
+		Name:        "ShellyRPCPulse",
+		Type:        conf.DurationType,
+		Description: "How long the lock or unlock switch is turned on (toggle_after)",
+		Default:     defaultPulse.String(),
+	},
+	{
+		Name:        "ShellyRPCInterlock",
+		Type:        conf.BoolType,
+		Description: "Whether or not the other switch is turned off before a switch is turned on",
+		Default:     "yes",
+	},
+	{
+		Name:        "ShellyRPCTimeout",
+		Type:        conf.DurationType,
+		Description: "The timeout for RPC requests to the Shelly device",
+		Default:     "5s",
+	},
+}
+
+// defaultPulse is used if ShellyRPCPulse is not set. Without a pulse
+// length the switch would never be turned off again.
+const defaultPulse = 2 * time.Second
+
+// New returns a new Shelly RPC door interfacer for cfg.
+func New(cfg Config) (*Door, error) {
+	if cfg.ShellyRPCAddress == "" {
+		return nil, fmt.Errorf("ShellyRPCAddress must be configured")
+	}
+
+	if cfg.ShellyRPCPulse < 0 {
+		return nil, fmt.Errorf("ShellyRPCPulse must be positive")
+	}
+
+	if cfg.ShellyRPCPulse == 0 {
+		cfg.ShellyRPCPulse = defaultPulse
+	}
+
+	if cfg.ShellyRPCLockSwitch == cfg.ShellyRPCUnlockSwitch {
+		return nil, fmt.Errorf("ShellyRPCLockSwitch and ShellyRPCUnlockSwitch must be different")
+	}
+
+	url := strings.TrimSuffix(cfg.ShellyRPCAddress, "/")
+	if !strings.Contains(url, "://") {
+		url = "http://" + url
+	}
+
+	var transport http.RoundTripper = http.DefaultTransport
+	if cfg.ShellyRPCPassword != "" {
+		transport = &digestTransport{
+			username: cfg.ShellyRPCUser,
+			password: cfg.ShellyRPCPassword,
+			next:     transport,
+		}
+	}
+
+	return &Door{
+		url: url + "/rpc",
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   cfg.ShellyRPCTimeout,
+		},
+		lockSwitch:   cfg.ShellyRPCLockSwitch,
+		unlockSwitch: cfg.ShellyRPCUnlockSwitch,
+		pulse:        cfg.ShellyRPCPulse,
+		interlock:    cfg.ShellyRPCInterlock,
+	}, nil
+}
+
+func (rd *Door) Lock(ctx context.Context) error {
+	return rd.toggle(ctx, rd.lockSwitch, rd.unlockSwitch)
+}
+
+func (rd *Door) Unlock(ctx context.Context) error {
+	return rd.toggle(ctx, rd.unlockSwitch, rd.lockSwitch)
+}
+
+// toggle pulses the switch id for the configured pulse length.
+func (rd *Door) toggle(ctx context.Context, id, other int) error {
+	rd.lock.Lock()
+	defer rd.lock.Unlock()
+
+	if rd.openTimer != nil {
+		return door.ErrDoorBusy
+	}
+
+	if rd.interlock {
+		if err := rd.setSwitch(ctx, other, false, 0); err != nil {
+			return err
+		}
+	}
+
+	if err := rd.setSwitch(ctx, id, true, rd.pulse); err != nil {
+		return err
+	}
+
+	return rd.confirm(ctx, id, other)
+}
+
+func (rd *Door) Open(ctx context.Context, d time.Duration) error {
+	rd.lock.Lock()
+	defer rd.lock.Unlock()
+
+	if rd.openTimer != nil {
+		return door.ErrDoorBusy
+	}
+
+	if rd.interlock {
+		if err := rd.setSwitch(ctx, rd.lockSwitch, false, 0); err != nil {
+			return err
+		}
+	}
+
+	if err := rd.setSwitch(ctx, rd.unlockSwitch, true, 0); err != nil {
+		return err
+	}
+
+	rd.openTimer = time.AfterFunc(d, rd.closeAfterOpen)
+
+	return rd.confirm(ctx, rd.unlockSwitch, rd.lockSwitch)
+}
+
+func (rd *Door) closeAfterOpen() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	rd.lock.Lock()
+	defer rd.lock.Unlock()
+
+	rd.openTimer = nil
+
+	if err := rd.setSwitch(ctx, rd.unlockSwitch, false, 0); err != nil {
+		log.From(ctx).Errorf("failed to turn off shelly switch %d after opening the door: %s", rd.unlockSwitch, err)
+	}
+}
+
+// confirm reads the status of the switches and ensures switch id
+// is turned on and, if interlock is enabled, other is turned off.
+func (rd *Door) confirm(ctx context.Context, id, other int) error {
+	on, err := rd.switchOutput(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !on {
+		return fmt.Errorf("shelly switch %d did not turn on", id)
+	}
+
+	if !rd.interlock {
+		return nil
+	}
+
+	on, err = rd.switchOutput(ctx, other)
+	if err != nil {
+		return err
+	}
+
+	if on {
+		return fmt.Errorf("shelly switch %d did not turn off", other)
+	}
+
+	return nil
+}
+
+func (rd *Door) setSwitch(ctx context.Context, id int, on bool, toggleAfter time.Duration) error {
+	params := map[string]any{
+		"id": id,
+		"on": on,
+	}
+
+	if toggleAfter > 0 {
+		params["toggle_after"] = toggleAfter.Seconds()
+	}
+
+	return rd.call(ctx, "Switch.Set", params, nil)
+}
+
+func (rd *Door) switchOutput(ctx context.Context, id int) (bool, error) {
+	var status struct {
+		Output bool `json:"output"`
+	}
+
+	if err := rd.call(ctx, "Switch.GetStatus", map[string]any{"id": id}, &status); err != nil {
+		return false, err
+	}
+
+	return status.Output, nil
+}
+
+// call performs a JSON-RPC call against the Shelly device and decodes
+// the result into result, if non-nil.
+func (rd *Door) call(ctx context.Context, method string, params map[string]any, result any) error {
+	rd.requestID++
+
+	blob, err := json.Marshal(map[string]any{
+		"id":     rd.requestID,
+		"method": method,
+		"params": params,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rd.url, bytes.NewReader(blob))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := rd.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: failed to perform request: %w", method, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("%s: unexpected status code: %s", method, res.Status)
+	}
+
+	var response struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
+		return fmt.Errorf("%s: failed to decode response: %w", method, err)
+	}
+
+	if response.Error != nil {
+		return fmt.Errorf("%s: %s (%d)", method, response.Error.Message, response.Error.Code)
+	}
+
+	if result != nil {
+		if err := json.Unmarshal(response.Result, result); err != nil {
+			return fmt.Errorf("%s: failed to decode result: %w", method, err)
+		}
+	}
+
+	return nil
+}
+
+func (rd *Door) Release() {
+	rd.lock.Lock()
+	timer := rd.openTimer
+	rd.lock.Unlock()
+
+	// make sure we don't leave the door open when being released.
+	if timer != nil && timer.Stop() {
+		rd.closeAfterOpen()
+	}
+
+	rd.client.CloseIdleConnections()
+}
+
+var _ door.Interfacer = (*Door)(nil)
+
+func init() {
+	door.MustRegisterDriver(door.Driver{
+		Type:        "shelly-rpc",
+		DisplayName: "Shelly Gen2 (RPC)",
+		Spec:        Spec,
+		Validate: func(sec conf.Section) error {
+			var cfg Config
+			if err := conf.DecodeSections([]conf.Section{sec}, Spec, &cfg); err != nil {
+				return err
+			}
+
+			_, err := New(cfg)
+
+			return err
+		},
+		New: func(_ door.DoorConfig, sec conf.Section) (door.Interfacer, error) {
+			var cfg Config
+			if err := conf.DecodeSections([]conf.Section{sec}, Spec, &cfg); err != nil {
+				return nil, err
+			}
+
+			rd, err := New(cfg)
+			if err != nil {
+				return nil, err
+			}
+
+			return rd, nil
+		},
+	})
+}
@@ -0,0 +1,235 @@
+package shellyrpc_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tierklinik-dobersberg/cis/internal/door"
+	"github.com/tierklinik-dobersberg/cis/internal/door/drivers/shellyrpc"
+)
+
+// fakeShelly emulates the Switch RPC methods of a Shelly Gen2 device
+// protected by SHA-256 digest authentication.
+type fakeShelly struct {
+	lock     sync.Mutex
+	password string
+	switches map[int]bool
+	calls    []string
+}
+
+func (fs *fakeShelly) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !fs.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Digest qop="auth", realm="shellypro2-test", nonce="1234", algorithm=SHA-256`)
+		w.WriteHeader(http.StatusUnauthorized)
+
+		return
+	}
+
+	var req struct {
+		ID     int    `json:"id"`
+		Method string `json:"method"`
+		Params struct {
+			ID          int     `json:"id"`
+			On          bool    `json:"on"`
+			ToggleAfter float64 `json:"toggle_after"`
+		} `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+
+	fs.calls = append(fs.calls, fmt.Sprintf("%s(%d,%v,%v)", req.Method, req.Params.ID, req.Params.On, req.Params.ToggleAfter))
+
+	var result any
+	switch req.Method {
+	case "Switch.Set":
+		result = map[string]any{"was_on": fs.switches[req.Params.ID]}
+		fs.switches[req.Params.ID] = req.Params.On
+	case "Switch.GetStatus":
+		result = map[string]any{"id": req.Params.ID, "output": fs.switches[req.Params.ID]}
+		fs.calls = fs.calls[:len(fs.calls)-1]
+	default:
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"id":    req.ID,
+			"error": map[string]any{"code": 404, "message": "No handler for " + req.Method},
+		})
+
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"id":     req.ID,
+		"result": result,
+	})
+}
+
+var digestParamRe = regexp.MustCompile(`(\w+)="?([^",]*)"?`)
+
+func (fs *fakeShelly) authorized(r *http.Request) bool {
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, "Digest ") {
+		return false
+	}
+
+	params := make(map[string]string)
+	for _, m := range digestParamRe.FindAllStringSubmatch(header[len("Digest "):], -1) {
+		params[m[1]] = m[2]
+	}
+
+	sum := func(s string) string {
+		h := sha256.Sum256([]byte(s))
+
+		return hex.EncodeToString(h[:])
+	}
+
+	ha1 := sum("admin:shellypro2-test:" + fs.password)
+	ha2 := sum(r.Method + ":" + params["uri"])
+	expected := sum(strings.Join([]string{ha1, "1234", params["nc"], params["cnonce"], "auth", ha2}, ":"))
+
+	return params["username"] == "admin" && params["response"] == expected
+}
+
+func newShellyDoor(t *testing.T, fs *fakeShelly, interlock bool) *shellyrpc.Door {
+	t.Helper()
+
+	srv := httptest.NewServer(fs)
+	t.Cleanup(srv.Close)
+
+	d, err := shellyrpc.New(shellyrpc.Config{
+		ShellyRPCAddress:      srv.URL,
+		ShellyRPCUser:         "admin",
+		ShellyRPCPassword:     fs.password,
+		ShellyRPCLockSwitch:   1,
+		ShellyRPCUnlockSwitch: 0,
+		ShellyRPCPulse:        2 * time.Second,
+		ShellyRPCInterlock:    interlock,
+		ShellyRPCTimeout:      time.Second,
+	})
+	require.NoError(t, err)
+
+	return d
+}
+
+func TestShellyRPCDoorLockUnlock(t *testing.T) {
+	t.Parallel()
+
+	fs := &fakeShelly{password: "secret", switches: map[int]bool{0: true}}
+	d := newShellyDoor(t, fs, true)
+
+	ctx := context.Background()
+	require.NoError(t, d.Lock(ctx))
+	require.NoError(t, d.Unlock(ctx))
+
+	assert.Equal(t, []string{
+		"Switch.Set(0,false,0)",
+		"Switch.Set(1,true,2)",
+		"Switch.Set(1,false,0)",
+		"Switch.Set(0,true,2)",
+	}, fs.calls)
+}
+
+func TestShellyRPCDoorWithoutInterlock(t *testing.T) {
+	t.Parallel()
+
+	fs := &fakeShelly{password: "secret", switches: map[int]bool{}}
+	d := newShellyDoor(t, fs, false)
+
+	require.NoError(t, d.Lock(context.Background()))
+
+	assert.Equal(t, []string{"Switch.Set(1,true,2)"}, fs.calls)
+}
+
+func TestShellyRPCDoorOpen(t *testing.T) {
+	t.Parallel()
+
+	fs := &fakeShelly{password: "secret", switches: map[int]bool{}}
+	d := newShellyDoor(t, fs, true)
+
+	ctx := context.Background()
+	require.NoError(t, d.Open(ctx, 50*time.Millisecond))
+
+	// further commands are rejected while the door is held open.
+	assert.ErrorIs(t, d.Lock(ctx), door.ErrDoorBusy)
+
+	assert.Eventually(t, func() bool {
+		fs.lock.Lock()
+		defer fs.lock.Unlock()
+
+		return len(fs.calls) == 3
+	}, time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, []string{
+		"Switch.Set(1,false,0)",
+		"Switch.Set(0,true,0)",
+		"Switch.Set(0,false,0)",
+	}, fs.calls)
+
+	require.NoError(t, d.Lock(ctx))
+}
+
+func TestShellyRPCDoorAuthFailure(t *testing.T) {
+	t.Parallel()
+
+	fs := &fakeShelly{password: "secret", switches: map[int]bool{}}
+
+	srv := httptest.NewServer(fs)
+	t.Cleanup(srv.Close)
+
+	d, err := shellyrpc.New(shellyrpc.Config{
+		ShellyRPCAddress:      srv.URL,
+		ShellyRPCUser:         "admin",
+		ShellyRPCPassword:     "wrong",
+		ShellyRPCLockSwitch:   1,
+		ShellyRPCUnlockSwitch: 0,
+		ShellyRPCTimeout:      time.Second,
+	})
+	require.NoError(t, err)
+
+	assert.Error(t, d.Lock(context.Background()))
+	assert.Empty(t, fs.calls)
+}
+
+func TestShellyRPCDoorPulse(t *testing.T) {
+	t.Parallel()
+
+	fs := &fakeShelly{password: "secret", switches: map[int]bool{}}
+
+	srv := httptest.NewServer(fs)
+	t.Cleanup(srv.Close)
+
+	cfg := shellyrpc.Config{
+		ShellyRPCAddress:      srv.URL,
+		ShellyRPCUser:         "admin",
+		ShellyRPCPassword:     fs.password,
+		ShellyRPCLockSwitch:   1,
+		ShellyRPCUnlockSwitch: 0,
+		ShellyRPCTimeout:      time.Second,
+	}
+
+	// without a pulse length the switch would stay on forever.
+	d, err := shellyrpc.New(cfg)
+	require.NoError(t, err)
+	require.NoError(t, d.Lock(context.Background()))
+	assert.Equal(t, []string{"Switch.Set(1,true,2)"}, fs.calls)
+
+	cfg.ShellyRPCPulse = -time.Second
+	_, err = shellyrpc.New(cfg)
+	assert.Error(t, err)
+}
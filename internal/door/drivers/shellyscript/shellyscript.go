@@ -0,0 +1,123 @@
+package shellyscript
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ppacher/system-conf/conf"
+	"github.com/tierklinik-dobersberg/cis/internal/door"
+)
+
+// Config holds the configuration of the shelly-script
+// door driver.
+type Config struct {
+	ShellyScriptURL string
+}
+
+// Spec defines the options of the shelly-script door
+// driver.
+var Spec = conf.SectionSpec{
+	{
+		Name:        "ShellyScriptURL",
+		Type:        conf.StringType,
+		Description: "The URL to start the provided shelly script",
+		Default:     "http://localhost/scripts/1/door",
+	},
+}
+
+// Door controls the door using the script provided in
+// contrib/door which must be installed on the Shelly device.
+type Door struct {
+	url string
+}
+
+// New returns a new shelly-script door interfacer
+// for cfg.
+func New(cfg Config) (*Door, error) {
+	if cfg.ShellyScriptURL == "" {
+		return nil, fmt.Errorf("ShellyScriptURL must be configured")
+	}
+
+	return &Door{
+		url: cfg.ShellyScriptURL,
+	}, nil
+}
+
+func (sd *Door) Lock(ctx context.Context) error {
+	return sd.doRequest(ctx, map[string]any{
+		"action": "lock",
+	})
+}
+
+func (sd *Door) Unlock(ctx context.Context) error {
+	return sd.doRequest(ctx, map[string]any{
+		"action": "unlock",
+	})
+}
+
+func (sd *Door) Open(ctx context.Context, d time.Duration) error {
+	// the script expects the duration in milliseconds.
+	return sd.doRequest(ctx, map[string]any{
+		"action":   "open",
+		"duration": d.Milliseconds(),
+	})
+}
+
+func (sd *Door) doRequest(ctx context.Context, payload map[string]any) error {
+	blob, _ := json.Marshal(payload)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", sd.url, bytes.NewReader(blob))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to perform request: %w", err)
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("unexpected status code: %s", res.Status)
+	}
+
+	return nil
+}
+
+func (*Door) Release() {}
+
+var _ door.Interfacer = (*Door)(nil)
+
+func init() {
+	door.MustRegisterDriver(door.Driver{
+		Type:        "shelly-script",
+		DisplayName: "Shelly Pro 2 (provided script)",
+		Spec:        Spec,
+		Validate: func(sec conf.Section) error {
+			var cfg Config
+			if err := conf.DecodeSections([]conf.Section{sec}, Spec, &cfg); err != nil {
+				return err
+			}
+
+			_, err := New(cfg)
+
+			return err
+		},
+		New: func(_ door.DoorConfig, sec conf.Section) (door.Interfacer, error) {
+			var cfg Config
+			if err := conf.DecodeSections([]conf.Section{sec}, Spec, &cfg); err != nil {
+				return nil, err
+			}
+
+			sd, err := New(cfg)
+			if err != nil {
+				return nil, err
+			}
+
+			return sd, nil
+		},
+	})
+}
@@ -0,0 +1,163 @@
+package door
+
+import (
+	"sync"
+	"time"
+)
+
+// EventType describes the type of a door event.
+type EventType string
+
+// Possible door event types.
+const (
+	EventStateChanged       = EventType("state-changed")
+	EventOverwriteCreated   = EventType("overwrite-created")
+	EventOverwriteCancelled = EventType("overwrite-cancelled")
+	EventOverwriteExpired   = EventType("overwrite-expired")
+	EventResetStarted       = EventType("reset-started")
+	EventResetStep          = EventType("reset-step")
+	EventResetFinished      = EventType("reset-finished")
+	EventError              = EventType("error")
+	EventRetriesExhausted   = EventType("retries-exhausted")
+	EventLockdownStarted    = EventType("lockdown-started")
+	EventLockdownReleased   = EventType("lockdown-released")
+)
+
+// Event is published by the door controller whenever something
+// interesting happens.
+type Event struct {
+	// Type is the type of the event.
+	Type EventType `json:"type"`
+	// Door is the ID of the door that published the event.
+	Door string `json:"door"`
+	// Time is the time the event has been published.
+	Time time.Time `json:"time"`
+	// State holds the door state applied or requested, if any.
+	State State `json:"state,omitempty"`
+	// Until holds the time until State is expected to be active.
+	Until *time.Time `json:"until,omitempty"`
+	// Overwrite is set for overwrite events.
+	Overwrite *Overwrite `json:"overwrite,omitempty"`
+	// ResetStep is set for reset-step events and holds the
+	// outcome of the step.
+	ResetStep *ResetStepResult `json:"resetStep,omitempty"`
+	// Lockdown is set for lockdown events.
+	Lockdown *Lockdown `json:"lockdown,omitempty"`
+	// Error holds the error message for failed operations.
+	Error string `json:"error,omitempty"`
+}
+
+// eventBufferSize is the number of events buffered for each
+// subscriber. If a subscriber falls behind, events are dropped.
+const eventBufferSize = 32
+
+// eventHub is a simple publish-subscribe hub for door events.
+type eventHub struct {
+	// lock protects access to subscribers and closed.
+	lock sync.Mutex
+
+	subscribers map[chan Event]struct{}
+
+	// closed is set to true once the hub has been closed.
+	closed bool
+}
+
+func newEventHub() *eventHub {
+	return &eventHub{
+		subscribers: make(map[chan Event]struct{}),
+	}
+}
+
+// subscribe returns a new channel that receives all events published
+// on the hub. The channel is closed when unsubscribe is called or the
+// hub is closed.
+func (hub *eventHub) subscribe() chan Event {
+	ch := make(chan Event, eventBufferSize)
+
+	hub.lock.Lock()
+	defer hub.lock.Unlock()
+
+	if hub.closed {
+		close(ch)
+
+		return ch
+	}
+
+	hub.subscribers[ch] = struct{}{}
+
+	return ch
+}
+
+func (hub *eventHub) unsubscribe(ch chan Event) {
+	hub.lock.Lock()
+	defer hub.lock.Unlock()
+
+	if _, ok := hub.subscribers[ch]; ok {
+		delete(hub.subscribers, ch)
+		close(ch)
+	}
+}
+
+// publish sends evt to all subscribers. It never blocks and drops
+// the event for subscribers that are not able to keep up.
+func (hub *eventHub) publish(evt Event) {
+	hub.lock.Lock()
+	defer hub.lock.Unlock()
+
+	for ch := range hub.subscribers {
+		select {
+		case ch <- evt:
+		default:
+		}
+	}
+}
+
+// close closes all subscriber channels. Subsequent subscriptions
+// are closed immediately.
+func (hub *eventHub) close() {
+	hub.lock.Lock()
+	defer hub.lock.Unlock()
+
+	for ch := range hub.subscribers {
+		close(ch)
+	}
+
+	hub.subscribers = nil
+	hub.closed = true
+}
+
+// Subscribe subscribes to all events published by the door controller.
+// The returned function must be called to cancel the subscription.
+// The channel is closed once the subscription is cancelled or the door
+// is removed.
+func (dc *Controller) Subscribe() (<-chan Event, func()) {
+	ch := dc.events.subscribe()
+
+	return ch, func() {
+		dc.events.unsubscribe(ch)
+	}
+}
+
+// publish publishes evt to all subscribers of the door controller.
+func (dc *Controller) publish(evt Event) {
+	evt.Door = dc.id
+
+	if evt.Time.IsZero() {
+		evt.Time = dc.Now()
+	}
+
+	dc.events.publish(evt)
+}
+
+// publishError publishes an EventError if err is non-nil.
+func (dc *Controller) publishError(state State, err error) {
+	if err == nil {
+		return
+	}
+
+	dc.publish(Event{
+		Type:  EventError,
+		State: state,
+		Error: err.Error(),
+	})
+}
@@ -0,0 +1,11 @@
+package door
+
+// UnregisterDriver removes the driver registered for typ so tests can
+// register drivers without leaking them into the Door schema.
+func UnregisterDriver(typ string) {
+	driversLock.Lock()
+	defer driversLock.Unlock()
+
+	delete(drivers, typ)
+	driverSpec = nil
+}
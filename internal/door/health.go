@@ -0,0 +1,180 @@
+package door
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Backoff settings used by the scheduler when applying the desired
+// door state fails.
+const (
+	minRetryBackoff = 2 * time.Second
+	maxRetryBackoff = time.Minute
+)
+
+// failureBudget is the time the scheduler keeps trying to apply the
+// desired door state after the first failed attempt before it gives up
+// and calls the failure hooks.
+const failureBudget = 5 * time.Minute
+
+// busyRetryDelay is the time the scheduler waits before applying the
+// desired door state again if the door interfacer returned ErrDoorBusy.
+const busyRetryDelay = 5 * time.Second
+
+// Health describes how well the scheduler is able to apply the
+// desired door state.
+type Health struct {
+	// DesiredState is the door state the scheduler tries to apply.
+	DesiredState State `json:"desiredState,omitempty"`
+	// LastSuccess is the time the desired state has last been
+	// applied successfully.
+	LastSuccess time.Time `json:"lastSuccess,omitempty"`
+	// LastError holds the last error returned by the door interfacer.
+	LastError string `json:"lastError,omitempty"`
+	// LastErrorTime is the time LastError occurred.
+	LastErrorTime time.Time `json:"lastErrorTime,omitempty"`
+	// ConsecutiveFailures is the number of failed attempts since
+	// the last successful one.
+	ConsecutiveFailures int `json:"consecutiveFailures"`
+	// FailingSince is the time of the first failed attempt since
+	// the last successful one.
+	FailingSince time.Time `json:"failingSince,omitempty"`
+	// RetriesExhausted is set to true if the scheduler gave up
+	// applying DesiredState.
+	RetriesExhausted bool `json:"retriesExhausted"`
+}
+
+// Healthy returns true if the last attempt to apply the desired
+// door state succeeded.
+func (h Health) Healthy() bool {
+	return h.ConsecutiveFailures == 0 && !h.RetriesExhausted
+}
+
+// FailureHook is called when the scheduler gave up applying the
+// desired door state.
+type FailureHook func(ctx context.Context, dc *Controller, health Health)
+
+// retryBackoff returns the time to wait before the next attempt
+// after failures consecutive failures.
+func retryBackoff(failures int) time.Duration {
+	backoff := minRetryBackoff
+	for i := 1; i < failures && backoff < maxRetryBackoff; i++ {
+		backoff *= 2
+	}
+
+	if backoff > maxRetryBackoff {
+		return maxRetryBackoff
+	}
+
+	return backoff
+}
+
+// Health returns the health of the door scheduler.
+func (dc *Controller) Health() Health {
+	dc.healthLock.Lock()
+	defer dc.healthLock.Unlock()
+
+	return dc.health
+}
+
+// recordAttempt updates the scheduler health after trying to apply
+// state and returns the new health.
+func (dc *Controller) recordAttempt(state State, err error) Health {
+	dc.healthLock.Lock()
+	defer dc.healthLock.Unlock()
+
+	if dc.health.DesiredState != state {
+		dc.health.DesiredState = state
+		dc.health.RetriesExhausted = false
+	}
+
+	if err != nil {
+		dc.health.LastError = err.Error()
+		dc.health.LastErrorTime = dc.Now()
+		dc.health.ConsecutiveFailures++
+
+		if dc.health.ConsecutiveFailures == 1 {
+			dc.health.FailingSince = dc.health.LastErrorTime
+		}
+	} else {
+		dc.health.LastSuccess = dc.Now()
+		dc.health.ConsecutiveFailures = 0
+		dc.health.FailingSince = time.Time{}
+		dc.health.RetriesExhausted = false
+	}
+
+	return dc.health
+}
+
+// retriesExhausted marks the retries of the scheduler as exhausted
+// and notifies the failure hook and webhook.
+func (dc *Controller) retriesExhausted(ctx context.Context) {
+	dc.healthLock.Lock()
+	dc.health.RetriesExhausted = true
+	health := dc.health
+	dc.healthLock.Unlock()
+
+	log.From(ctx).Errorf("giving up to apply door state %s after %d failed attempts: %s", health.DesiredState, health.ConsecutiveFailures, health.LastError)
+
+	dc.publish(Event{
+		Type:  EventRetriesExhausted,
+		State: health.DesiredState,
+		Error: health.LastError,
+	})
+
+	// hooks should not block the scheduler and must be
+	// called even if ctx has already been cancelled.
+	ctx = context.WithoutCancel(ctx)
+
+	if dc.onFailure != nil {
+		go dc.onFailure(ctx, dc, health)
+	}
+
+	dc.configLock.RLock()
+	webhook := dc.failureWebhook
+	dc.configLock.RUnlock()
+
+	if webhook != "" {
+		go func() {
+			if err := dc.callFailureWebhook(ctx, webhook, health); err != nil {
+				log.From(ctx).Errorf("failed to call door failure webhook: %s", err)
+			}
+		}()
+	}
+}
+
+func (dc *Controller) callFailureWebhook(ctx context.Context, url string, health Health) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	blob, err := json.Marshal(map[string]any{
+		"door":        dc.ID(),
+		"displayName": dc.DisplayName(),
+		"health":      health,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(blob))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to perform request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("unexpected status code: %s", res.Status)
+	}
+
+	return nil
+}
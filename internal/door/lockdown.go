@@ -0,0 +1,263 @@
+package door
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	idmv1 "github.com/tierklinik-dobersberg/apis/gen/go/tkd/idm/v1"
+	"github.com/tierklinik-dobersberg/cis/runtime/session"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// LockdownCollection is the name of the mongo-db collection used
+// to persist door lockdowns.
+const LockdownCollection = "cis:door:lockdown"
+
+var (
+	// ErrLockdownActive is returned for actions that are not permitted
+	// while the door is in lockdown.
+	ErrLockdownActive = errors.New("door is in lockdown")
+
+	// ErrNoLockdown is returned when releasing a lockdown while the door
+	// is not in lockdown.
+	ErrNoLockdown = errors.New("door is not in lockdown")
+
+	// ErrLockdownReleaseDenied is returned if the user is not permitted
+	// to release a lockdown.
+	ErrLockdownReleaseDenied = errors.New("not permitted to release the lockdown")
+
+	// ErrNoLockdownReleaseRoles is returned when starting a lockdown of
+	// a door that does not have any LockdownReleaseRoles configured as
+	// nobody would be able to release it.
+	ErrNoLockdownReleaseRoles = errors.New("no roles are permitted to release a lockdown")
+)
+
+// Lockdown forces the door to be locked until it is released. It takes
+// precedence over opening hours and manual overwrites.
+type Lockdown struct {
+	// Door is the ID of the door in lockdown.
+	Door string `json:"door" bson:"_id"`
+	// Reason is a human readable description of why the door is
+	// in lockdown.
+	Reason string `json:"reason,omitempty" bson:"reason,omitempty"`
+	// SessionUser is the ID of the user that started the lockdown.
+	SessionUser string `json:"sessionUser" bson:"sessionUser"`
+	// CreatedAt holds the time the lockdown has been started.
+	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
+}
+
+// LockdownDatabase persists door lockdowns so they survive restarts
+// of cisd.
+type LockdownDatabase interface {
+	// Save stores the lockdown of a door, replacing any existing one.
+	Save(ctx context.Context, lockdown Lockdown) error
+
+	// Load returns the lockdown of door or nil if the door is not
+	// in lockdown.
+	Load(ctx context.Context, door string) (*Lockdown, error)
+
+	// Delete removes the lockdown of door.
+	Delete(ctx context.Context, door string) error
+}
+
+type lockdownDatabase struct {
+	col *mongo.Collection
+}
+
+// NewLockdownDatabase returns a new lockdown database that stores
+// door lockdowns in db.
+func NewLockdownDatabase(_ context.Context, db *mongo.Database) (LockdownDatabase, error) {
+	return &lockdownDatabase{
+		col: db.Collection(LockdownCollection),
+	}, nil
+}
+
+func (db *lockdownDatabase) Save(ctx context.Context, lockdown Lockdown) error {
+	_, err := db.col.ReplaceOne(ctx, bson.M{"_id": lockdown.Door}, lockdown, options.Replace().SetUpsert(true))
+
+	return err
+}
+
+func (db *lockdownDatabase) Load(ctx context.Context, door string) (*Lockdown, error) {
+	var lockdown Lockdown
+	if err := db.col.FindOne(ctx, bson.M{"_id": door}).Decode(&lockdown); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &lockdown, nil
+}
+
+func (db *lockdownDatabase) Delete(ctx context.Context, door string) error {
+	_, err := db.col.DeleteOne(ctx, bson.M{"_id": door})
+
+	return err
+}
+
+// ActiveLockdown returns the lockdown of the door or nil if the door
+// is not in lockdown.
+func (dc *Controller) ActiveLockdown() *Lockdown {
+	dc.lockdownLock.Lock()
+	defer dc.lockdownLock.Unlock()
+
+	if dc.lockdown == nil {
+		return nil
+	}
+
+	lockdown := *dc.lockdown
+
+	return &lockdown
+}
+
+// lockdownAt returns the lockdown that is active at t, if any.
+func (dc *Controller) lockdownAt(t time.Time) *Lockdown {
+	lockdown := dc.ActiveLockdown()
+	if lockdown == nil || t.Before(lockdown.CreatedAt) {
+		return nil
+	}
+
+	return lockdown
+}
+
+// StartLockdown puts the door into lockdown. The door is locked
+// immediately and stays locked until the lockdown is released.
+// Starting a lockdown while one is active updates the reason.
+// ErrNoLockdownReleaseRoles is returned if no LockdownReleaseRoles
+// are configured.
+func (dc *Controller) StartLockdown(ctx context.Context, reason string) (*Lockdown, error) {
+	dc.configLock.RLock()
+	releasable := len(dc.lockdownReleaseRoles) > 0
+	dc.configLock.RUnlock()
+
+	if !releasable {
+		return nil, ErrNoLockdownReleaseRoles
+	}
+
+	previous, _, _ := dc.Current(ctx)
+
+	lockdown := Lockdown{
+		Door:        dc.id,
+		Reason:      reason,
+		SessionUser: session.UserFromCtx(ctx).GetUser().GetId(),
+		CreatedAt:   dc.Now(),
+	}
+
+	if existing := dc.ActiveLockdown(); existing != nil {
+		lockdown.CreatedAt = existing.CreatedAt
+	}
+
+	if dc.lockdowns != nil {
+		if err := dc.lockdowns.Save(ctx, lockdown); err != nil {
+			return nil, fmt.Errorf("failed to persist door lockdown: %w", err)
+		}
+	}
+
+	dc.lockdownLock.Lock()
+	dc.lockdown = &lockdown
+	dc.lockdownLock.Unlock()
+
+	log.From(ctx).Infof("door %s is in lockdown: %s", dc.id, reason)
+
+	dc.record(ctx, AuditRecord{
+		Action:        AuditLockdown,
+		Actor:         lockdown.SessionUser,
+		DesiredState:  Locked,
+		PreviousState: previous,
+		From:          lockdown.CreatedAt,
+	}, nil)
+
+	dc.publish(Event{
+		Type:     EventLockdownStarted,
+		State:    Locked,
+		Lockdown: &lockdown,
+	})
+
+	return &lockdown, dc.triggerSoftReset(ctx)
+}
+
+// CanReleaseLockdown returns true if user has one of the roles that
+// are permitted to release a lockdown. Roles are matched by ID or name.
+func (dc *Controller) CanReleaseLockdown(user *idmv1.Profile) bool {
+	dc.configLock.RLock()
+	defer dc.configLock.RUnlock()
+
+	for _, role := range user.GetRoles() {
+		if slices.Contains(dc.lockdownReleaseRoles, role.GetId()) || slices.Contains(dc.lockdownReleaseRoles, role.GetName()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ReleaseLockdown releases the lockdown of the door. The user associated
+// with ctx must have one of the configured LockdownReleaseRoles.
+func (dc *Controller) ReleaseLockdown(ctx context.Context) error {
+	user := session.UserFromCtx(ctx)
+	if !dc.CanReleaseLockdown(user) {
+		return ErrLockdownReleaseDenied
+	}
+
+	lockdown := dc.ActiveLockdown()
+	if lockdown == nil {
+		return ErrNoLockdown
+	}
+
+	if dc.lockdowns != nil {
+		if err := dc.lockdowns.Delete(ctx, dc.id); err != nil {
+			return fmt.Errorf("failed to delete door lockdown: %w", err)
+		}
+	}
+
+	dc.lockdownLock.Lock()
+	dc.lockdown = nil
+	dc.lockdownLock.Unlock()
+
+	log.From(ctx).Infof("door %s lockdown released", dc.id)
+
+	dc.record(ctx, AuditRecord{
+		Action:        AuditReleaseLockdown,
+		Actor:         user.GetUser().GetId(),
+		PreviousState: Locked,
+		From:          lockdown.CreatedAt,
+		Until:         dc.Now(),
+	}, nil)
+
+	dc.publish(Event{
+		Type:     EventLockdownReleased,
+		Lockdown: lockdown,
+	})
+
+	return dc.triggerSoftReset(ctx)
+}
+
+// loadLockdown restores the lockdown of the door from the lockdown
+// database.
+func (dc *Controller) loadLockdown(ctx context.Context) error {
+	if dc.lockdowns == nil {
+		return nil
+	}
+
+	lockdown, err := dc.lockdowns.Load(ctx, dc.id)
+	if err != nil {
+		return err
+	}
+
+	if lockdown != nil {
+		log.From(ctx).Infof("restored door lockdown for %s by %q since %s: %s", dc.id, lockdown.SessionUser, lockdown.CreatedAt, lockdown.Reason)
+	}
+
+	dc.lockdownLock.Lock()
+	dc.lockdown = lockdown
+	dc.lockdownLock.Unlock()
+
+	return nil
+}
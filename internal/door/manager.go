@@ -0,0 +1,396 @@
+package door
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ppacher/system-conf/conf"
+	"github.com/tierklinik-dobersberg/cis/internal/openinghours"
+	"github.com/tierklinik-dobersberg/cis/runtime"
+)
+
+// Manager keeps track of all configured doors and runs a door
+// Controller for each of them.
+type Manager struct {
+	ohCtrl     *openinghours.Controller
+	overwrites OverwriteDatabase
+	lockdowns  LockdownDatabase
+	audit      AuditLog
+
+	// permissions holds the configured DoorPermissions.
+	permissions *permissions
+
+	// hooksLock protects access to failureHooks.
+	hooksLock sync.Mutex
+
+	// failureHooks are called whenever a door scheduler gave up
+	// applying the desired door state.
+	failureHooks []FailureHook
+
+	// rw protects access to doors and started.
+	rw sync.RWMutex
+
+	// doors holds a door controller for each Door configuration
+	// instance, indexed by the configuration ID.
+	doors map[string]*Controller
+
+	// started is set to true once Start has been called so
+	// doors that are created afterwards are started immediately.
+	started bool
+}
+
+// NewManager returns a new door manager and creates a door controller
+// for each configured door. If overwrites and lockdowns are non-nil, manual
+// overwrites and lockdowns are persisted and restored. If audit is non-nil,
+// all door actions are recorded there.
+func NewManager(ctx context.Context, ohCtrl *openinghours.Controller, cs *runtime.ConfigSchema, overwrites OverwriteDatabase, lockdowns LockdownDatabase, audit AuditLog) (*Manager, error) {
+	mng := &Manager{
+		ohCtrl:     ohCtrl,
+		overwrites: overwrites,
+		lockdowns:  lockdowns,
+		audit:      audit,
+		doors:      make(map[string]*Controller),
+		permissions: &permissions{
+			entries: make(map[string]PermissionConfig),
+		},
+	}
+
+	cs.AddNotifier(mng, "Door")
+	cs.AddValidator(mng, "Door")
+
+	cs.AddNotifier(mng.permissions, "DoorPermission")
+	cs.AddValidator(mng.permissions, "DoorPermission")
+
+	// initialize now
+	all, err := cs.All(ctx, "Door")
+	if err != nil {
+		return nil, err
+	}
+
+	for idx := range all {
+		if err := mng.NotifyChange(ctx, runtime.ChangeTypeCreate, all[idx].ID, &all[idx].Section); err != nil {
+			return nil, err
+		}
+	}
+
+	allPermissions, err := cs.All(ctx, "DoorPermission")
+	if err != nil {
+		return nil, err
+	}
+
+	for idx := range allPermissions {
+		if err := mng.permissions.NotifyChange(ctx, runtime.ChangeTypeCreate, allPermissions[idx].ID, &allPermissions[idx].Section); err != nil {
+			return nil, err
+		}
+	}
+
+	if !mng.permissions.configured() {
+		log.From(ctx).Errorf("no DoorPermission configured, authenticated users may only view the doors")
+	}
+
+	// reset the schedulers whenever new opening hours got configured.
+	ohCtrl.OnChange(func() {
+		for _, dc := range mng.List() {
+			dc.softReset()
+		}
+	})
+
+	return mng, nil
+}
+
+func (mng *Manager) Validate(ctx context.Context, sec runtime.Section) error {
+	var cfg DoorConfig
+	if err := conf.DecodeSections([]conf.Section{sec.Section}, Spec, &cfg); err != nil {
+		return err
+	}
+
+	if cfg.Name == "" {
+		return fmt.Errorf("Name must be configured")
+	}
+
+	if slices.Contains(reservedDoorNames, strings.ToLower(cfg.Name)) {
+		return fmt.Errorf("Name: %q is reserved and cannot be used as a door name", cfg.Name)
+	}
+
+	mng.rw.RLock()
+	err := mng.checkConflicts(sec.ID, cfg, sec.Section)
+	mng.rw.RUnlock()
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := ParseResetSequence(cfg.ResetSequence); err != nil {
+		return fmt.Errorf("ResetSequence: %w", err)
+	}
+
+	if cfg.Type == DisabledType {
+		return nil
+	}
+
+	driver, ok := GetDriver(cfg.Type)
+	if !ok {
+		return fmt.Errorf("unsupport door interface type: %q", cfg.Type)
+	}
+
+	if driver.Validate != nil {
+		return driver.Validate(sec.Section)
+	}
+
+	return nil
+}
+
+func (mng *Manager) NotifyChange(ctx context.Context, changeType, id string, sec *conf.Section) error {
+	stale, started, err := mng.applyChange(ctx, changeType, id, sec)
+
+	// stopping a door controller waits for all running door operations
+	// to complete so it must not happen while holding mng.rw.
+	if stale != nil {
+		stopDoor(ctx, stale, started)
+	}
+
+	return err
+}
+
+// applyChange applies a configuration change of the door with the given
+// configuration ID. It returns the door controller that has been removed,
+// if any, and whether or not the manager has been started.
+func (mng *Manager) applyChange(ctx context.Context, changeType, id string, sec *conf.Section) (*Controller, bool, error) {
+	mng.rw.Lock()
+	defer mng.rw.Unlock()
+
+	existing, ok := mng.doors[id]
+
+	if changeType == runtime.ChangeTypeDelete {
+		if !ok {
+			return nil, mng.started, nil
+		}
+
+		delete(mng.doors, id)
+
+		return existing, mng.started, nil
+	}
+
+	var cfg DoorConfig
+	if err := conf.DecodeSections([]conf.Section{*sec}, Spec, &cfg); err != nil {
+		return nil, mng.started, err
+	}
+
+	if err := mng.checkConflicts(id, cfg, *sec); err != nil {
+		return nil, mng.started, err
+	}
+
+	// if the name of the door changed we need to create a new door
+	// controller because overwrites and audit records are bound to
+	// the door name.
+	var stale *Controller
+	if ok && existing.ID() != cfg.Name {
+		delete(mng.doors, id)
+		stale = existing
+
+		ok = false
+	}
+
+	if ok {
+		return nil, mng.started, existing.configure(cfg, *sec)
+	}
+
+	dc, err := newController(ctx, cfg.Name, mng.ohCtrl, mng.overwrites, mng.lockdowns, mng.audit)
+	if err != nil {
+		return stale, mng.started, fmt.Errorf("door %s: %w", cfg.Name, err)
+	}
+
+	if err := dc.configure(cfg, *sec); err != nil {
+		return stale, mng.started, fmt.Errorf("door %s: %w", cfg.Name, err)
+	}
+
+	dc.onFailure = mng.notifyFailure
+
+	mng.doors[id] = dc
+
+	if mng.started {
+		if err := dc.Start(); err != nil {
+			return stale, mng.started, fmt.Errorf("door %s: failed to start scheduler: %w", cfg.Name, err)
+		}
+	}
+
+	return stale, mng.started, nil
+}
+
+// checkConflicts returns an error if a door other than the one with
+// the configuration ID id is already named like cfg or shares the value
+// of an option that the driver requires to be unique. Door names are
+// compared case-insensitive. The caller must hold mng.rw.
+func (mng *Manager) checkConflicts(id string, cfg DoorConfig, sec conf.Section) error {
+	var unique []string
+	if driver, ok := GetDriver(cfg.Type); ok {
+		unique = driver.Unique
+	}
+
+	for doorID, dc := range mng.doors {
+		if doorID == id {
+			continue
+		}
+
+		if strings.EqualFold(dc.ID(), cfg.Name) {
+			return fmt.Errorf("Name: a door named %q already exists", dc.ID())
+		}
+
+		dc.configLock.RLock()
+		doorType, other := dc.doorType, dc.section
+		dc.configLock.RUnlock()
+
+		if doorType != cfg.Type {
+			continue
+		}
+
+		for _, opt := range unique {
+			value := getOption(sec, opt)
+			if value != "" && value == getOption(other, opt) {
+				return fmt.Errorf("%s: %q is already used by door %s", opt, value, dc.ID())
+			}
+		}
+	}
+
+	return nil
+}
+
+// getOption returns the value of the option name in sec or an empty
+// string if name is not set.
+func getOption(sec conf.Section, name string) string {
+	value, _ := sec.Options.GetString(name)
+
+	return value
+}
+
+func stopDoor(ctx context.Context, dc *Controller, started bool) {
+	if started {
+		if err := dc.Stop(); err != nil {
+			log.From(ctx).Errorf("failed to stop door scheduler for %s: %s", dc.ID(), err)
+		}
+	}
+
+	dc.release()
+}
+
+// OnFailure registers fn to be called whenever a door scheduler gave
+// up applying the desired door state.
+func (mng *Manager) OnFailure(fn FailureHook) {
+	mng.hooksLock.Lock()
+	defer mng.hooksLock.Unlock()
+
+	mng.failureHooks = append(mng.failureHooks, fn)
+}
+
+func (mng *Manager) notifyFailure(ctx context.Context, dc *Controller, health Health) {
+	mng.hooksLock.Lock()
+	hooks := append([]FailureHook(nil), mng.failureHooks...)
+	mng.hooksLock.Unlock()
+
+	for _, fn := range hooks {
+		fn(ctx, dc, health)
+	}
+}
+
+// Get returns the door controller for the door with the given name. If name
+// is empty, the default door is returned. The default door is either the door
+// named DefaultDoorName or the only configured door.
+func (mng *Manager) Get(name string) (*Controller, error) {
+	mng.rw.RLock()
+	defer mng.rw.RUnlock()
+
+	if name == "" {
+		if len(mng.doors) == 1 {
+			for _, dc := range mng.doors {
+				return dc, nil
+			}
+		}
+
+		name = DefaultDoorName
+	}
+
+	for _, dc := range mng.doors {
+		if strings.EqualFold(dc.ID(), name) {
+			return dc, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrUnknownDoor, name)
+}
+
+// List returns all door controllers sorted by their ID.
+func (mng *Manager) List() []*Controller {
+	mng.rw.RLock()
+	defer mng.rw.RUnlock()
+
+	result := make([]*Controller, 0, len(mng.doors))
+	for _, dc := range mng.doors {
+		result = append(result, dc)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID() < result[j].ID()
+	})
+
+	return result
+}
+
+// History returns all audit records matching query together with
+// the total number of matching records.
+func (mng *Manager) History(ctx context.Context, query AuditQuery) ([]AuditRecord, int64, error) {
+	if mng.audit == nil {
+		return nil, 0, fmt.Errorf("door audit log not configured")
+	}
+
+	return mng.audit.Query(ctx, query)
+}
+
+// Start starts the schedulers of all door controllers. Doors that
+// are configured later on are started automatically.
+func (mng *Manager) Start() error {
+	mng.rw.Lock()
+	defer mng.rw.Unlock()
+
+	for _, dc := range mng.doors {
+		if err := dc.Start(); err != nil {
+			return fmt.Errorf("door %s: %w", dc.ID(), err)
+		}
+	}
+
+	mng.started = true
+
+	return nil
+}
+
+// Stop stops the schedulers of all door controllers and waits
+// for all operations to complete.
+func (mng *Manager) Stop() error {
+	mng.rw.Lock()
+	if !mng.started {
+		mng.rw.Unlock()
+
+		return nil
+	}
+
+	doors := make([]*Controller, 0, len(mng.doors))
+	for _, dc := range mng.doors {
+		doors = append(doors, dc)
+	}
+
+	mng.started = false
+	mng.rw.Unlock()
+
+	// door controllers are stopped without holding mng.rw as
+	// running door operations may need to access the manager.
+	for _, dc := range doors {
+		if err := dc.Stop(); err != nil {
+			return fmt.Errorf("door %s: %w", dc.ID(), err)
+		}
+	}
+
+	return nil
+}
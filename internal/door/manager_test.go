@@ -0,0 +1,166 @@
+package door
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ppacher/system-conf/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tierklinik-dobersberg/cis/internal/cfgspec"
+	"github.com/tierklinik-dobersberg/cis/internal/openinghours"
+	"github.com/tierklinik-dobersberg/cis/pkg/clock"
+	"github.com/tierklinik-dobersberg/cis/runtime"
+)
+
+// blockingDoor is a door interfacer that blocks opening the door
+// until release is closed.
+type blockingDoor struct {
+	NoOp
+
+	opened  chan struct{}
+	release chan struct{}
+}
+
+func (bd *blockingDoor) Open(context.Context, time.Duration) error {
+	close(bd.opened)
+	<-bd.release
+
+	return nil
+}
+
+func TestManagerValidateName(t *testing.T) {
+	t.Parallel()
+
+	mng := &Manager{}
+
+	section := func(name string) runtime.Section {
+		return runtime.Section{
+			Section: conf.Section{
+				Name: "Door",
+				Options: conf.Options{
+					{Name: "Name", Value: name},
+					{Name: "Type", Value: DisabledType},
+				},
+			},
+		}
+	}
+
+	ctx := context.Background()
+
+	require.NoError(t, mng.Validate(ctx, section("entry")))
+	require.NoError(t, mng.Validate(ctx, section("garage")))
+
+	for _, name := range reservedDoorNames {
+		assert.ErrorContains(t, mng.Validate(ctx, section(name)), "reserved", name)
+	}
+}
+
+func TestManagerDuplicateNames(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ohCtrl, err := openinghours.NewController(cfgspec.Config{
+		TimeZone: "Europe/Vienna",
+	}, noHolidays{}, clock.NewFake(time.Now()))
+	require.NoError(t, err)
+
+	dc, err := newController(ctx, "Entry", ohCtrl, nil, nil, nil)
+	require.NoError(t, err)
+
+	mng := &Manager{
+		doors: map[string]*Controller{"door-1": dc},
+	}
+
+	section := func(id, name string) runtime.Section {
+		return runtime.Section{
+			ID: id,
+			Section: conf.Section{
+				Name: "Door",
+				Options: conf.Options{
+					{Name: "Name", Value: name},
+					{Name: "Type", Value: DisabledType},
+				},
+			},
+		}
+	}
+
+	assert.ErrorContains(t, mng.Validate(ctx, section("door-2", "Entry")), "already exists")
+	assert.ErrorContains(t, mng.Validate(ctx, section("door-2", "ENTRY")), "already exists")
+	assert.NoError(t, mng.Validate(ctx, section("door-1", "entry")), "a door may keep its own name")
+	assert.NoError(t, mng.Validate(ctx, section("door-2", "Garage")))
+
+	sec := section("door-2", "entry").Section
+	assert.ErrorContains(t, mng.NotifyChange(ctx, runtime.ChangeTypeCreate, "door-2", &sec), "already exists")
+	assert.Len(t, mng.List(), 1)
+
+	for _, name := range []string{"Entry", "entry", "ENTRY"} {
+		found, err := mng.Get(name)
+		require.NoError(t, err, name)
+		assert.Same(t, dc, found, name)
+	}
+}
+
+func TestManagerStopsDoorWithoutLock(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ohCtrl, err := openinghours.NewController(cfgspec.Config{
+		TimeZone: "Europe/Vienna",
+	}, noHolidays{}, clock.NewFake(time.Now()))
+	require.NoError(t, err)
+
+	dc, err := newController(ctx, "test", ohCtrl, nil, nil, nil)
+	require.NoError(t, err)
+
+	bd := &blockingDoor{
+		opened:  make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	dc.door = bd
+
+	require.NoError(t, dc.Start())
+
+	mng := &Manager{
+		doors:   map[string]*Controller{"door-1": dc},
+		started: true,
+	}
+
+	go func() {
+		_ = dc.Open(ctx, 0)
+	}()
+	<-bd.opened
+
+	removed := make(chan error, 1)
+	go func() {
+		removed <- mng.NotifyChange(ctx, runtime.ChangeTypeDelete, "door-1", nil)
+	}()
+
+	// wait until the manager is stopping the door controller which
+	// waits for the door to be opened.
+	<-dc.stop
+
+	listed := make(chan []*Controller, 1)
+	go func() {
+		listed <- mng.List()
+	}()
+
+	select {
+	case doors := <-listed:
+		assert.Empty(t, doors)
+	case <-time.After(5 * time.Second):
+		t.Fatal("manager is blocked while stopping a door")
+	}
+
+	close(bd.release)
+
+	select {
+	case err := <-removed:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("door has not been removed")
+	}
+}
@@ -1,10 +1,13 @@
 package door
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type NoOp struct{}
 
-func (NoOp) Lock(context.Context) error   { return nil }
-func (NoOp) Unlock(context.Context) error { return nil }
-func (NoOp) Open(context.Context) error   { return nil }
-func (NoOp) Release()                     {}
+func (NoOp) Lock(context.Context) error                { return nil }
+func (NoOp) Unlock(context.Context) error              { return nil }
+func (NoOp) Open(context.Context, time.Duration) error { return nil }
+func (NoOp) Release()                                  {}
@@ -0,0 +1,303 @@
+package door
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/tierklinik-dobersberg/cis/runtime/session"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Overwrite overwrites the current door state with state until untilTime.
+// It is a shortcut for ScheduleOverwrite starting now with the default
+// priority.
+func (dc *Controller) Overwrite(ctx context.Context, state State, untilTime time.Time) error {
+	_, err := dc.ScheduleOverwrite(ctx, state, dc.Now(), untilTime, 0)
+
+	return err
+}
+
+// ScheduleOverwrite overwrites the door state with state between from and
+// until. If multiple overwrites are active at the same time, the one with
+// the highest priority wins.
+func (dc *Controller) ScheduleOverwrite(ctx context.Context, state State, from, until time.Time, priority int) (*Overwrite, error) {
+	log.From(ctx).V(7).Logf("overwritting door state to %s from %s until %s", state, from, until)
+
+	if err := isValidState(state); err != nil {
+		return nil, err
+	}
+
+	if !until.After(from) {
+		return nil, fmt.Errorf("overwrite must end after it starts")
+	}
+
+	now := dc.Now()
+	if !until.After(now) {
+		return nil, fmt.Errorf("overwrite must end in the future")
+	}
+
+	previous, _, _ := dc.Current(ctx)
+
+	overwrite := Overwrite{
+		ID:          primitive.NewObjectID(),
+		Door:        dc.id,
+		State:       state,
+		From:        from,
+		Until:       until,
+		Priority:    priority,
+		SessionUser: session.UserFromCtx(ctx).GetUser().GetId(),
+		CreatedAt:   now,
+	}
+
+	if dc.overwrites != nil {
+		if err := dc.overwrites.Save(ctx, &overwrite); err != nil {
+			return nil, fmt.Errorf("failed to persist door overwrite: %w", err)
+		}
+	}
+
+	dc.overwriteLock.Lock()
+	{
+		dc.manualOverwrites = append(dc.manualOverwrites, overwrite)
+	}
+	dc.overwriteLock.Unlock()
+
+	dc.record(ctx, AuditRecord{
+		Action:        AuditOverwrite,
+		Actor:         overwrite.SessionUser,
+		DesiredState:  state,
+		PreviousState: previous,
+		From:          from,
+		Until:         until,
+	}, nil)
+
+	dc.publish(Event{
+		Type:      EventOverwriteCreated,
+		State:     state,
+		Overwrite: &overwrite,
+	})
+
+	// trigger a soft reset, unlocking above is REQUIRED
+	// to avoid deadlocking with getManualOverwrites() in
+	// scheduler() (which triggers immediately)
+	if err := dc.triggerSoftReset(ctx); err != nil {
+		return nil, err
+	}
+
+	log.From(ctx).V(6).Logf("door overwrite forcing %s from %s until %s done", state, from, until)
+
+	return &overwrite, nil
+}
+
+// Overwrites returns all overwrites that are active at t or scheduled
+// to become active afterwards, sorted by their start time.
+func (dc *Controller) Overwrites(t time.Time) []Overwrite {
+	result := make([]Overwrite, 0)
+	for _, ov := range dc.getManualOverwrites() {
+		if ov.Until.After(t) {
+			result = append(result, ov)
+		}
+	}
+
+	slices.SortFunc(result, func(a, b Overwrite) int {
+		return a.From.Compare(b.From)
+	})
+
+	return result
+}
+
+// CancelOverwrite cancels the active or scheduled overwrite with
+// the given ID.
+func (dc *Controller) CancelOverwrite(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("%w: %q", ErrUnknownOverwrite, id)
+	}
+
+	previous, _, _ := dc.Current(ctx)
+
+	dc.overwriteLock.Lock()
+	idx := slices.IndexFunc(dc.manualOverwrites, func(ov Overwrite) bool {
+		return ov.ID == oid
+	})
+	if idx < 0 {
+		dc.overwriteLock.Unlock()
+
+		return fmt.Errorf("%w: %q", ErrUnknownOverwrite, id)
+	}
+
+	overwrite := dc.manualOverwrites[idx]
+	dc.manualOverwrites = slices.Delete(dc.manualOverwrites, idx, idx+1)
+	dc.overwriteLock.Unlock()
+
+	if dc.overwrites != nil {
+		if err := dc.overwrites.Delete(ctx, dc.id, oid); err != nil && !errors.Is(err, ErrUnknownOverwrite) {
+			return fmt.Errorf("failed to delete persisted door overwrite: %w", err)
+		}
+	}
+
+	dc.record(ctx, AuditRecord{
+		Action:        AuditCancel,
+		Actor:         session.UserFromCtx(ctx).GetUser().GetId(),
+		DesiredState:  overwrite.State,
+		PreviousState: previous,
+		From:          overwrite.From,
+		Until:         overwrite.Until,
+	}, nil)
+
+	dc.publish(Event{
+		Type:      EventOverwriteCancelled,
+		State:     overwrite.State,
+		Overwrite: &overwrite,
+	})
+
+	return dc.triggerSoftReset(ctx)
+}
+
+// triggerSoftReset triggers a soft reset of the scheduler and blocks
+// until the scheduler accepted it.
+func (dc *Controller) triggerSoftReset(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-dc.stop:
+		return errors.New("stopped")
+	case dc.reset <- resetSoft:
+		return nil
+	}
+}
+
+// cancelActiveOverwrites removes all overwrites that are active at t.
+func (dc *Controller) cancelActiveOverwrites(ctx context.Context, t time.Time) {
+	dc.removeOverwrites(ctx, EventOverwriteCancelled, func(ov Overwrite) bool {
+		return ov.Covers(t)
+	})
+}
+
+// pruneOverwrites removes all overwrites that expired before t.
+func (dc *Controller) pruneOverwrites(ctx context.Context, t time.Time) {
+	dc.removeOverwrites(ctx, EventOverwriteExpired, func(ov Overwrite) bool {
+		return !ov.Until.After(t)
+	})
+}
+
+// removeOverwrites removes all overwrites matching fn and publishes
+// an event of type evt for each of them.
+func (dc *Controller) removeOverwrites(ctx context.Context, evt EventType, fn func(Overwrite) bool) {
+	var removed []Overwrite
+
+	dc.overwriteLock.Lock()
+	dc.manualOverwrites = slices.DeleteFunc(dc.manualOverwrites, func(ov Overwrite) bool {
+		if fn(ov) {
+			removed = append(removed, ov)
+
+			return true
+		}
+
+		return false
+	})
+	dc.overwriteLock.Unlock()
+
+	for idx := range removed {
+		dc.publish(Event{
+			Type:      evt,
+			State:     removed[idx].State,
+			Overwrite: &removed[idx],
+		})
+	}
+
+	if dc.overwrites == nil {
+		return
+	}
+
+	for _, ov := range removed {
+		if err := dc.overwrites.Delete(ctx, dc.id, ov.ID); err != nil && !errors.Is(err, ErrUnknownOverwrite) {
+			log.From(ctx).Errorf("failed to delete persisted door overwrite %s: %s", ov.ID.Hex(), err)
+		}
+	}
+}
+
+// loadOverwrites restores all persisted overwrites. Expired
+// overwrites are discarded.
+func (dc *Controller) loadOverwrites(ctx context.Context) error {
+	if dc.overwrites == nil {
+		return nil
+	}
+
+	overwrites, err := dc.overwrites.List(ctx, dc.id)
+	if err != nil {
+		return err
+	}
+
+	now := dc.Now()
+	for _, ov := range overwrites {
+		if !ov.Until.After(now) {
+			log.From(ctx).V(6).Logf("discarding expired door overwrite %q until %s", ov.State, ov.Until)
+
+			if err := dc.overwrites.Delete(ctx, dc.id, ov.ID); err != nil && !errors.Is(err, ErrUnknownOverwrite) {
+				return err
+			}
+
+			continue
+		}
+
+		log.From(ctx).Infof("restored door overwrite %q for %s by %q from %s until %s", ov.State, dc.id, ov.SessionUser, ov.From, ov.Until)
+
+		dc.overwriteLock.Lock()
+		dc.manualOverwrites = append(dc.manualOverwrites, ov)
+		dc.overwriteLock.Unlock()
+	}
+
+	return nil
+}
+
+func (dc *Controller) getManualOverwrites() []Overwrite {
+	dc.overwriteLock.Lock()
+	defer dc.overwriteLock.Unlock()
+
+	return slices.Clone(dc.manualOverwrites)
+}
+
+// activeOverwrite returns the overwrite with the highest precedence that
+// is active at t or nil if there is none.
+func activeOverwrite(overwrites []Overwrite, t time.Time) *Overwrite {
+	var active *Overwrite
+	for idx := range overwrites {
+		ov := &overwrites[idx]
+		if !ov.Covers(t) {
+			continue
+		}
+
+		if active == nil || ov.outranks(*active) {
+			active = ov
+		}
+	}
+
+	return active
+}
+
+// nextOverwriteChange returns the earliest time after t and before until
+// at which an overwrite starts that takes precedence over active. If active
+// is nil, any overwrite is considered. A zero until is treated as infinite.
+// If there's no such overwrite, until is returned.
+func nextOverwriteChange(overwrites []Overwrite, active *Overwrite, t, until time.Time) time.Time {
+	for _, ov := range overwrites {
+		if !ov.From.After(t) {
+			continue
+		}
+
+		if !until.IsZero() && !ov.From.Before(until) {
+			continue
+		}
+
+		if active != nil && !ov.outranks(*active) {
+			continue
+		}
+
+		until = ov.From
+	}
+
+	return until
+}
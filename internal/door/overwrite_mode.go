@@ -0,0 +1,147 @@
+package door
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tierklinik-dobersberg/cis/pkg/daytime"
+)
+
+// OverwriteMode defines how the end time of an overwrite is resolved
+// from the opening hours of the door.
+type OverwriteMode string
+
+// All supported overwrite modes.
+const (
+	// OverwriteUntilNextChange lasts until the schedule would change
+	// the door state anyway. Contiguous opening hours are treated as
+	// a single frame.
+	OverwriteUntilNextChange OverwriteMode = "until-next-change"
+
+	// OverwriteUntilNextOpening lasts until the next opening hour
+	// starts.
+	OverwriteUntilNextOpening OverwriteMode = "until-next-opening"
+
+	// OverwriteUntilEndOfDay lasts until midnight.
+	OverwriteUntilEndOfDay OverwriteMode = "until-end-of-day"
+)
+
+var (
+	// ErrInvalidOverwriteMode is returned for unsupported overwrite modes.
+	ErrInvalidOverwriteMode = errors.New("invalid overwrite mode")
+
+	// ErrNoScheduledChange is returned if an overwrite mode cannot be
+	// resolved because there are no upcoming opening hours.
+	ErrNoScheduledChange = errors.New("no upcoming opening hours")
+)
+
+// scheduleLookahead limits how far the opening hours are searched
+// when resolving the end time of an overwrite.
+const scheduleLookahead = 14 * 24 * time.Hour
+
+// contiguousFrameGap is the largest gap between two opening hour frames
+// that are still considered contiguous. Opening hours are configured with
+// minute resolution and must not overlap so 08:00 - 12:00 followed by
+// 12:01 - 18:00, or a day ending at 23:59 followed by one starting at 00:00,
+// form a single frame.
+const contiguousFrameGap = time.Minute
+
+// ResolveOverwriteUntil returns the end time of an overwrite that starts
+// at from using mode. Active overwrites and lockdowns are not taken into
+// account.
+func (dc *Controller) ResolveOverwriteUntil(ctx context.Context, mode OverwriteMode, from time.Time) (time.Time, error) {
+	switch mode {
+	case OverwriteUntilNextChange:
+		for _, frame := range dc.scheduledFrames(ctx, from, 2) {
+			// frames include their end time so a frame that ends exactly
+			// at from is already over unless it's followed by a contiguous
+			// one.
+			if frame.Covers(from) {
+				until := dc.endOfContiguousFrames(ctx, frame.To, from.Add(scheduleLookahead))
+				if until.After(from) {
+					return until, nil
+				}
+
+				continue
+			}
+
+			if frame.From.After(from) {
+				return frame.From, nil
+			}
+		}
+
+		return time.Time{}, ErrNoScheduledChange
+
+	case OverwriteUntilNextOpening:
+		for _, frame := range dc.scheduledFrames(ctx, from, 2) {
+			if frame.From.After(from) {
+				return frame.From, nil
+			}
+		}
+
+		return time.Time{}, ErrNoScheduledChange
+
+	case OverwriteUntilEndOfDay:
+		return nextDay(from.In(dc.Location())), nil
+	}
+
+	return time.Time{}, ErrInvalidOverwriteMode
+}
+
+// endOfContiguousFrames returns the end of the opening hour frames that
+// follow until without a gap, even across midnight. See contiguousFrameGap.
+// The search stops at limit.
+func (dc *Controller) endOfContiguousFrames(ctx context.Context, until, limit time.Time) time.Time {
+	for until.Before(limit) {
+		extended := false
+
+		for _, frame := range dc.scheduledFrames(ctx, until, 2) {
+			if !frame.From.After(until.Add(contiguousFrameGap)) && frame.To.After(until) {
+				until = frame.To
+				extended = true
+
+				break
+			}
+		}
+
+		if !extended {
+			break
+		}
+	}
+
+	return until
+}
+
+// scheduledFrames returns up to limit opening hour frames of the door
+// that cover or follow from.
+func (dc *Controller) scheduledFrames(ctx context.Context, from time.Time, limit int) []daytime.TimeRange {
+	ids := dc.OpeningHours()
+	end := from.Add(scheduleLookahead)
+
+	var frames []daytime.TimeRange
+
+	for t := from; t.Before(end) && len(frames) < limit; {
+		// UpcomingDoorFramesFor stops at the first day without opening
+		// hours so we continue searching on the next day.
+		upcoming := dc.UpcomingDoorFramesFor(ctx, t, 1, ids)
+		if len(upcoming) == 0 {
+			t = nextDay(t.In(dc.Location()))
+
+			continue
+		}
+
+		frame := upcoming[0]
+		frames = append(frames, frame)
+
+		// frames include their end time so we need to search after it.
+		t = frame.To.Add(time.Nanosecond)
+	}
+
+	return frames
+}
+
+// nextDay returns midnight of the day following t.
+func nextDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,115 @@
+package door
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tierklinik-dobersberg/cis/internal/openinghours"
+)
+
+func TestResolveOverwriteUntil(t *testing.T) {
+	t.Parallel()
+
+	st := newSchedulerTest(t,
+		openinghours.Definition{
+			OnWeekday:  []string{"Mon"},
+			TimeRanges: []string{"08:00 - 12:00", "16:00 - 18:00"},
+			OpenBefore: 15 * time.Minute,
+			CloseAfter: 15 * time.Minute,
+		},
+		openinghours.Definition{
+			OnWeekday:  []string{"Wed"},
+			TimeRanges: []string{"08:00 - 12:00"},
+		},
+	)
+
+	wednesday := st.at(8, 0).AddDate(0, 0, 2)
+
+	cases := []struct {
+		from  time.Time
+		mode  OverwriteMode
+		until time.Time
+	}{
+		{st.at(7, 0), OverwriteUntilNextChange, st.at(7, 45)},
+		{st.at(7, 0), OverwriteUntilNextOpening, st.at(7, 45)},
+		{st.at(9, 0), OverwriteUntilNextChange, st.at(12, 15)},
+		{st.at(9, 0), OverwriteUntilNextOpening, st.at(15, 45)},
+		// opening hours end at 12:15 so the next change is at 15:45
+		{st.at(12, 15), OverwriteUntilNextChange, st.at(15, 45)},
+		{st.at(13, 0), OverwriteUntilNextChange, st.at(15, 45)},
+		// days without opening hours are skipped
+		{st.at(19, 0), OverwriteUntilNextChange, wednesday},
+		{st.at(19, 0), OverwriteUntilNextOpening, wednesday},
+		{st.at(9, 0), OverwriteUntilEndOfDay, st.at(0, 0).AddDate(0, 0, 1)},
+	}
+
+	for _, c := range cases {
+		until, err := st.dc.ResolveOverwriteUntil(st.ctx, c.mode, c.from)
+		require.NoError(t, err, "%s at %s", c.mode, c.from)
+		assert.Equal(t, c.until, until, "%s at %s", c.mode, c.from)
+	}
+
+	_, err := st.dc.ResolveOverwriteUntil(st.ctx, "forever", st.at(9, 0))
+	assert.ErrorIs(t, err, ErrInvalidOverwriteMode)
+}
+
+func TestResolveOverwriteUntilWithoutOpeningHours(t *testing.T) {
+	t.Parallel()
+
+	st := newSchedulerTest(t)
+
+	_, err := st.dc.ResolveOverwriteUntil(st.ctx, OverwriteUntilNextChange, st.at(9, 0))
+	assert.ErrorIs(t, err, ErrNoScheduledChange)
+
+	_, err = st.dc.ResolveOverwriteUntil(st.ctx, OverwriteUntilNextOpening, st.at(9, 0))
+	assert.ErrorIs(t, err, ErrNoScheduledChange)
+}
+
+func TestResolveOverwriteUntilContiguousFrames(t *testing.T) {
+	t.Parallel()
+
+	st := newSchedulerTest(t,
+		openinghours.Definition{
+			OnWeekday:  []string{"Mon"},
+			TimeRanges: []string{"08:00 - 12:00", "12:01 - 18:00", "18:05 - 19:00"},
+		},
+		// frames that continue across midnight
+		openinghours.Definition{
+			OnWeekday:  []string{"Tue"},
+			TimeRanges: []string{"20:00 - 23:59"},
+		},
+		openinghours.Definition{
+			OnWeekday:  []string{"Wed"},
+			TimeRanges: []string{"00:00 - 06:00"},
+		},
+	)
+
+	day := func(offset, hour, minute int) time.Time {
+		return st.at(hour, minute).AddDate(0, 0, offset)
+	}
+
+	cases := []struct {
+		from  time.Time
+		until time.Time
+	}{
+		{day(0, 9, 0), day(0, 18, 0)},
+		{day(0, 12, 0), day(0, 18, 0)},
+		{day(0, 13, 0), day(0, 18, 0)},
+		// 18:05 is not contiguous with 18:00
+		{day(0, 18, 2), day(0, 18, 5)},
+		{day(0, 18, 30), day(0, 19, 0)},
+		{day(1, 21, 0), day(2, 6, 0)},
+		{day(2, 1, 0), day(2, 6, 0)},
+		// before the frames start the next change is still the start
+		// of the first frame.
+		{day(0, 7, 0), day(0, 8, 0)},
+	}
+
+	for _, c := range cases {
+		until, err := st.dc.ResolveOverwriteUntil(st.ctx, OverwriteUntilNextChange, c.from)
+		require.NoError(t, err, "at %s", c.from)
+		assert.Equal(t, c.until, until, "at %s", c.from)
+	}
+}
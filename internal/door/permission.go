@@ -0,0 +1,199 @@
+package door
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/ppacher/system-conf/conf"
+	idmv1 "github.com/tierklinik-dobersberg/apis/gen/go/tkd/idm/v1"
+	"github.com/tierklinik-dobersberg/cis/runtime"
+	"github.com/tierklinik-dobersberg/cis/runtime/session"
+)
+
+// Action is a door action that can be permitted to IDM roles using
+// the DoorPermission schema.
+type Action string
+
+// All door actions that require a permission.
+const (
+	ActionView      Action = "view"
+	ActionOpen      Action = "open"
+	ActionOverwrite Action = "overwrite"
+	ActionReset     Action = "reset"
+	ActionLockdown  Action = "lockdown"
+)
+
+// Actions holds all door actions.
+var Actions = []Action{
+	ActionView,
+	ActionOpen,
+	ActionOverwrite,
+	ActionReset,
+	ActionLockdown,
+}
+
+// ErrPermissionDenied is returned if a user is not permitted to perform
+// a door action.
+var ErrPermissionDenied = errors.New("permission denied")
+
+// PermissionConfig permits a set of door actions to users with one of
+// the configured roles.
+type PermissionConfig struct {
+	Roles   []string
+	Actions []string
+	Doors   []string
+}
+
+// PermissionSpec defines the options of the DoorPermission schema.
+var PermissionSpec = conf.SectionSpec{
+	{
+		Name:        "Roles",
+		Required:    true,
+		Type:        conf.StringSliceType,
+		Description: "The IDs or names of roles that are granted the permission",
+		Annotations: new(conf.Annotation).With(
+			runtime.OneOfRoles,
+		),
+	},
+	{
+		Name:        "Actions",
+		Required:    true,
+		Type:        conf.StringSliceType,
+		Description: "The door actions that are permitted",
+		Annotations: new(conf.Annotation).With(
+			runtime.OneOf(
+				runtime.PossibleValue{
+					Display: "View state",
+					Value:   string(ActionView),
+				},
+				runtime.PossibleValue{
+					Display: "Open",
+					Value:   string(ActionOpen),
+				},
+				runtime.PossibleValue{
+					Display: "Overwrite",
+					Value:   string(ActionOverwrite),
+				},
+				runtime.PossibleValue{
+					Display: "Reset",
+					Value:   string(ActionReset),
+				},
+				runtime.PossibleValue{
+					Display: "Lockdown",
+					Value:   string(ActionLockdown),
+				},
+			),
+		),
+	},
+	{
+		Name:        "Doors",
+		Type:        conf.StringSliceType,
+		Description: "The names of the doors the permission applies to. If empty, it applies to all doors",
+		Annotations: new(conf.Annotation).With(
+			runtime.OneOfRef("Door", "Name", "DisplayName"),
+		),
+	},
+}
+
+// permissions holds all configured door permissions and keeps them
+// up-to-date with the DoorPermission schema.
+type permissions struct {
+	rw      sync.RWMutex
+	entries map[string]PermissionConfig
+}
+
+func (p *permissions) Validate(ctx context.Context, sec runtime.Section) error {
+	var cfg PermissionConfig
+	if err := conf.DecodeSections([]conf.Section{sec.Section}, PermissionSpec, &cfg); err != nil {
+		return err
+	}
+
+	if len(cfg.Roles) == 0 {
+		return fmt.Errorf("Roles must be configured")
+	}
+
+	for _, action := range cfg.Actions {
+		if !slices.Contains(Actions, Action(action)) {
+			return fmt.Errorf("invalid door action: %q", action)
+		}
+	}
+
+	return nil
+}
+
+func (p *permissions) NotifyChange(ctx context.Context, changeType, id string, sec *conf.Section) error {
+	p.rw.Lock()
+	defer p.rw.Unlock()
+
+	if changeType == runtime.ChangeTypeDelete {
+		delete(p.entries, id)
+
+		return nil
+	}
+
+	var cfg PermissionConfig
+	if err := conf.DecodeSections([]conf.Section{*sec}, PermissionSpec, &cfg); err != nil {
+		return err
+	}
+
+	p.entries[id] = cfg
+
+	return nil
+}
+
+// configured returns true if at least one DoorPermission is configured.
+func (p *permissions) configured() bool {
+	p.rw.RLock()
+	defer p.rw.RUnlock()
+
+	return len(p.entries) > 0
+}
+
+// allowed returns true if user has a role that is permitted to perform
+// action on door. Roles are matched by ID or name. If no DoorPermission
+// is configured at all, authenticated users may only view the doors.
+func (p *permissions) allowed(user *idmv1.Profile, door string, action Action) bool {
+	p.rw.RLock()
+	defer p.rw.RUnlock()
+
+	if len(p.entries) == 0 {
+		return user != nil && action == ActionView
+	}
+
+	for _, entry := range p.entries {
+		if !slices.Contains(entry.Actions, string(action)) {
+			continue
+		}
+
+		if len(entry.Doors) > 0 && !slices.Contains(entry.Doors, door) {
+			continue
+		}
+
+		for _, role := range user.GetRoles() {
+			if slices.Contains(entry.Roles, role.GetId()) || slices.Contains(entry.Roles, role.GetName()) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Allowed returns true if user is permitted to perform action on the
+// door with the given name.
+func (mng *Manager) Allowed(user *idmv1.Profile, door string, action Action) bool {
+	return mng.permissions.allowed(user, door, action)
+}
+
+// Authorize returns ErrPermissionDenied if the user associated with ctx
+// is not permitted to perform action on the door with the given name.
+func (mng *Manager) Authorize(ctx context.Context, door string, action Action) error {
+	if !mng.Allowed(session.UserFromCtx(ctx), door, action) {
+		return fmt.Errorf("%w: %s on door %s", ErrPermissionDenied, action, door)
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package door
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ppacher/system-conf/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	idmv1 "github.com/tierklinik-dobersberg/apis/gen/go/tkd/idm/v1"
+	"github.com/tierklinik-dobersberg/cis/runtime"
+)
+
+func permissionSection(roles, actions, doors []string) conf.Section {
+	sec := conf.Section{Name: "DoorPermission"}
+
+	for _, role := range roles {
+		sec.Options = append(sec.Options, conf.Option{Name: "Roles", Value: role})
+	}
+	for _, action := range actions {
+		sec.Options = append(sec.Options, conf.Option{Name: "Actions", Value: action})
+	}
+	for _, door := range doors {
+		sec.Options = append(sec.Options, conf.Option{Name: "Doors", Value: door})
+	}
+
+	return sec
+}
+
+func TestPermissions(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	p := &permissions{
+		entries: make(map[string]PermissionConfig),
+	}
+
+	staff := &idmv1.Profile{
+		Roles: []*idmv1.Role{{Id: "1", Name: "staff"}},
+	}
+	admin := &idmv1.Profile{
+		Roles: []*idmv1.Role{{Id: "2", Name: "admin"}},
+	}
+
+	// without any permission, authenticated users may only view
+	// the doors.
+	for _, action := range Actions {
+		assert.Equal(t, action == ActionView, p.allowed(staff, "entry", action), action)
+		assert.False(t, p.allowed(nil, "entry", action), action)
+	}
+
+	staffSec := permissionSection([]string{"staff"}, []string{"view", "open"}, []string{"entry"})
+	require.NoError(t, p.Validate(ctx, runtime.Section{Section: staffSec}))
+	require.NoError(t, p.NotifyChange(ctx, runtime.ChangeTypeCreate, "1", &staffSec))
+
+	// roles are matched by ID
+	adminSec := permissionSection([]string{"2"}, []string{"view", "open", "overwrite", "reset", "lockdown"}, nil)
+	require.NoError(t, p.NotifyChange(ctx, runtime.ChangeTypeCreate, "2", &adminSec))
+
+	assert.True(t, p.allowed(staff, "entry", ActionView))
+	assert.True(t, p.allowed(staff, "entry", ActionOpen))
+	assert.False(t, p.allowed(staff, "entry", ActionOverwrite))
+	assert.False(t, p.allowed(staff, "garage", ActionOpen))
+	assert.False(t, p.allowed(nil, "entry", ActionView))
+
+	for _, action := range Actions {
+		assert.True(t, p.allowed(admin, "entry", action), action)
+		assert.True(t, p.allowed(admin, "garage", action), action)
+	}
+
+	require.NoError(t, p.NotifyChange(ctx, runtime.ChangeTypeDelete, "1", nil))
+	assert.False(t, p.allowed(staff, "entry", ActionView))
+
+	// once the last permission is removed only viewing is permitted.
+	require.NoError(t, p.NotifyChange(ctx, runtime.ChangeTypeDelete, "2", nil))
+	assert.True(t, p.allowed(staff, "entry", ActionView))
+	assert.False(t, p.allowed(staff, "entry", ActionOverwrite))
+	assert.False(t, p.allowed(admin, "entry", ActionOpen))
+
+	// unknown actions and missing roles are rejected
+	invalid := permissionSection([]string{"staff"}, []string{"fly"}, nil)
+	assert.Error(t, p.Validate(ctx, runtime.Section{Section: invalid}))
+
+	noRoles := permissionSection(nil, []string{"view"}, nil)
+	assert.Error(t, p.Validate(ctx, runtime.Section{Section: noRoles}))
+}
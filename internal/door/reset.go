@@ -0,0 +1,232 @@
+package door
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tierklinik-dobersberg/cis/runtime/session"
+)
+
+// resetStepTimeout is the time a single step of the reset sequence
+// may take.
+const resetStepTimeout = 10 * time.Second
+
+// ResetStep is a single step of the door reset sequence.
+type ResetStep struct {
+	// Action is the door state to apply. It is either Locked,
+	// Unlocked or Open.
+	Action State `json:"action"`
+	// Delay is the time to wait after the action has been
+	// performed.
+	Delay time.Duration `json:"delay"`
+}
+
+// ResetStepResult holds the outcome of a single step of the door
+// reset sequence.
+type ResetStepResult struct {
+	// Step is the index of the step in the reset sequence.
+	Step int `json:"step"`
+	// Action is the door state that has been applied.
+	Action State `json:"action"`
+	// Time is the time the action has been performed.
+	Time time.Time `json:"time"`
+	// Error holds the error message if the action failed.
+	Error string `json:"error,omitempty"`
+}
+
+// defaultResetSequence unlocks, locks and unlocks the door again.
+// For whatever reason, this proved to work best when the door does
+// not behave as it should.
+var defaultResetSequence = []ResetStep{
+	{Action: Unlocked, Delay: 2 * time.Second},
+	{Action: Locked, Delay: 2 * time.Second},
+	{Action: Unlocked},
+}
+
+// ParseResetSequence parses the steps of a door reset sequence. Each
+// step has the format "<action> [<delay>]" where action is one of lock,
+// unlock or open and delay is the time to wait afterwards. If steps is
+// empty the default sequence is returned.
+func ParseResetSequence(steps []string) ([]ResetStep, error) {
+	if len(steps) == 0 {
+		return defaultResetSequence, nil
+	}
+
+	sequence := make([]ResetStep, 0, len(steps))
+	for _, line := range steps {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || len(fields) > 2 {
+			return nil, fmt.Errorf("invalid reset step %q, expected \"<action> [<delay>]\"", line)
+		}
+
+		var step ResetStep
+		switch strings.ToLower(fields[0]) {
+		case "lock":
+			step.Action = Locked
+		case "unlock":
+			step.Action = Unlocked
+		case "open":
+			step.Action = Open
+		default:
+			return nil, fmt.Errorf("invalid reset step %q: unknown action %q", line, fields[0])
+		}
+
+		if len(fields) == 2 {
+			delay, err := time.ParseDuration(fields[1])
+			if err != nil || delay < 0 {
+				return nil, fmt.Errorf("invalid reset step %q: invalid delay %q", line, fields[1])
+			}
+
+			step.Delay = delay
+		}
+
+		sequence = append(sequence, step)
+	}
+
+	return sequence, nil
+}
+
+// ResetSequence returns the steps performed when resetting the door.
+func (dc *Controller) ResetSequence() []ResetStep {
+	dc.configLock.RLock()
+	defer dc.configLock.RUnlock()
+
+	return dc.resetSequence
+}
+
+// Reset triggers a hard reset of the door scheduler and waits for the
+// reset sequence to finish. It returns the outcome of each step.
+// Resetting the door is not permitted while the door is in lockdown
+// as the reset sequence may unlock the door.
+func (dc *Controller) Reset(ctx context.Context) ([]ResetStepResult, error) {
+	if dc.ActiveLockdown() != nil {
+		return nil, ErrLockdownActive
+	}
+
+	req := &resetRequest{
+		actor:  session.UserFromCtx(ctx).GetUser().GetId(),
+		result: make(chan []ResetStepResult, 1),
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+
+	case <-dc.stop:
+		return nil, errors.New("stopped")
+
+	// trigger a hard-reset
+	case dc.reset <- req:
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+
+	case <-dc.stop:
+		return nil, errors.New("stopped")
+
+	case results := <-req.result:
+		return results, nil
+	}
+}
+
+// resetDoor resets the entry door by performing the configured reset
+// sequence.
+func (dc *Controller) resetDoor(ctx context.Context, req *resetRequest) {
+	dc.wg.Add(1)
+	defer dc.wg.Done()
+
+	dc.resetInProgress.Set()
+	defer dc.resetInProgress.UnSet()
+
+	log := log.From(ctx)
+
+	// the state before the reset must be captured before the active
+	// overwrites are removed.
+	previous, _, _ := dc.Current(ctx)
+
+	// remove any active manual overwrite when we do a reset. Overwrites
+	// scheduled for the future are kept.
+	dc.cancelActiveOverwrites(ctx, dc.Now())
+
+	dc.publish(Event{Type: EventResetStarted})
+
+	var (
+		errs    []error
+		results []ResetStepResult
+	)
+
+	for idx, step := range dc.ResetSequence() {
+		result := ResetStepResult{
+			Step:   idx,
+			Action: step.Action,
+			Time:   dc.Now(),
+		}
+
+		if err := dc.performResetStep(ctx, step); err != nil {
+			log.Errorf("reset step %d: failed to apply door state %s: %s", idx, step.Action, err)
+
+			dc.publishError(step.Action, err)
+
+			result.Error = err.Error()
+			errs = append(errs, fmt.Errorf("step %d (%s): %w", idx, step.Action, err))
+		}
+
+		results = append(results, result)
+
+		dc.publish(Event{
+			Type:      EventResetStep,
+			State:     step.Action,
+			Error:     result.Error,
+			ResetStep: &result,
+		})
+
+		if step.Delay > 0 {
+			dc.Clock().Sleep(step.Delay)
+		}
+	}
+
+	err := errors.Join(errs...)
+
+	dc.record(ctx, AuditRecord{
+		Action:        AuditReset,
+		Actor:         req.actor,
+		PreviousState: previous,
+	}, err)
+
+	finished := Event{Type: EventResetFinished}
+	if err != nil {
+		finished.Error = err.Error()
+	}
+	dc.publish(finished)
+
+	if req.result != nil {
+		req.result <- results
+	}
+}
+
+// performResetStep applies the door state of step.
+func (dc *Controller) performResetStep(ctx context.Context, step ResetStep) error {
+	ctx, cancel := context.WithTimeout(ctx, resetStepTimeout)
+	defer cancel()
+
+	dc.interfacerLock.Lock()
+	defer dc.interfacerLock.Unlock()
+
+	switch step.Action {
+	case Locked:
+		return dc.door.Lock(ctx)
+	case Unlocked:
+		return dc.door.Unlock(ctx)
+	case Open:
+		d, _ := dc.OpenDuration(0)
+
+		return dc.door.Open(ctx, d)
+	}
+
+	return fmt.Errorf("invalid reset action %q", step.Action)
+}
@@ -0,0 +1,749 @@
+package door
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	idmv1 "github.com/tierklinik-dobersberg/apis/gen/go/tkd/idm/v1"
+	"github.com/tierklinik-dobersberg/cis/internal/cfgspec"
+	"github.com/tierklinik-dobersberg/cis/internal/holidays"
+	"github.com/tierklinik-dobersberg/cis/internal/openinghours"
+	"github.com/tierklinik-dobersberg/cis/pkg/clock"
+	"github.com/tierklinik-dobersberg/cis/runtime/session"
+)
+
+// schedulerTimers is the number of timers the scheduler waits
+// on while idle.
+const schedulerTimers = 2
+
+type noHolidays struct{}
+
+func (noHolidays) ForYear(context.Context, int) ([]holidays.Holiday, error) {
+	return nil, nil
+}
+
+func (noHolidays) IsHoliday(context.Context, time.Time) (bool, error) {
+	return false, nil
+}
+
+// fakeDoor is a door interfacer that records all calls.
+type fakeDoor struct {
+	lock  sync.Mutex
+	err   error
+	calls chan State
+}
+
+func (fd *fakeDoor) do(state State) error {
+	fd.lock.Lock()
+	defer fd.lock.Unlock()
+
+	fd.calls <- state
+
+	return fd.err
+}
+
+func (fd *fakeDoor) setError(err error) {
+	fd.lock.Lock()
+	defer fd.lock.Unlock()
+
+	fd.err = err
+}
+
+func (fd *fakeDoor) Lock(context.Context) error                { return fd.do(Locked) }
+func (fd *fakeDoor) Unlock(context.Context) error              { return fd.do(Unlocked) }
+func (fd *fakeDoor) Open(context.Context, time.Duration) error { return fd.do(Open) }
+func (fd *fakeDoor) Release()                                  {}
+
+// memoryAudit is an audit log that keeps all records in memory.
+type memoryAudit struct {
+	lock    sync.Mutex
+	records []AuditRecord
+}
+
+func (ma *memoryAudit) Record(_ context.Context, record AuditRecord) error {
+	ma.lock.Lock()
+	defer ma.lock.Unlock()
+
+	ma.records = append(ma.records, record)
+
+	return nil
+}
+
+func (ma *memoryAudit) Query(context.Context, AuditQuery) ([]AuditRecord, int64, error) {
+	ma.lock.Lock()
+	defer ma.lock.Unlock()
+
+	return ma.records, int64(len(ma.records)), nil
+}
+
+// recorded returns all audit records of the given action.
+func (ma *memoryAudit) recorded(action AuditAction) []AuditRecord {
+	ma.lock.Lock()
+	defer ma.lock.Unlock()
+
+	var res []AuditRecord
+	for _, record := range ma.records {
+		if record.Action == action {
+			res = append(res, record)
+		}
+	}
+
+	return res
+}
+
+type schedulerTest struct {
+	t     *testing.T
+	ctx   context.Context
+	clock *clock.Fake
+	door  *fakeDoor
+	audit *memoryAudit
+	dc    *Controller
+}
+
+// newSchedulerTest creates a door controller that uses a fake clock set
+// to Monday, 2024-01-08 07:00 in Europe/Vienna and opening hours from
+// 08:00 to 12:00 on Mondays. The scheduler is started and has applied
+// the initial state once the function returns.
+func newSchedulerTest(t *testing.T, defs ...openinghours.Definition) *schedulerTest {
+	t.Helper()
+
+	fd := &fakeDoor{
+		calls: make(chan State, 100),
+	}
+
+	st := newSchedulerTestWithDoor(t, fd, defs...)
+	st.door = fd
+
+	return st
+}
+
+// newSchedulerTestWithDoor is like newSchedulerTest but uses iface as the
+// door interfacer.
+func newSchedulerTestWithDoor(t *testing.T, iface Interfacer, defs ...openinghours.Definition) *schedulerTest {
+	t.Helper()
+
+	loc, err := time.LoadLocation("Europe/Vienna")
+	require.NoError(t, err)
+
+	clk := clock.NewFake(time.Date(2024, time.January, 8, 7, 0, 0, 0, loc))
+
+	ohCtrl, err := openinghours.NewController(cfgspec.Config{
+		TimeZone: "Europe/Vienna",
+	}, noHolidays{}, clk)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	if len(defs) > 0 {
+		require.NoError(t, ohCtrl.AddOpeningHours(ctx, defs...))
+	}
+
+	audit := &memoryAudit{}
+
+	dc, err := newController(ctx, "test", ohCtrl, nil, nil, audit)
+	require.NoError(t, err)
+
+	dc.door = iface
+
+	require.NoError(t, dc.Start())
+	t.Cleanup(func() {
+		_ = dc.Stop()
+	})
+
+	st := &schedulerTest{
+		t:     t,
+		ctx:   ctx,
+		clock: clk,
+		audit: audit,
+		dc:    dc,
+	}
+
+	// the scheduler triggers one second after being started.
+	st.advance(time.Second)
+
+	return st
+}
+
+var mondayMorning = openinghours.Definition{
+	OnWeekday:  []string{"Mon"},
+	TimeRanges: []string{"08:00 - 12:00"},
+}
+
+// advance waits for the scheduler to be idle, advances the fake clock
+// by d and waits for the scheduler to become idle again.
+func (st *schedulerTest) advance(d time.Duration) {
+	st.clock.BlockUntil(schedulerTimers)
+	st.clock.Advance(d)
+	st.clock.BlockUntil(schedulerTimers)
+}
+
+// at returns the given time of the current day.
+func (st *schedulerTest) at(hour, minute int) time.Time {
+	now := st.clock.Now().In(st.dc.Location())
+
+	return time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, now.Location())
+}
+
+// advanceTo is like advance but sets the clock to the given time of
+// the current day.
+func (st *schedulerTest) advanceTo(hour, minute int) {
+	st.advance(st.at(hour, minute).Sub(st.clock.Now()))
+}
+
+// expectCall expects the next call to the door interfacer to apply want.
+func (st *schedulerTest) expectCall(want State) {
+	st.t.Helper()
+
+	select {
+	case got := <-st.door.calls:
+		assert.Equal(st.t, want, got)
+	case <-time.After(5 * time.Second):
+		st.t.Fatalf("expected door call %s but got none", want)
+	}
+}
+
+// expectNoCall expects that the door interfacer has not been called.
+func (st *schedulerTest) expectNoCall() {
+	st.t.Helper()
+
+	select {
+	case got := <-st.door.calls:
+		st.t.Fatalf("unexpected door call %s", got)
+	default:
+	}
+}
+
+// reset triggers a hard reset and returns a channel that receives
+// the outcome of the reset steps.
+func (st *schedulerTest) reset() <-chan []ResetStepResult {
+	results := make(chan []ResetStepResult, 1)
+
+	go func() {
+		steps, err := st.dc.Reset(st.ctx)
+		assert.NoError(st.t, err)
+
+		results <- steps
+	}()
+
+	return results
+}
+
+// drain discards all recorded door calls.
+func (st *schedulerTest) drain() {
+	for {
+		select {
+		case <-st.door.calls:
+		default:
+			return
+		}
+	}
+}
+
+func TestSchedulerTransitions(t *testing.T) {
+	t.Parallel()
+
+	st := newSchedulerTest(t, mondayMorning)
+	st.expectCall(Locked)
+
+	state, until, _ := st.dc.Current(st.ctx)
+	assert.Equal(t, Locked, state)
+	assert.Equal(t, 8, until.Hour())
+
+	st.advanceTo(8, 0)
+	st.expectCall(Unlocked)
+	st.expectNoCall()
+
+	state, until, _ = st.dc.Current(st.ctx)
+	assert.Equal(t, Unlocked, state)
+	assert.Equal(t, 12, until.Hour())
+
+	st.advanceTo(12, 0)
+	st.expectCall(Locked)
+	st.expectNoCall()
+}
+
+func TestSchedulerResendsState(t *testing.T) {
+	t.Parallel()
+
+	st := newSchedulerTest(t, mondayMorning)
+	st.expectCall(Locked)
+
+	// the current state is re-sent every minute
+	st.advance(time.Minute)
+	st.expectCall(Locked)
+
+	st.advance(30 * time.Second)
+	st.expectNoCall()
+
+	st.advance(30 * time.Second)
+	st.expectCall(Locked)
+}
+
+func TestSchedulerRetryBackoff(t *testing.T) {
+	t.Parallel()
+
+	st := newSchedulerTest(t, mondayMorning)
+	st.expectCall(Locked)
+
+	st.door.setError(errors.New("door offline"))
+
+	st.advance(time.Minute)
+	st.expectCall(Locked)
+
+	health := st.dc.Health()
+	assert.Equal(t, 1, health.ConsecutiveFailures)
+	assert.Equal(t, "door offline", health.LastError)
+	assert.False(t, health.Healthy())
+
+	// the first retry happens after minRetryBackoff
+	st.advance(minRetryBackoff)
+	st.expectCall(Locked)
+	assert.Equal(t, 2, st.dc.Health().ConsecutiveFailures)
+
+	// and the next one after twice the time
+	st.advance(minRetryBackoff)
+	st.expectNoCall()
+	st.advance(minRetryBackoff)
+	st.expectCall(Locked)
+	assert.Equal(t, 3, st.dc.Health().ConsecutiveFailures)
+
+	st.door.setError(nil)
+
+	st.advance(4 * minRetryBackoff)
+	st.expectCall(Locked)
+
+	health = st.dc.Health()
+	assert.Equal(t, 0, health.ConsecutiveFailures)
+	assert.True(t, health.Healthy())
+	assert.Equal(t, st.clock.Now(), health.LastSuccess)
+}
+
+func TestSchedulerDoorBusy(t *testing.T) {
+	t.Parallel()
+
+	st := newSchedulerTest(t, mondayMorning)
+	st.expectCall(Locked)
+
+	st.door.setError(ErrDoorBusy)
+
+	st.advance(time.Minute)
+	st.expectCall(Locked)
+
+	// a busy door is not a failed attempt and is retried shortly.
+	assert.True(t, st.dc.Health().Healthy())
+
+	st.advance(busyRetryDelay)
+	st.expectCall(Locked)
+	assert.True(t, st.dc.Health().Healthy())
+
+	st.door.setError(nil)
+
+	st.advance(busyRetryDelay)
+	st.expectCall(Locked)
+
+	st.advance(busyRetryDelay)
+	st.expectNoCall()
+
+	for _, record := range st.audit.recorded(AuditSchedule) {
+		assert.Empty(t, record.Error)
+	}
+}
+
+func TestSchedulerRetriesExhausted(t *testing.T) {
+	t.Parallel()
+
+	// without any opening hours the door is locked forever.
+	st := newSchedulerTest(t)
+	st.expectCall(Locked)
+
+	exhausted := make(chan Health, 1)
+	st.dc.onFailure = func(_ context.Context, _ *Controller, health Health) {
+		exhausted <- health
+	}
+
+	st.door.setError(errors.New("door offline"))
+
+	st.advance(time.Minute)
+	st.expectCall(Locked)
+
+	failingSince := st.clock.Now()
+	assert.Equal(t, failingSince, st.dc.Health().FailingSince)
+
+	// the scheduler retries with backoff until the failure budget
+	// is used up: 2s, 4s, 8s, 16s, 32s and 4 times 60s.
+	for failures := 1; failures < 10; failures++ {
+		select {
+		case <-exhausted:
+			t.Fatalf("gave up after %s", st.clock.Now().Sub(failingSince))
+		default:
+		}
+
+		st.advance(retryBackoff(failures))
+		st.expectCall(Locked)
+	}
+
+	select {
+	case health := <-exhausted:
+		assert.True(t, health.RetriesExhausted)
+		assert.Equal(t, 10, health.ConsecutiveFailures)
+		assert.GreaterOrEqual(t, st.clock.Now().Sub(health.FailingSince), failureBudget)
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the failure hook to be called")
+	}
+
+	assert.True(t, st.dc.Health().RetriesExhausted)
+
+	// the scheduler gives up
+	st.advance(maxRetryBackoff)
+	st.expectNoCall()
+
+	// only the initial attempt, the first failure and giving up
+	// are recorded.
+	records := st.audit.recorded(AuditSchedule)
+	require.Len(t, records, 3)
+	assert.Empty(t, records[0].Error)
+	assert.Equal(t, "door offline", records[1].Error)
+	assert.Equal(t, "giving up after 10 failed attempts: door offline", records[2].Error)
+}
+
+// reportingDoor is a fakeDoor that always reports state as the
+// actual door state.
+type reportingDoor struct {
+	*fakeDoor
+
+	state State
+}
+
+func (rd *reportingDoor) ReportState(context.Context) (State, error) {
+	return rd.state, nil
+}
+
+func TestSchedulerDriftRetriesCapped(t *testing.T) {
+	t.Parallel()
+
+	fd := &fakeDoor{
+		calls: make(chan State, 100),
+	}
+
+	// the door never reports the locked state so the scheduler keeps
+	// detecting a drift.
+	st := newSchedulerTestWithDoor(t, &reportingDoor{fakeDoor: fd, state: Unlocked})
+	st.door = fd
+	st.expectCall(Locked)
+
+	// the drift is re-applied 60 times before the scheduler gives up.
+	for i := 0; i < 60; i++ {
+		st.advance(time.Minute)
+		st.expectCall(Locked)
+	}
+
+	st.advance(time.Minute)
+	st.expectNoCall()
+
+	st.advance(time.Minute)
+	st.expectNoCall()
+}
+
+func TestSchedulerOverwriteExpires(t *testing.T) {
+	t.Parallel()
+
+	st := newSchedulerTest(t, mondayMorning)
+	st.expectCall(Locked)
+
+	events, unsubscribe := st.dc.Subscribe()
+	defer unsubscribe()
+
+	require.NoError(t, st.dc.Overwrite(st.ctx, Unlocked, st.at(7, 30)))
+	st.expectCall(Unlocked)
+
+	state, until, _ := st.dc.Current(st.ctx)
+	assert.Equal(t, Unlocked, state)
+	assert.True(t, st.at(7, 30).Equal(until))
+	assert.Len(t, st.dc.Overwrites(st.clock.Now()), 1)
+
+	st.clock.BlockUntil(schedulerTimers)
+	st.drain()
+
+	st.advanceTo(7, 30)
+	st.expectCall(Locked)
+	assert.Empty(t, st.dc.Overwrites(st.clock.Now()))
+
+	var types []EventType
+	for len(events) > 0 {
+		types = append(types, (<-events).Type)
+	}
+	assert.Contains(t, types, EventOverwriteCreated)
+	assert.Contains(t, types, EventOverwriteExpired)
+}
+
+func TestSchedulerScheduledOverwrite(t *testing.T) {
+	t.Parallel()
+
+	st := newSchedulerTest(t, mondayMorning)
+	st.expectCall(Locked)
+
+	// a high priority lock overwrite during the opening hours
+	_, err := st.dc.ScheduleOverwrite(st.ctx, Locked, st.at(8, 30), st.at(9, 0), 10)
+	require.NoError(t, err)
+	st.expectCall(Locked)
+
+	// a low priority unlock overwrite before the opening hours
+	_, err = st.dc.ScheduleOverwrite(st.ctx, Unlocked, st.at(7, 30), st.at(7, 45), 0)
+	require.NoError(t, err)
+	st.expectCall(Locked)
+
+	st.clock.BlockUntil(schedulerTimers)
+	st.drain()
+
+	st.advanceTo(7, 30)
+	st.expectCall(Unlocked)
+
+	st.advanceTo(7, 45)
+	st.expectCall(Locked)
+
+	st.advanceTo(8, 0)
+	st.expectCall(Unlocked)
+
+	st.advanceTo(8, 30)
+	st.expectCall(Locked)
+
+	st.advanceTo(9, 0)
+	st.expectCall(Unlocked)
+}
+
+func TestSchedulerSoftReset(t *testing.T) {
+	t.Parallel()
+
+	st := newSchedulerTest(t, mondayMorning)
+	st.expectCall(Locked)
+
+	require.NoError(t, st.dc.triggerSoftReset(st.ctx))
+
+	// a soft reset only re-applies the current state.
+	st.expectCall(Locked)
+	st.clock.BlockUntil(schedulerTimers)
+	st.expectNoCall()
+}
+
+func TestSchedulerHardReset(t *testing.T) {
+	t.Parallel()
+
+	st := newSchedulerTest(t, mondayMorning)
+	st.expectCall(Locked)
+
+	events, unsubscribe := st.dc.Subscribe()
+	defer unsubscribe()
+
+	// an active overwrite is cancelled by a reset
+	require.NoError(t, st.dc.Overwrite(st.ctx, Unlocked, st.clock.Now().Add(time.Hour)))
+	st.expectCall(Unlocked)
+	st.clock.BlockUntil(schedulerTimers)
+
+	results := st.reset()
+
+	// unlock, lock and unlock with a pause of two seconds each
+	st.expectCall(Unlocked)
+	assert.True(t, st.dc.resetInProgress.IsSet())
+
+	st.clock.BlockUntil(1)
+	st.clock.Advance(2 * time.Second)
+	st.expectCall(Locked)
+
+	st.clock.BlockUntil(1)
+	st.clock.Advance(2 * time.Second)
+	st.expectCall(Unlocked)
+
+	// afterwards the desired state is applied again.
+	st.expectCall(Locked)
+	st.clock.BlockUntil(schedulerTimers)
+	assert.False(t, st.dc.resetInProgress.IsSet())
+	assert.Empty(t, st.dc.Overwrites(st.clock.Now()))
+
+	steps := <-results
+	require.Len(t, steps, 3)
+	assert.Equal(t, []State{Unlocked, Locked, Unlocked}, []State{steps[0].Action, steps[1].Action, steps[2].Action})
+
+	var types []EventType
+	for len(events) > 0 {
+		types = append(types, (<-events).Type)
+	}
+	assert.Contains(t, types, EventResetStarted)
+	assert.Contains(t, types, EventOverwriteCancelled)
+	assert.Contains(t, types, EventResetStep)
+	assert.Contains(t, types, EventResetFinished)
+
+	// the state before the overwrite has been cancelled is recorded.
+	records := st.audit.recorded(AuditReset)
+	require.Len(t, records, 1)
+	assert.Equal(t, Unlocked, records[0].PreviousState)
+}
+
+func TestSchedulerResetSequence(t *testing.T) {
+	t.Parallel()
+
+	st := newSchedulerTest(t, mondayMorning)
+	st.expectCall(Locked)
+
+	sequence, err := ParseResetSequence([]string{"lock 5s", "unlock"})
+	require.NoError(t, err)
+	st.dc.configLock.Lock()
+	st.dc.resetSequence = sequence
+	st.dc.configLock.Unlock()
+
+	events, unsubscribe := st.dc.Subscribe()
+	defer unsubscribe()
+
+	st.door.setError(errors.New("door offline"))
+
+	results := st.reset()
+	st.expectCall(Locked)
+
+	st.clock.BlockUntil(1)
+	st.clock.Advance(5 * time.Second)
+	st.expectCall(Unlocked)
+
+	steps := <-results
+	require.Len(t, steps, 2)
+	assert.Equal(t, Locked, steps[0].Action)
+	assert.Equal(t, "door offline", steps[0].Error)
+	assert.Equal(t, Unlocked, steps[1].Action)
+	assert.Equal(t, "door offline", steps[1].Error)
+
+	var resetSteps []*ResetStepResult
+	for len(events) > 0 {
+		if evt := <-events; evt.Type == EventResetStep {
+			resetSteps = append(resetSteps, evt.ResetStep)
+		}
+	}
+	require.Len(t, resetSteps, 2)
+	assert.Equal(t, 1, resetSteps[1].Step)
+	assert.Equal(t, "door offline", resetSteps[1].Error)
+}
+
+func TestParseResetSequence(t *testing.T) {
+	t.Parallel()
+
+	sequence, err := ParseResetSequence(nil)
+	require.NoError(t, err)
+	assert.Equal(t, defaultResetSequence, sequence)
+
+	sequence, err = ParseResetSequence([]string{"Unlock 1s", "open", "lock 500ms"})
+	require.NoError(t, err)
+	assert.Equal(t, []ResetStep{
+		{Action: Unlocked, Delay: time.Second},
+		{Action: Open},
+		{Action: Locked, Delay: 500 * time.Millisecond},
+	}, sequence)
+
+	for _, invalid := range []string{"", "close", "lock soon", "lock -1s", "lock 1s 2s"} {
+		_, err := ParseResetSequence([]string{invalid})
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestSchedulerLockdown(t *testing.T) {
+	t.Parallel()
+
+	st := newSchedulerTest(t, mondayMorning)
+	st.expectCall(Locked)
+
+	// nobody could release the lockdown without release roles.
+	_, err := st.dc.StartLockdown(st.ctx, "fire alarm")
+	require.ErrorIs(t, err, ErrNoLockdownReleaseRoles)
+	assert.Nil(t, st.dc.ActiveLockdown())
+
+	st.dc.configLock.Lock()
+	st.dc.lockdownReleaseRoles = []string{"security"}
+	st.dc.configLock.Unlock()
+
+	st.advanceTo(8, 0)
+	st.expectCall(Unlocked)
+
+	// a lockdown takes precedence over the opening hours ...
+	_, err = st.dc.StartLockdown(st.ctx, "fire alarm")
+	require.NoError(t, err)
+	st.expectCall(Locked)
+
+	state, _, _ := st.dc.Current(st.ctx)
+	assert.Equal(t, Locked, state)
+	assert.Equal(t, ReasonLockdown, st.dc.ReasonFor(st.ctx, st.clock.Now()))
+
+	// ... and any manual overwrite.
+	require.NoError(t, st.dc.Overwrite(st.ctx, Unlocked, st.at(11, 0)))
+	st.expectCall(Locked)
+
+	assert.ErrorIs(t, st.dc.Open(st.ctx, 0), ErrLockdownActive)
+
+	_, err = st.dc.Reset(st.ctx)
+	assert.ErrorIs(t, err, ErrLockdownActive)
+
+	// only users with a release role may release the lockdown.
+	user := session.WithUser(st.ctx, &idmv1.Profile{
+		Roles: []*idmv1.Role{{Id: "1", Name: "staff"}},
+	})
+	assert.ErrorIs(t, st.dc.ReleaseLockdown(user), ErrLockdownReleaseDenied)
+	assert.ErrorIs(t, st.dc.ReleaseLockdown(st.ctx), ErrLockdownReleaseDenied)
+
+	admin := session.WithUser(st.ctx, &idmv1.Profile{
+		Roles: []*idmv1.Role{{Id: "2", Name: "security"}},
+	})
+	require.NoError(t, st.dc.ReleaseLockdown(admin))
+	assert.Nil(t, st.dc.ActiveLockdown())
+
+	// the overwrite is active again.
+	st.expectCall(Unlocked)
+	assert.Equal(t, ReasonOverwrite, st.dc.ReasonFor(st.ctx, st.clock.Now()))
+
+	assert.ErrorIs(t, st.dc.ReleaseLockdown(admin), ErrNoLockdown)
+}
+
+func TestSchedulerClosureFollowsOpeningHours(t *testing.T) {
+	t.Parallel()
+
+	st := newSchedulerTest(t, mondayMorning)
+	st.expectCall(Locked)
+
+	require.NoError(t, st.dc.AddClosures(st.ctx, openinghours.Closure{
+		ID:     "team-event",
+		Dates:  []string{"2024-01-08"},
+		Reason: "Team event",
+	}))
+
+	// the clinic is closed but the door still follows the regular
+	// opening hours as the closure does not keep it locked.
+	assert.NotNil(t, st.dc.ClosureFor(st.ctx, st.clock.Now()))
+
+	st.advanceTo(8, 0)
+	st.expectCall(Unlocked)
+	assert.Equal(t, ReasonRegular, st.dc.ReasonFor(st.ctx, st.clock.Now()))
+
+	st.advanceTo(12, 0)
+	st.expectCall(Locked)
+}
+
+func TestSchedulerClosureKeepsDoorLocked(t *testing.T) {
+	t.Parallel()
+
+	st := newSchedulerTest(t, mondayMorning)
+	st.expectCall(Locked)
+
+	require.NoError(t, st.dc.AddClosures(st.ctx, openinghours.Closure{
+		ID:             "vacation",
+		Dates:          []string{"2024-01-08"},
+		Reason:         "Company holidays",
+		KeepDoorLocked: true,
+	}))
+
+	// the scheduler still wakes up at 08:00 but keeps the door locked.
+	st.advanceTo(8, 0)
+	st.expectCall(Locked)
+	st.expectNoCall()
+
+	state, _, _ := st.dc.Current(st.ctx)
+	assert.Equal(t, Locked, state)
+	assert.Equal(t, ReasonClosure, st.dc.ReasonFor(st.ctx, st.clock.Now()))
+}
@@ -0,0 +1,84 @@
+package door
+
+import (
+	"context"
+	"time"
+
+	"github.com/tierklinik-dobersberg/cis/internal/openinghours"
+)
+
+// Reason describes why the door is in a given state.
+type Reason string
+
+// Possible reasons for a door state.
+const (
+	ReasonRegular      = Reason(openinghours.SourceRegular)
+	ReasonDateSpecific = Reason(openinghours.SourceDateSpecific)
+	ReasonHoliday      = Reason(openinghours.SourceHoliday)
+	ReasonClosure      = Reason(openinghours.SourceClosure)
+	ReasonOverwrite    = Reason("overwrite")
+	ReasonLockdown     = Reason("lockdown")
+)
+
+// Transition describes a change of the desired door state.
+type Transition struct {
+	// Time is the time the door changes to State.
+	Time time.Time `json:"time"`
+	// State is the desired door state starting at Time.
+	State State `json:"state"`
+	// Until is the time of the next transition. It may be after
+	// the end of the simulated time window.
+	Until time.Time `json:"until,omitempty"`
+	// Reason describes why the door is in State.
+	Reason Reason `json:"reason"`
+}
+
+// Simulate returns all transitions of the desired door state between
+// from and to. The first transition always starts at from.
+func (dc *Controller) Simulate(ctx context.Context, from, to time.Time) []Transition {
+	var result []Transition
+
+	t := from.In(dc.Location())
+	for t.Before(to) {
+		state, until := dc.StateFor(ctx, t)
+		reason := dc.ReasonFor(ctx, t)
+
+		// if there's nothing scheduled the state does not change until
+		// the next day. Continue at midnight as the next day might have
+		// opening hours configured.
+		next := until
+		if next.IsZero() || !next.After(t) {
+			next = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		}
+
+		if len(result) > 0 && result[len(result)-1].State == state && result[len(result)-1].Reason == reason {
+			result[len(result)-1].Until = next
+		} else {
+			result = append(result, Transition{
+				Time:   t,
+				State:  state,
+				Until:  next,
+				Reason: reason,
+			})
+		}
+
+		t = next.In(dc.Location())
+	}
+
+	return result
+}
+
+// ReasonFor returns the reason for the desired door state at t.
+func (dc *Controller) ReasonFor(ctx context.Context, t time.Time) Reason {
+	if dc.lockdownAt(t) != nil {
+		return ReasonLockdown
+	}
+
+	if activeOverwrite(dc.getManualOverwrites(), t) != nil {
+		return ReasonOverwrite
+	}
+
+	_, source := dc.DoorHoursWithSource(ctx, t, dc.OpeningHours())
+
+	return Reason(source)
+}
@@ -0,0 +1,46 @@
+package door
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tierklinik-dobersberg/cis/internal/openinghours"
+)
+
+func TestSimulateClosures(t *testing.T) {
+	t.Parallel()
+
+	st := newSchedulerTest(t, mondayMorning)
+
+	tuesday := st.at(0, 0).AddDate(0, 0, 1)
+	nextMonday := st.at(0, 0).AddDate(0, 0, 7)
+
+	require.NoError(t, st.dc.AddClosures(st.ctx,
+		openinghours.Closure{
+			ID:     "team-event",
+			Dates:  []string{"2024-01-08"},
+			Reason: "Team event",
+		},
+		openinghours.Closure{
+			ID:             "vacation",
+			Dates:          []string{"2024-01-15"},
+			Reason:         "Company holidays",
+			KeepDoorLocked: true,
+		},
+	))
+
+	// the door still follows the regular opening hours during closures
+	// that do not keep it locked.
+	assert.Equal(t, []Transition{
+		{Time: st.at(7, 0), State: Locked, Until: st.at(8, 0), Reason: ReasonRegular},
+		{Time: st.at(8, 0), State: Unlocked, Until: st.at(12, 0), Reason: ReasonRegular},
+		{Time: st.at(12, 0), State: Locked, Until: tuesday, Reason: ReasonRegular},
+	}, st.dc.Simulate(st.ctx, st.at(7, 0), tuesday))
+
+	transitions := st.dc.Simulate(st.ctx, nextMonday, nextMonday.Add(24*time.Hour))
+	assert.Equal(t, []Transition{
+		{Time: nextMonday, State: Locked, Until: nextMonday.Add(24 * time.Hour), Reason: ReasonClosure},
+	}, transitions)
+}
@@ -3,6 +3,8 @@ package door
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/ppacher/system-conf/conf"
 	"github.com/tierklinik-dobersberg/cis/runtime"
@@ -13,36 +15,93 @@ var (
 	AddToSchema   = configBuilder.AddToSchema
 )
 
+// DefaultDoorName is the name of the door that is used if no door
+// is explicitly selected.
+const DefaultDoorName = "entry"
+
+// reservedDoorNames may not be used as door names because they are
+// used as static path segments by the door API and would shadow the
+// routes of the door.
+var reservedDoorNames = []string{
+	"doors",
+	"events",
+	"health",
+	"history",
+	"lockdown",
+	"open",
+	"overwrite",
+	"overwrites",
+	"reset",
+	"state",
+	"test",
+}
+
 type DoorConfig struct {
-	Type            string
-	ShellyScriptURL string
+	Name              string
+	DisplayName       string
+	OpeningHours      []string
+	Type              string
+	FailureWebhookURL string
+	OpenDuration      time.Duration
+	MaxOpenDuration   time.Duration
+	ResetSequence     []string
+
+	LockdownReleaseRoles []string
 }
 
-var Spec = conf.SectionSpec{
+// Spec is the option registry of the Door schema. It contains the
+// common door options, the Type option and the options of all
+// registered drivers.
+var Spec conf.OptionRegistry = doorSpec{}
+
+// commonSpec holds the options that apply to all door types.
+var commonSpec = conf.SectionSpec{
 	{
-		Name:        "Type",
+		Name:        "Name",
 		Required:    true,
-		Default:     "disabled",
-		Description: "The type of door interface to use",
+		Default:     DefaultDoorName,
+		Description: "A unique name for the door (compared case-insensitive). It is used to identify the door in the API and must not be one of " + strings.Join(reservedDoorNames, ", "),
 		Type:        conf.StringType,
+	},
+	{
+		Name:        "DisplayName",
+		Description: "A human readable name for the door",
+		Type:        conf.StringType,
+	},
+	{
+		Name:        "OpeningHours",
+		Description: "A list of opening hour definitions that control this door. If empty, all opening hours are used",
+		Type:        conf.StringSliceType,
 		Annotations: new(conf.Annotation).With(
-			runtime.OneOf(
-				runtime.PossibleValue{
-					Display: "Shelly Pro 2 (provided script)",
-					Value:   "shelly-script",
-				},
-				runtime.PossibleValue{
-					Display: "Disabled",
-					Value:   "disabled",
-				},
-			),
+			runtime.OneOfRef("OpeningHour", runtime.IDRef, "TimeRanges"),
 		),
 	},
 	{
-		Name:        "ShellyScriptURL",
+		Name:        "FailureWebhookURL",
+		Description: "A URL that receives a POST request with the scheduler health whenever the desired door state could not be applied for 5 minutes",
 		Type:        conf.StringType,
-		Description: "The URL to start the provided shelly script. Used only if Type is set to Shelly Pro 2",
-		Default:     "http://localhost/scripts/1/door",
+	},
+	{
+		Name:        "OpenDuration",
+		Type:        conf.DurationType,
+		Description: "How long the door is kept open if no duration is specified when opening it",
+		Default:     "10s",
+	},
+	{
+		Name:        "MaxOpenDuration",
+		Type:        conf.DurationType,
+		Description: "The maximum duration the door may be kept open",
+		Default:     "5m",
+	},
+	{
+		Name:        "ResetSequence",
+		Type:        conf.StringSliceType,
+		Description: "The steps performed when resetting the door in the format \"<action> [<delay>]\" where action is lock, unlock or open and delay is the time to wait afterwards. Defaults to \"unlock 2s\", \"lock 2s\", \"unlock\"",
+	},
+	{
+		Name:        "LockdownReleaseRoles",
+		Type:        conf.StringSliceType,
+		Description: "The IDs or names of roles that are permitted to release a lockdown of the door. A lockdown can only be started if at least one role is configured",
 	},
 }
 
@@ -78,14 +137,18 @@ func addDoorSchema(runtimeConfig *runtime.ConfigSchema) error {
 		Description: "Configure the door controller",
 		SVGData:     `<path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M8 11V7a4 4 0 118 0m-4 8v2m-6 4h12a2 2 0 002-2v-6a2 2 0 00-2-2H6a2 2 0 00-2 2v6a2 2 0 002 2z" />`,
 		Spec:        Spec,
-		Multi:       false,
+		Multi:       true,
+		Annotations: new(conf.Annotation).With(
+			runtime.OverviewFields("Name", "DisplayName", "Type"),
+			runtime.Unique("Name"),
+		),
 		Tests: []runtime.ConfigTest{
 			{
 				ID:   "test-door",
-				Name: "Test MQTT Door",
+				Name: "Test Door",
 				Spec: testSpec,
 				TestFunc: func(ctx context.Context, config, testConfig []conf.Option) (*runtime.TestResult, error) {
-					door, err := getTestDoor(ctx, runtimeConfig, config)
+					door, cfg, err := getTestDoor(ctx, runtimeConfig, config)
 					if err != nil {
 						return runtime.NewTestError(err), nil
 					}
@@ -102,7 +165,7 @@ func addDoorSchema(runtimeConfig *runtime.ConfigSchema) error {
 					case "unlock":
 						err = door.Unlock(ctx)
 					case "open":
-						err = door.Open(ctx)
+						err = door.Open(ctx, cfg.OpenDuration)
 					}
 
 					if err != nil {
@@ -113,40 +176,38 @@ func addDoorSchema(runtimeConfig *runtime.ConfigSchema) error {
 				},
 			},
 		},
+	}, runtime.Schema{
+		Name:        "DoorPermission",
+		DisplayName: "Door Permissions",
+		Description: "Grant door actions to roles. Users without a matching permission cannot view or control doors. As long as no permission is configured, authenticated users may only view the doors",
+		SVGData:     `<path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M9 12l2 2 4-4m5.618-4.016A11.955 11.955 0 0112 2.944a11.955 11.955 0 01-8.618 3.04A12.02 12.02 0 003 9c0 5.591 3.824 10.29 9 11.622 5.176-1.332 9-6.03 9-11.622 0-1.042-.133-2.052-.382-3.016z" />`,
+		Spec:        PermissionSpec,
+		Multi:       true,
+		Annotations: new(conf.Annotation).With(
+			runtime.OverviewFields("Roles", "Actions", "Doors"),
+		),
 	})
 }
 
-func getTestDoor(ctx context.Context, cs *runtime.ConfigSchema, config []conf.Option) (Interfacer, error) {
+func getTestDoor(ctx context.Context, cs *runtime.ConfigSchema, config []conf.Option) (Interfacer, DoorConfig, error) {
 	var cfg DoorConfig
-	if err := conf.DecodeSections(
-		conf.Sections{
-			{
-				Name:    "MQTT-Config",
-				Options: config,
-			},
-		},
-		Spec,
-		&cfg,
-	); err != nil {
-		return nil, fmt.Errorf("failed to decode configuration: %w", err)
-	}
-
-	switch cfg.Type {
 
-	case "shelly-script":
-		door := &ShellyScriptDoor{
-			url: cfg.ShellyScriptURL,
-		}
+	sec := conf.Section{
+		Name:    "Door",
+		Options: config,
+	}
 
-		return door, nil
+	if err := conf.DecodeSections(conf.Sections{sec}, Spec, &cfg); err != nil {
+		return nil, cfg, fmt.Errorf("failed to decode configuration: %w", err)
+	}
 
-	case "disabled":
-		return nil, fmt.Errorf("door control is disabled")
+	if cfg.Type == DisabledType {
+		return nil, cfg, fmt.Errorf("door control is disabled")
+	}
 
-	default:
-		return nil, fmt.Errorf("invalid door interface type: %q", cfg.Type)
+	door, err := newInterfacer(cfg, sec)
 
-	}
+	return door, cfg, err
 }
 
 func init() {
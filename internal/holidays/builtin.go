@@ -0,0 +1,149 @@
+package holidays
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// rule describes a public holiday that is either at a fixed date or
+// relative to easter sunday.
+type rule struct {
+	name string
+
+	// month and day are set for holidays at a fixed date.
+	month time.Month
+	day   int
+
+	// easterOffset is the number of days after easter sunday and
+	// only used if month is zero.
+	easterOffset int
+}
+
+func fixed(month time.Month, day int, name string) rule {
+	return rule{name: name, month: month, day: day}
+}
+
+func easterRelative(offset int, name string) rule {
+	return rule{name: name, easterOffset: offset}
+}
+
+// countryRules holds the nationwide public holidays for all countries
+// supported by the built-in provider.
+var countryRules = map[string][]rule{
+	"AT": {
+		fixed(time.January, 1, "Neujahr"),
+		fixed(time.January, 6, "Heilige Drei Könige"),
+		easterRelative(1, "Ostermontag"),
+		fixed(time.May, 1, "Staatsfeiertag"),
+		easterRelative(39, "Christi Himmelfahrt"),
+		easterRelative(50, "Pfingstmontag"),
+		easterRelative(60, "Fronleichnam"),
+		fixed(time.August, 15, "Mariä Himmelfahrt"),
+		fixed(time.October, 26, "Nationalfeiertag"),
+		fixed(time.November, 1, "Allerheiligen"),
+		fixed(time.December, 8, "Mariä Empfängnis"),
+		fixed(time.December, 25, "Christtag"),
+		fixed(time.December, 26, "Stefanitag"),
+	},
+	"DE": {
+		fixed(time.January, 1, "Neujahr"),
+		easterRelative(-2, "Karfreitag"),
+		easterRelative(1, "Ostermontag"),
+		fixed(time.May, 1, "Tag der Arbeit"),
+		easterRelative(39, "Christi Himmelfahrt"),
+		easterRelative(50, "Pfingstmontag"),
+		fixed(time.October, 3, "Tag der Deutschen Einheit"),
+		fixed(time.December, 25, "Erster Weihnachtstag"),
+		fixed(time.December, 26, "Zweiter Weihnachtstag"),
+	},
+}
+
+// SupportedCountries returns the ISO 2-letter codes of all countries
+// supported by the built-in provider.
+func SupportedCountries() []string {
+	countries := make([]string, 0, len(countryRules))
+	for country := range countryRules {
+		countries = append(countries, country)
+	}
+	sort.Strings(countries)
+
+	return countries
+}
+
+// NewBuiltin returns a provider that calculates the nationwide public
+// holidays of country in-process. Regional public holidays are not
+// supported.
+func NewBuiltin(country string) (Provider, error) {
+	rules, ok := countryRules[strings.ToUpper(country)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedCountry, country)
+	}
+
+	return &builtinProvider{rules: rules}, nil
+}
+
+type builtinProvider struct {
+	rules []rule
+}
+
+func (bp *builtinProvider) ForYear(_ context.Context, year int) ([]Holiday, error) {
+	return bp.forYear(year), nil
+}
+
+func (bp *builtinProvider) IsHoliday(_ context.Context, date time.Time) (bool, error) {
+	key := date.Format(DateFormat)
+	for _, h := range bp.forYear(date.Year()) {
+		if h.Date == key {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (bp *builtinProvider) forYear(year int) []Holiday {
+	easter := Easter(year)
+
+	result := make([]Holiday, len(bp.rules))
+	for idx, r := range bp.rules {
+		date := easter.AddDate(0, 0, r.easterOffset)
+		if r.month != 0 {
+			date = time.Date(year, r.month, r.day, 0, 0, 0, 0, time.UTC)
+		}
+
+		result[idx] = Holiday{
+			Date: date.Format(DateFormat),
+			Name: r.name,
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Date < result[j].Date
+	})
+
+	return result
+}
+
+// Easter returns the date of easter sunday in year using the
+// anonymous gregorian algorithm. The returned time is at midnight UTC.
+func Easter(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := ((h + l - 7*m + 114) % 31) + 1
+
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
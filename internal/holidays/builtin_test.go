@@ -0,0 +1,105 @@
+package holidays
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEaster(t *testing.T) {
+	t.Parallel()
+
+	cases := map[int]string{
+		2000: "2000-04-23",
+		2019: "2019-04-21",
+		2024: "2024-03-31",
+		2025: "2025-04-20",
+		2026: "2026-04-05",
+		2038: "2038-04-25",
+	}
+
+	for year, want := range cases {
+		assert.Equal(t, want, Easter(year).Format(DateFormat), year)
+	}
+}
+
+func TestBuiltin(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	at, err := NewBuiltin("at")
+	require.NoError(t, err)
+
+	holidays, err := at.ForYear(ctx, 2025)
+	require.NoError(t, err)
+	require.Len(t, holidays, 13)
+	assert.Equal(t, Holiday{Date: "2025-01-01", Name: "Neujahr"}, holidays[0])
+	assert.Contains(t, holidays, Holiday{Date: "2025-04-21", Name: "Ostermontag"})
+	assert.Contains(t, holidays, Holiday{Date: "2025-06-19", Name: "Fronleichnam"})
+	assert.Equal(t, Holiday{Date: "2025-12-26", Name: "Stefanitag"}, holidays[len(holidays)-1])
+
+	loc, err := time.LoadLocation("Europe/Vienna")
+	require.NoError(t, err)
+
+	isHoliday, err := at.IsHoliday(ctx, time.Date(2026, time.April, 6, 23, 30, 0, 0, loc))
+	require.NoError(t, err)
+	assert.True(t, isHoliday, "easter monday")
+
+	isHoliday, err = at.IsHoliday(ctx, time.Date(2026, time.April, 3, 10, 0, 0, 0, loc))
+	require.NoError(t, err)
+	assert.False(t, isHoliday, "good friday is not a public holiday in AT")
+
+	de, err := NewBuiltin("DE")
+	require.NoError(t, err)
+
+	isHoliday, err = de.IsHoliday(ctx, time.Date(2026, time.April, 3, 10, 0, 0, 0, loc))
+	require.NoError(t, err)
+	assert.True(t, isHoliday, "good friday")
+
+	isHoliday, err = de.IsHoliday(ctx, time.Date(2026, time.October, 3, 10, 0, 0, 0, loc))
+	require.NoError(t, err)
+	assert.True(t, isHoliday, "german unity day")
+
+	_, err = NewBuiltin("XX")
+	assert.ErrorIs(t, err, ErrUnsupportedCountry)
+}
+
+type failingProvider struct{}
+
+func (failingProvider) ForYear(context.Context, int) ([]Holiday, error) {
+	return nil, errors.New("unavailable")
+}
+
+func (failingProvider) IsHoliday(context.Context, time.Time) (bool, error) {
+	return false, errors.New("unavailable")
+}
+
+func TestFallback(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	at, err := NewBuiltin("AT")
+	require.NoError(t, err)
+
+	p := Fallback(failingProvider{}, at)
+
+	isHoliday, err := p.IsHoliday(ctx, time.Date(2026, time.April, 6, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.True(t, isHoliday)
+
+	holidays, err := p.ForYear(ctx, 2026)
+	require.NoError(t, err)
+	assert.Len(t, holidays, 13)
+
+	_, err = Fallback(at, failingProvider{}).ForYear(ctx, 2026)
+	assert.NoError(t, err)
+
+	_, err = Fallback(failingProvider{}, failingProvider{}).IsHoliday(ctx, time.Now())
+	assert.Error(t, err)
+}
@@ -0,0 +1,181 @@
+package holidays
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tierklinik-dobersberg/cis/pkg/clock"
+)
+
+// DefaultRefreshInterval is the interval at which the Cache reloads
+// public holidays from the underlying provider.
+const DefaultRefreshInterval = 6 * time.Hour
+
+// Cache is a Provider that caches the public holidays of the
+// underlying provider per year. Cached years are refreshed periodically
+// once the cache is started. If refreshing fails, the previously loaded
+// holidays are served instead.
+type Cache struct {
+	provider Provider
+	clock    clock.Clock
+	interval time.Duration
+
+	rw    sync.RWMutex
+	years map[int]cachedYear
+}
+
+type cachedYear struct {
+	holidays []Holiday
+	dates    map[string]struct{}
+	loadedAt time.Time
+}
+
+// NewCache returns a new holiday cache for provider that is refreshed
+// every interval. If interval is zero DefaultRefreshInterval is used.
+func NewCache(provider Provider, clk clock.Clock, interval time.Duration) *Cache {
+	if interval <= 0 {
+		interval = DefaultRefreshInterval
+	}
+
+	return &Cache{
+		provider: provider,
+		clock:    clk,
+		interval: interval,
+		years:    make(map[int]cachedYear),
+	}
+}
+
+// Start preloads the public holidays of the current and the next year
+// and keeps all cached years up-to-date until ctx is cancelled.
+func (c *Cache) Start(ctx context.Context) {
+	now := c.clock.Now()
+	for _, year := range []int{now.Year(), now.Year() + 1} {
+		if err := c.load(ctx, year); err != nil {
+			log.From(ctx).Errorf("failed to preload holidays for %d: %s", year, err)
+		}
+	}
+
+	go c.refreshLoop(ctx)
+}
+
+func (c *Cache) refreshLoop(ctx context.Context) {
+	timer := c.clock.NewTimer(c.interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C():
+		}
+
+		c.refresh(ctx)
+		timer.Reset(c.interval)
+	}
+}
+
+// refresh reloads all cached years as well as the current and the
+// next one.
+func (c *Cache) refresh(ctx context.Context) {
+	now := c.clock.Now()
+
+	c.rw.RLock()
+	years := []int{now.Year(), now.Year() + 1}
+	for year := range c.years {
+		if year != now.Year() && year != now.Year()+1 {
+			years = append(years, year)
+		}
+	}
+	c.rw.RUnlock()
+
+	sort.Ints(years)
+
+	for _, year := range years {
+		if err := c.load(ctx, year); err != nil {
+			log.From(ctx).Errorf("failed to refresh holidays for %d, serving stale data: %s", year, err)
+		}
+	}
+}
+
+// load loads the public holidays of year from the underlying provider
+// and replaces the cached ones on success.
+func (c *Cache) load(ctx context.Context, year int) error {
+	res, err := c.provider.ForYear(ctx, year)
+	if err != nil {
+		return err
+	}
+
+	entry := cachedYear{
+		holidays: res,
+		dates:    make(map[string]struct{}, len(res)),
+		loadedAt: c.clock.Now(),
+	}
+	for _, h := range res {
+		entry.dates[h.Date] = struct{}{}
+	}
+
+	c.rw.Lock()
+	defer c.rw.Unlock()
+
+	c.years[year] = entry
+
+	return nil
+}
+
+// get returns the cached holidays of year and loads them if year has
+// not been cached yet.
+func (c *Cache) get(ctx context.Context, year int) (cachedYear, error) {
+	c.rw.RLock()
+	entry, ok := c.years[year]
+	c.rw.RUnlock()
+
+	if ok {
+		return entry, nil
+	}
+
+	if err := c.load(ctx, year); err != nil {
+		return cachedYear{}, err
+	}
+
+	c.rw.RLock()
+	defer c.rw.RUnlock()
+
+	return c.years[year], nil
+}
+
+// ForYear implements Provider.
+func (c *Cache) ForYear(ctx context.Context, year int) ([]Holiday, error) {
+	entry, err := c.get(ctx, year)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]Holiday, len(entry.holidays))
+	copy(res, entry.holidays)
+
+	return res, nil
+}
+
+// IsHoliday implements Provider.
+func (c *Cache) IsHoliday(ctx context.Context, date time.Time) (bool, error) {
+	entry, err := c.get(ctx, date.Year())
+	if err != nil {
+		return false, err
+	}
+
+	_, ok := entry.dates[date.Format(DateFormat)]
+
+	return ok, nil
+}
+
+// LoadedAt returns the time the holidays of year have been loaded
+// successfully for the last time. It returns the zero time if year
+// is not cached.
+func (c *Cache) LoadedAt(year int) time.Time {
+	c.rw.RLock()
+	defer c.rw.RUnlock()
+
+	return c.years[year].loadedAt
+}
@@ -0,0 +1,116 @@
+package holidays
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tierklinik-dobersberg/cis/pkg/clock"
+)
+
+// stubProvider returns a single holiday on January 1st of each year
+// and counts the number of calls.
+type stubProvider struct {
+	lock  sync.Mutex
+	err   error
+	name  string
+	calls map[int]int
+}
+
+func (sp *stubProvider) ForYear(_ context.Context, year int) ([]Holiday, error) {
+	sp.lock.Lock()
+	defer sp.lock.Unlock()
+
+	sp.calls[year]++
+
+	if sp.err != nil {
+		return nil, sp.err
+	}
+
+	return []Holiday{
+		{Date: time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC).Format(DateFormat), Name: sp.name},
+	}, nil
+}
+
+func (sp *stubProvider) IsHoliday(context.Context, time.Time) (bool, error) {
+	panic("IsHoliday should not be called by the cache")
+}
+
+func (sp *stubProvider) set(name string, err error) {
+	sp.lock.Lock()
+	defer sp.lock.Unlock()
+
+	sp.name = name
+	sp.err = err
+}
+
+func (sp *stubProvider) callsFor(year int) int {
+	sp.lock.Lock()
+	defer sp.lock.Unlock()
+
+	return sp.calls[year]
+}
+
+func TestCache(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	clk := clock.NewFake(time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC))
+	stub := &stubProvider{name: "Neujahr", calls: make(map[int]int)}
+
+	cache := NewCache(stub, clk, time.Hour)
+	cache.Start(ctx)
+
+	// the current and the next year are preloaded
+	assert.Equal(t, 1, stub.callsFor(2026))
+	assert.Equal(t, 1, stub.callsFor(2027))
+
+	for i := 0; i < 10; i++ {
+		isHoliday, err := cache.IsHoliday(ctx, time.Date(2026, time.January, 1, 10, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		assert.True(t, isHoliday)
+	}
+
+	isHoliday, err := cache.IsHoliday(ctx, time.Date(2026, time.January, 2, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.False(t, isHoliday)
+	assert.Equal(t, 1, stub.callsFor(2026))
+
+	// other years are loaded on demand
+	_, err = cache.ForYear(ctx, 2030)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stub.callsFor(2030))
+
+	// stale data is served if refreshing fails
+	stub.set("", errors.New("unavailable"))
+	clk.BlockUntil(1)
+	clk.Advance(time.Hour)
+	clk.BlockUntil(1)
+
+	assert.Equal(t, 2, stub.callsFor(2026))
+	assert.Equal(t, 2, stub.callsFor(2030))
+
+	res, err := cache.ForYear(ctx, 2026)
+	require.NoError(t, err)
+	assert.Equal(t, []Holiday{{Date: "2026-01-01", Name: "Neujahr"}}, res)
+
+	// years that have never been loaded return the error
+	_, err = cache.ForYear(ctx, 2031)
+	assert.Error(t, err)
+
+	// the cache is updated once the provider is available again
+	stub.set("New Year", nil)
+	clk.Advance(time.Hour)
+	clk.BlockUntil(1)
+
+	res, err = cache.ForYear(ctx, 2026)
+	require.NoError(t, err)
+	assert.Equal(t, []Holiday{{Date: "2026-01-01", Name: "New Year"}}, res)
+	assert.Equal(t, clk.Now(), cache.LoadedAt(2026))
+}
@@ -0,0 +1,75 @@
+// Package holidays provides access to public holidays either by querying
+// the remote HolidayService or by calculating them in-process.
+package holidays
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tierklinik-dobersberg/cis/pkg/pkglog"
+)
+
+var log = pkglog.New("holidays")
+
+// DateFormat is the format of Holiday.Date.
+const DateFormat = "2006-01-02"
+
+// ErrUnsupportedCountry is returned by NewBuiltin if public holidays
+// cannot be calculated for a country.
+var ErrUnsupportedCountry = errors.New("unsupported country")
+
+// Holiday describes a single public holiday.
+type Holiday struct {
+	// Date is the date of the public holiday in the format YYYY-MM-DD.
+	Date string `json:"date"`
+
+	// Name is the localized name of the public holiday.
+	Name string `json:"name"`
+}
+
+// Provider provides public holidays.
+type Provider interface {
+	// ForYear returns all public holidays in year sorted by date.
+	ForYear(ctx context.Context, year int) ([]Holiday, error)
+
+	// IsHoliday reports whether or not date is a public holiday.
+	// Only the year, month and day of date are taken into account.
+	IsHoliday(ctx context.Context, date time.Time) (bool, error)
+}
+
+// Fallback returns a provider that queries primary and uses fallback
+// if primary fails.
+func Fallback(primary, fallback Provider) Provider {
+	return &fallbackProvider{
+		primary:  primary,
+		fallback: fallback,
+	}
+}
+
+type fallbackProvider struct {
+	primary  Provider
+	fallback Provider
+}
+
+func (fp *fallbackProvider) ForYear(ctx context.Context, year int) ([]Holiday, error) {
+	res, err := fp.primary.ForYear(ctx, year)
+	if err == nil {
+		return res, nil
+	}
+
+	log.From(ctx).Errorf("failed to load holidays for %d, using fallback: %s", year, err)
+
+	return fp.fallback.ForYear(ctx, year)
+}
+
+func (fp *fallbackProvider) IsHoliday(ctx context.Context, date time.Time) (bool, error) {
+	res, err := fp.primary.IsHoliday(ctx, date)
+	if err == nil {
+		return res, nil
+	}
+
+	log.From(ctx).Errorf("failed to check holiday at %s, using fallback: %s", date.Format(DateFormat), err)
+
+	return fp.fallback.IsHoliday(ctx, date)
+}
@@ -0,0 +1,68 @@
+package holidays
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	calendarv1 "github.com/tierklinik-dobersberg/apis/gen/go/tkd/calendar/v1"
+	"github.com/tierklinik-dobersberg/apis/gen/go/tkd/calendar/v1/calendarv1connect"
+)
+
+// NewServiceProvider returns a provider that queries public holidays
+// for country from the remote HolidayService. If country is empty the
+// default country of the HolidayService is used.
+func NewServiceProvider(cli calendarv1connect.HolidayServiceClient, country string) Provider {
+	return &serviceProvider{
+		cli:     cli,
+		country: country,
+	}
+}
+
+type serviceProvider struct {
+	cli     calendarv1connect.HolidayServiceClient
+	country string
+}
+
+func (sp *serviceProvider) ForYear(ctx context.Context, year int) ([]Holiday, error) {
+	res, err := sp.cli.GetHoliday(ctx, connect.NewRequest(&calendarv1.GetHolidayRequest{
+		Year:        uint64(year),
+		CountryCode: sp.country,
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Holiday, len(res.Msg.Holidays))
+	for idx, h := range res.Msg.Holidays {
+		result[idx] = Holiday{
+			Date: h.Date,
+			Name: h.LocalName,
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Date < result[j].Date
+	})
+
+	return result, nil
+}
+
+// IsHoliday is built on top of ForYear so both methods use the same
+// query and honor the configured country.
+func (sp *serviceProvider) IsHoliday(ctx context.Context, date time.Time) (bool, error) {
+	res, err := sp.ForYear(ctx, date.Year())
+	if err != nil {
+		return false, err
+	}
+
+	key := date.Format(DateFormat)
+	for _, h := range res {
+		if h.Date == key {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,116 @@
+package holidays
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	calendarv1 "github.com/tierklinik-dobersberg/apis/gen/go/tkd/calendar/v1"
+	"github.com/tierklinik-dobersberg/apis/gen/go/tkd/calendar/v1/calendarv1connect"
+	"github.com/tierklinik-dobersberg/cis/internal/cfgspec"
+)
+
+// fakeHolidayService returns the public holidays of each country.
+type fakeHolidayService struct {
+	calendarv1connect.HolidayServiceClient
+
+	holidays map[string][]string
+}
+
+func (fs *fakeHolidayService) GetHoliday(_ context.Context, req *connect.Request[calendarv1.GetHolidayRequest]) (*connect.Response[calendarv1.GetHolidayResponse], error) {
+	res := new(calendarv1.GetHolidayResponse)
+	for _, date := range fs.holidays[req.Msg.CountryCode] {
+		res.Holidays = append(res.Holidays, &calendarv1.PublicHoliday{
+			Date:      date,
+			LocalName: date,
+		})
+	}
+
+	return connect.NewResponse(res), nil
+}
+
+func TestServiceProviderCountry(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	cli := &fakeHolidayService{
+		holidays: map[string][]string{
+			"AT": {"2026-12-08"},
+			"DE": {"2026-10-03"},
+		},
+	}
+
+	cases := []struct {
+		country string
+		date    time.Time
+		holiday bool
+	}{
+		{"AT", time.Date(2026, time.December, 8, 0, 0, 0, 0, time.UTC), true},
+		{"AT", time.Date(2026, time.October, 3, 0, 0, 0, 0, time.UTC), false},
+		{"DE", time.Date(2026, time.October, 3, 0, 0, 0, 0, time.UTC), true},
+		{"DE", time.Date(2026, time.December, 8, 0, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, c := range cases {
+		isHoliday, err := NewServiceProvider(cli, c.country).IsHoliday(ctx, c.date)
+		require.NoError(t, err)
+		assert.Equal(t, c.holiday, isHoliday, "%s at %s", c.country, c.date)
+	}
+}
+
+// TestNewRetriesServiceDiscovery cannot run in parallel as it replaces
+// discoverService.
+func TestNewRetriesServiceDiscovery(t *testing.T) {
+	var service Provider
+
+	discoverService = func(context.Context, string) (Provider, error) {
+		if service == nil {
+			return nil, errors.New("consul not reachable")
+		}
+
+		return service, nil
+	}
+	defer func() {
+		discoverService = newServiceFromDiscovery
+	}()
+
+	ctx := context.Background()
+	cfg := cfgspec.Config{
+		Country:         "AT",
+		HolidayProvider: ProviderService,
+	}
+
+	// without fallback the holiday service is required.
+	_, err := New(ctx, cfg)
+	assert.Error(t, err)
+
+	cfg.HolidayFallback = true
+	p, err := New(ctx, cfg)
+	require.NoError(t, err)
+
+	// the built-in holidays are used as long as the service is not available ...
+	easterMonday := time.Date(2026, time.April, 6, 0, 0, 0, 0, time.UTC)
+	isHoliday, err := p.IsHoliday(ctx, easterMonday)
+	require.NoError(t, err)
+	assert.True(t, isHoliday)
+
+	// ... and the service is used as soon as it can be discovered.
+	service = NewServiceProvider(&fakeHolidayService{
+		holidays: map[string][]string{
+			"AT": {"2026-01-02"},
+		},
+	}, "AT")
+
+	isHoliday, err = p.IsHoliday(ctx, time.Date(2026, time.January, 2, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.True(t, isHoliday)
+
+	isHoliday, err = p.IsHoliday(ctx, easterMonday)
+	require.NoError(t, err)
+	assert.False(t, isHoliday)
+}
@@ -0,0 +1,126 @@
+package holidays
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tierklinik-dobersberg/apis/pkg/discovery/consuldiscover"
+	"github.com/tierklinik-dobersberg/apis/pkg/discovery/wellknown"
+	"github.com/tierklinik-dobersberg/cis/internal/cfgspec"
+)
+
+// Supported values for the HolidayProvider= option.
+const (
+	ProviderService = "service"
+	ProviderBuiltin = "builtin"
+)
+
+// discoverService returns a provider for the holiday service found in the
+// service catalog. It's replaced in tests.
+var discoverService = newServiceFromDiscovery
+
+// New returns the holiday provider configured in cfg. If the holiday
+// service is used and HolidayFallback= is enabled, each lookup tries the
+// holiday service first and uses the built-in provider if the service
+// cannot be reached.
+func New(ctx context.Context, cfg cfgspec.Config) (Provider, error) {
+	log := log.From(ctx)
+
+	switch strings.ToLower(cfg.HolidayProvider) {
+	case ProviderBuiltin:
+		return NewBuiltin(cfg.Country)
+
+	case "", ProviderService:
+		var fallback Provider
+		if cfg.HolidayFallback {
+			var err error
+			fallback, err = NewBuiltin(cfg.Country)
+			if err != nil {
+				log.Errorf("built-in holiday fallback not available: %s", err)
+			}
+		}
+
+		service := &discoveryProvider{
+			country: cfg.Country,
+		}
+
+		if _, err := service.get(ctx); err != nil {
+			if fallback == nil {
+				return nil, err
+			}
+
+			log.Errorf("holiday service not available, using built-in holidays until it can be reached: %s", err)
+		}
+
+		if fallback == nil {
+			return service, nil
+		}
+
+		return Fallback(service, fallback), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported holiday provider %q", cfg.HolidayProvider)
+	}
+}
+
+// discoveryProvider is a Provider that looks up the holiday service in
+// the service catalog. If the lookup fails it is retried on the next call.
+type discoveryProvider struct {
+	country string
+
+	lock     sync.Mutex
+	provider Provider
+}
+
+func (dp *discoveryProvider) get(ctx context.Context) (Provider, error) {
+	dp.lock.Lock()
+	defer dp.lock.Unlock()
+
+	if dp.provider != nil {
+		return dp.provider, nil
+	}
+
+	provider, err := discoverService(ctx, dp.country)
+	if err != nil {
+		return nil, err
+	}
+
+	dp.provider = provider
+
+	return provider, nil
+}
+
+func (dp *discoveryProvider) ForYear(ctx context.Context, year int) ([]Holiday, error) {
+	provider, err := dp.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return provider.ForYear(ctx, year)
+}
+
+func (dp *discoveryProvider) IsHoliday(ctx context.Context, date time.Time) (bool, error) {
+	provider, err := dp.get(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return provider.IsHoliday(ctx, date)
+}
+
+func newServiceFromDiscovery(ctx context.Context, country string) (Provider, error) {
+	disc, err := consuldiscover.NewFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get consul service catalog: %w", err)
+	}
+
+	cli, err := wellknown.HolidayService.Create(ctx, disc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get holiday service client: %w", err)
+	}
+
+	return NewServiceProvider(cli, country), nil
+}
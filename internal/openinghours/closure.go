@@ -0,0 +1,174 @@
+package openinghours
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ppacher/system-conf/conf"
+	"github.com/tierklinik-dobersberg/cis/runtime"
+)
+
+// Closure describes dates at which the clinic is closed even if there
+// would be opening hours otherwise (like team events or company holidays).
+// Closures are always reported as closed days but the entry door only
+// stays locked if KeepDoorLocked is set.
+type Closure struct {
+	ID string `json:"id" option:"-"`
+
+	// Dates is a list of dates or date ranges at which the clinic is
+	// closed. See Definition.UseAtDate for the supported formats.
+	Dates []string `json:"dates"`
+
+	// Reason describes why the clinic is closed.
+	Reason string `json:"reason"`
+
+	// KeepDoorLocked controls whether or not the entry door stays locked
+	// during the closure. If unset, the door still follows the opening
+	// hours that would apply without the closure.
+	KeepDoorLocked bool `json:"keepDoorLocked"`
+}
+
+// ClosureSpec describes the different configuration stanzas for the Closure struct.
+var ClosureSpec = conf.SectionSpec{
+	{
+		Name:        "Dates",
+		Description: "A list of dates at which the clinic is closed. Either MM/DD for recurring dates, YYYY-MM-DD for a specific date or YYYY-MM-DD - YYYY-MM-DD for an inclusive date range.",
+		Type:        conf.StringSliceType,
+		Required:    true,
+	},
+	{
+		Name:        "Reason",
+		Description: "The reason for the closure.",
+		Type:        conf.StringType,
+	},
+	{
+		Name:        "KeepDoorLocked",
+		Description: "Whether or not the entry door stays locked during the closure. If not set, the door still follows the opening hours that would apply otherwise.",
+		Type:        conf.BoolType,
+		Default:     "no",
+	},
+}
+
+func addClosures(s *runtime.ConfigSchema) error {
+	return s.Register(runtime.Schema{
+		Name:        "Closure",
+		DisplayName: "Schließtage",
+		Description: "Days and periods at which the clinic is closed",
+		Spec:        ClosureSpec,
+		Multi:       true,
+		SVGData:     `<path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M8 7V3m8 4V3m-9 8h10M5 21h14a2 2 0 002-2V7a2 2 0 00-2-2H5a2 2 0 00-2 2v12a2 2 0 002 2z" />`,
+		Annotations: new(conf.Annotation).With(
+			runtime.OverviewFields("Dates", "Reason", "KeepDoorLocked"),
+		),
+	})
+}
+
+func decodeClosure(sec *conf.Section) (Closure, error) {
+	var closure Closure
+
+	if sec != nil {
+		if err := conf.DecodeSections(conf.Sections{*sec}, ClosureSpec, &closure); err != nil {
+			return closure, err
+		}
+	}
+
+	return closure, nil
+}
+
+// closureListener validates and applies changes to the Closure
+// configuration.
+type closureListener struct {
+	ctrl *Controller
+}
+
+func (cl *closureListener) Validate(ctx context.Context, sec runtime.Section) error {
+	closure, err := decodeClosure(&sec.Section)
+	if err != nil {
+		return err
+	}
+	closure.ID = sec.ID
+
+	cl.ctrl.rw.RLock()
+	defer cl.ctrl.rw.RUnlock()
+
+	testState := cl.ctrl.state.clone()
+	if closure.ID != "" {
+		// the closure does not exist yet if it is about to be created.
+		_ = testState.deleteClosure(closure.ID)
+	}
+
+	return testState.addClosures(closure)
+}
+
+func (cl *closureListener) NotifyChange(ctx context.Context, changeType string, id string, sec *conf.Section) error {
+	closure, err := decodeClosure(sec)
+	if err != nil {
+		return err
+	}
+	closure.ID = id
+
+	cl.ctrl.rw.Lock()
+	defer cl.ctrl.rw.Unlock()
+
+	newState := cl.ctrl.state.clone()
+
+	// we delete for "delete" and "update".
+	if changeType != "create" {
+		if err := newState.deleteClosure(closure.ID); err != nil {
+			return fmt.Errorf("failed to delete: %w", err)
+		}
+	}
+
+	// we "create" for "create" and "update".
+	if changeType != "delete" {
+		if err := newState.addClosures(closure); err != nil {
+			return fmt.Errorf("failed to create: %w", err)
+		}
+	}
+
+	cl.ctrl.state = newState
+
+	// notify all subscribers that the opening hours changed
+	for _, fn := range cl.ctrl.notifier {
+		fn()
+	}
+
+	return nil
+}
+
+// AddClosures adds closures to the controller.
+func (ctrl *Controller) AddClosures(ctx context.Context, closures ...Closure) error {
+	ctrl.rw.Lock()
+	defer ctrl.rw.Unlock()
+
+	newState := ctrl.state.clone()
+
+	if err := newState.addClosures(closures...); err != nil {
+		return err
+	}
+
+	ctrl.state = newState
+
+	return nil
+}
+
+// ClosureFor returns the closure that applies to date or nil if the
+// clinic is not closed at date. Year specific closures take precedence
+// over recurring ones.
+func (ctrl *Controller) ClosureFor(ctx context.Context, date time.Time) *Closure {
+	ctrl.rw.RLock()
+	defer ctrl.rw.RUnlock()
+
+	return ctrl.closureFor(date)
+}
+
+func (ctrl *Controller) closureFor(date time.Time) *Closure {
+	for _, key := range dateKeys(date.In(ctrl.location)) {
+		if closure, ok := ctrl.state.Closures[key]; ok {
+			return &closure
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package openinghours
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ppacher/system-conf/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func closureSection(date, reason string) conf.Section {
+	return conf.Section{
+		Name: "Closure",
+		Options: conf.Options{
+			{Name: "Dates", Value: date},
+			{Name: "Reason", Value: reason},
+		},
+	}
+}
+
+func TestClosures(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ctrl := newTestController(t)
+
+	require.NoError(t, ctrl.AddOpeningHours(ctx, Definition{
+		id:         "regular",
+		OnWeekday:  []string{"Mon", "Tue", "Wed", "Thu", "Fri"},
+		TimeRanges: []string{"08:00 - 12:00"},
+	}))
+
+	require.NoError(t, ctrl.AddClosures(ctx,
+		Closure{
+			ID:             "vacation",
+			Dates:          []string{"2026-08-03 - 2026-08-07"},
+			Reason:         "Company holidays",
+			KeepDoorLocked: true,
+		},
+		Closure{
+			ID:     "team-event",
+			Dates:  []string{"2026-08-10"},
+			Reason: "Team event",
+		},
+	))
+
+	vacation := time.Date(2026, time.August, 5, 0, 0, 0, 0, ctrl.Location())
+	teamEvent := time.Date(2026, time.August, 10, 0, 0, 0, 0, ctrl.Location())
+	regular := time.Date(2026, time.August, 11, 0, 0, 0, 0, ctrl.Location())
+
+	closure := ctrl.ClosureFor(ctx, vacation)
+	require.NotNil(t, closure)
+	assert.Equal(t, "Company holidays", closure.Reason)
+	assert.Nil(t, ctrl.ClosureFor(ctx, regular))
+
+	// closures always apply to the opening hours ...
+	for _, date := range []time.Time{vacation, teamEvent} {
+		ranges, source := ctrl.ForDateWithSource(ctx, date, nil)
+		assert.Empty(t, ranges, date)
+		assert.Equal(t, SourceClosure, source, date)
+	}
+
+	// ... but only affect the door if it should be kept locked.
+	ranges, source := ctrl.DoorHoursWithSource(ctx, vacation, nil)
+	assert.Empty(t, ranges)
+	assert.Equal(t, SourceClosure, source)
+
+	ranges, source = ctrl.DoorHoursWithSource(ctx, teamEvent, nil)
+	assert.Len(t, ranges, 1)
+	assert.Equal(t, SourceRegular, source)
+
+	assert.Empty(t, ctrl.UpcomingFramesFor(ctx, teamEvent, 1, nil))
+	assert.Len(t, ctrl.UpcomingDoorFramesFor(ctx, teamEvent, 1, nil), 1)
+
+	// a date cannot be closed twice
+	err := ctrl.AddClosures(ctx, Closure{
+		ID:    "duplicate",
+		Dates: []string{"2026-08-07 - 2026-08-08"},
+	})
+	assert.Error(t, err)
+}
+
+func TestClosureListener(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ctrl := newTestController(t)
+	cl := &closureListener{ctrl: ctrl}
+
+	date := time.Date(2026, time.December, 27, 0, 0, 0, 0, ctrl.Location())
+
+	sec := closureSection("2026-12-27", "Inventory")
+	require.NoError(t, cl.NotifyChange(ctx, "create", "1", &sec))
+	require.NotNil(t, ctrl.ClosureFor(ctx, date))
+	assert.Equal(t, "1", ctrl.ClosureFor(ctx, date).ID)
+
+	updated := closureSection("2026-12-28", "Inventory")
+	require.NoError(t, cl.NotifyChange(ctx, "update", "1", &updated))
+	assert.Nil(t, ctrl.ClosureFor(ctx, date))
+	assert.NotNil(t, ctrl.ClosureFor(ctx, date.AddDate(0, 0, 1)))
+
+	require.NoError(t, cl.NotifyChange(ctx, "delete", "1", nil))
+	assert.Nil(t, ctrl.ClosureFor(ctx, date.AddDate(0, 0, 1)))
+}
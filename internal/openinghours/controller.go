@@ -4,18 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"sync"
 	"time"
 
-	"github.com/bufbuild/connect-go"
 	"github.com/ppacher/system-conf/conf"
-	calendarv1 "github.com/tierklinik-dobersberg/apis/gen/go/tkd/calendar/v1"
-	"github.com/tierklinik-dobersberg/apis/gen/go/tkd/calendar/v1/calendarv1connect"
-	commonv1 "github.com/tierklinik-dobersberg/apis/gen/go/tkd/common/v1"
-	"github.com/tierklinik-dobersberg/apis/pkg/discovery/consuldiscover"
-	"github.com/tierklinik-dobersberg/apis/pkg/discovery/wellknown"
 	"github.com/tierklinik-dobersberg/cis/internal/cfgspec"
+	"github.com/tierklinik-dobersberg/cis/internal/holidays"
+	"github.com/tierklinik-dobersberg/cis/pkg/clock"
 	"github.com/tierklinik-dobersberg/cis/pkg/daytime"
 	"github.com/tierklinik-dobersberg/cis/pkg/pkglog"
 	"github.com/tierklinik-dobersberg/cis/runtime"
@@ -23,6 +20,18 @@ import (
 
 var log = pkglog.New("openinghours")
 
+// Source describes where the opening hours of a given day
+// have been taken from.
+type Source string
+
+// Possible opening hour sources.
+const (
+	SourceRegular      = Source("regular")
+	SourceDateSpecific = Source("date-specific")
+	SourceHoliday      = Source("holiday")
+	SourceClosure      = Source("closure")
+)
+
 type (
 	// ChangeNotifyFunc can be registered at the Controller to get notified
 	// when new opening hours have been configured.
@@ -39,7 +48,10 @@ type (
 		// to retrieve the correct list of public holidays.
 		country string
 
-		holidays calendarv1connect.HolidayServiceClient
+		holidays holidays.Provider
+
+		// clock is used to determine the current time.
+		clock clock.Clock
 
 		state *state
 	}
@@ -47,32 +59,21 @@ type (
 
 // New returns a new opening hour controller.
 func New(ctx context.Context, cfg cfgspec.Config, globalSchema *runtime.ConfigSchema) (*Controller, error) {
-	loc, err := time.LoadLocation(cfg.TimeZone)
+	provider, err := holidays.New(ctx, cfg)
 	if err != nil {
-		return nil, fmt.Errorf("option Location: %w", err)
+		return nil, fmt.Errorf("failed to get holiday provider: %w", err)
 	}
 
-	disc, err := consuldiscover.NewFromEnv()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get consul service catalog: %w", err)
-	}
+	// public holidays are cached and refreshed in the background so
+	// we don't need to query the provider on each lookup.
+	cache := holidays.NewCache(provider, clock.System, holidays.DefaultRefreshInterval)
 
-	holidays, err := wellknown.HolidayService.Create(ctx, disc)
+	ctrl, err := NewController(cfg, cache, clock.System)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get holiday service client: %w", err)
+		return nil, err
 	}
 
-	ctrl := &Controller{
-		location: loc,
-		country:  cfg.Country,
-		holidays: holidays,
-		state: &state{
-			Regular:           make(map[time.Weekday][]OpeningHour),
-			DateSpecific:      make(map[string][]OpeningHour),
-			defaultCloseAfter: cfg.DefaultCloseAfter,
-			defaultOpenBefore: cfg.DefaultOpenBefore,
-		},
-	}
+	cache.Start(ctx)
 
 	globalSchema.AddValidator(ctrl, "OpeningHour")
 	globalSchema.AddNotifier(ctrl, "OpeningHour")
@@ -87,9 +88,48 @@ func New(ctx context.Context, cfg cfgspec.Config, globalSchema *runtime.ConfigSc
 		}
 	}
 
+	closures := &closureListener{ctrl: ctrl}
+	globalSchema.AddValidator(closures, "Closure")
+	globalSchema.AddNotifier(closures, "Closure")
+
+	sections, err = globalSchema.All(ctx, "Closure")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get existing closures: %w", err)
+	}
+	for _, def := range sections {
+		if err := closures.NotifyChange(ctx, "create", def.ID, &def.Section); err != nil {
+			return nil, fmt.Errorf("failed to create closure %s: %w", def.ID, err)
+		}
+	}
+
 	return ctrl, nil
 }
 
+// NewController returns a new opening hour controller that uses provider
+// to detect public holidays and clk to determine the current time. Other
+// than New, the controller is not bound to the configuration schema so
+// opening hours must be added using AddOpeningHours.
+func NewController(cfg cfgspec.Config, provider holidays.Provider, clk clock.Clock) (*Controller, error) {
+	loc, err := time.LoadLocation(cfg.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("option Location: %w", err)
+	}
+
+	return &Controller{
+		location: loc,
+		country:  cfg.Country,
+		holidays: provider,
+		clock:    clk,
+		state: &state{
+			Regular:           make(map[time.Weekday][]OpeningHour),
+			DateSpecific:      make(map[string][]OpeningHour),
+			Closures:          make(map[string]Closure),
+			defaultCloseAfter: cfg.DefaultCloseAfter,
+			defaultOpenBefore: cfg.DefaultOpenBefore,
+		},
+	}, nil
+}
+
 func decodeOpeningHour(sec *conf.Section) (Definition, error) {
 	var entry Definition
 
@@ -188,13 +228,34 @@ func (ctrl *Controller) AddOpeningHours(ctx context.Context, timeRanges ...Defin
 }
 
 func (ctrl *Controller) UpcomingFrames(ctx context.Context, dateTime time.Time, limit int) []daytime.TimeRange {
+	return ctrl.UpcomingFramesFor(ctx, dateTime, limit, nil)
+}
+
+// UpcomingFramesFor is like UpcomingFrames but only considers opening hours
+// that have been created from one of the definitions listed in ids. If ids is
+// empty, all opening hours are considered.
+func (ctrl *Controller) UpcomingFramesFor(ctx context.Context, dateTime time.Time, limit int, ids []string) []daytime.TimeRange {
+	ctrl.rw.RLock()
+	defer ctrl.rw.RUnlock()
+
+	return ctrl.upcomingFrames(ctx, dateTime, limit, ids, false)
+}
+
+// UpcomingDoorFramesFor is like UpcomingFramesFor but only honors closures
+// that keep the entry door locked.
+func (ctrl *Controller) UpcomingDoorFramesFor(ctx context.Context, dateTime time.Time, limit int, ids []string) []daytime.TimeRange {
 	ctrl.rw.RLock()
 	defer ctrl.rw.RUnlock()
 
+	return ctrl.upcomingFrames(ctx, dateTime, limit, ids, true)
+}
+
+func (ctrl *Controller) upcomingFrames(ctx context.Context, dateTime time.Time, limit int, ids []string, door bool) []daytime.TimeRange {
 	var result []daytime.TimeRange
 
 	for len(result) < limit {
-		ranges := ctrl.forDate(ctx, dateTime)
+		ranges, _ := ctrl.forDateWithSource(ctx, dateTime, door)
+		ranges = filterByID(ranges, ids)
 
 		if len(ranges) == 0 {
 			break
@@ -240,49 +301,106 @@ func (ctrl *Controller) UpcomingFrames(ctx context.Context, dateTime time.Time,
 }
 
 func (ctrl *Controller) ForDate(ctx context.Context, date time.Time) []OpeningHour {
+	return ctrl.ForDateFor(ctx, date, nil)
+}
+
+// ForDateFor is like ForDate but only returns opening hours that have been
+// created from one of the definitions listed in ids. If ids is empty, all
+// opening hours are returned.
+func (ctrl *Controller) ForDateFor(ctx context.Context, date time.Time, ids []string) []OpeningHour {
+	ctrl.rw.RLock()
+	defer ctrl.rw.RUnlock()
+
+	return filterByID(ctrl.forDate(ctx, date), ids)
+}
+
+// ForDateWithSource is like ForDateFor but also returns the source of
+// the returned opening hours.
+func (ctrl *Controller) ForDateWithSource(ctx context.Context, date time.Time, ids []string) ([]OpeningHour, Source) {
 	ctrl.rw.RLock()
 	defer ctrl.rw.RUnlock()
 
-	return ctrl.forDate(ctx, date)
+	ranges, source := ctrl.forDateWithSource(ctx, date, false)
+
+	return filterByID(ranges, ids), source
+}
+
+// DoorHoursWithSource is like ForDateWithSource but only honors closures
+// that keep the entry door locked.
+func (ctrl *Controller) DoorHoursWithSource(ctx context.Context, date time.Time, ids []string) ([]OpeningHour, Source) {
+	ctrl.rw.RLock()
+	defer ctrl.rw.RUnlock()
+
+	ranges, source := ctrl.forDateWithSource(ctx, date, true)
+
+	return filterByID(ranges, ids), source
+}
+
+func filterByID(ranges []OpeningHour, ids []string) []OpeningHour {
+	if len(ids) == 0 {
+		return ranges
+	}
+
+	result := make([]OpeningHour, 0, len(ranges))
+	for _, oh := range ranges {
+		if slices.Contains(ids, oh.ID) {
+			result = append(result, oh)
+		}
+	}
+
+	return result
 }
 
 func (ctrl *Controller) forDate(ctx context.Context, date time.Time) []OpeningHour {
+	ranges, _ := ctrl.forDateWithSource(ctx, date, false)
+
+	return ranges
+}
+
+// forDateWithSource returns the opening hours for date. If door is set,
+// closures that do not keep the entry door locked are ignored.
+func (ctrl *Controller) forDateWithSource(ctx context.Context, date time.Time, door bool) ([]OpeningHour, Source) {
 	date = date.In(ctrl.location)
 
 	log := log.From(ctx)
-	key := fmt.Sprintf("%02d/%02d", date.Month(), date.Day())
 
-	// First we check for date specific overwrites ...
-	ranges, ok := ctrl.state.DateSpecific[key]
-	if ok {
-		return ranges
+	// Closures take precedence over everything else ...
+	if closure := ctrl.closureFor(date); closure != nil && (!door || closure.KeepDoorLocked) {
+		return nil, SourceClosure
 	}
 
-	// Check if we need to use holiday ranges ...
-	isHoliday, err := ctrl.holidays.IsHoliday(ctx, connect.NewRequest(&calendarv1.IsHolidayRequest{
-		Date: &commonv1.Date{
-			Year:  int64(date.Year()),
-			Month: commonv1.Month(date.Month()),
-			Day:   int32(date.Day()),
-		},
-	}))
+	// ... then we check for date specific overwrites. Year specific
+	// dates take precedence over recurring ones ...
+	for _, key := range dateKeys(date) {
+		if ranges, ok := ctrl.state.DateSpecific[key]; ok {
+			return ranges, SourceDateSpecific
+		}
+	}
 
+	// Check if we need to use holiday ranges. If we cannot tell whether
+	// date is a public holiday we fail safe and treat it as one rather
+	// than opening on a public holiday.
+	isHoliday, err := ctrl.holidays.IsHoliday(ctx, date)
 	if err != nil {
-		log.Errorf("failed to load holidays: %s", err.Error())
-	} else if isHoliday.Msg.IsHoliday {
-		return ctrl.state.Holiday
+		log.Errorf("failed to load holidays, treating %s as a public holiday: %s", date.Format(holidays.DateFormat), err.Error())
+
+		return ctrl.state.Holiday, SourceHoliday
+	}
+
+	if isHoliday {
+		return ctrl.state.Holiday, SourceHoliday
 	}
 
 	// Finally use the regular opening hours
-	ranges, ok = ctrl.state.Regular[date.Weekday()]
+	ranges, ok := ctrl.state.Regular[date.Weekday()]
 	if ok {
-		return ranges
+		return ranges, SourceRegular
 	}
 
 	// There are no ranges for that day!
 	log.V(4).Logf("No opening hour ranges found for %s", date)
 
-	return nil
+	return nil, SourceRegular
 }
 
 // Location returns the location the controller is configured for.
@@ -290,6 +408,21 @@ func (ctrl *Controller) Location() *time.Location {
 	return ctrl.location
 }
 
+// Clock returns the clock used by the controller.
+func (ctrl *Controller) Clock() clock.Clock {
+	return ctrl.clock
+}
+
+// Now returns the current time in the location of the controller.
+func (ctrl *Controller) Now() time.Time {
+	return ctrl.clock.Now().In(ctrl.location)
+}
+
+// Holidays returns the provider used to detect public holidays.
+func (ctrl *Controller) Holidays() holidays.Provider {
+	return ctrl.holidays
+}
+
 // Country returns the name of the country the controller is configured
 // for. The country is important to detect public holidays.
 func (ctrl *Controller) Country() string {
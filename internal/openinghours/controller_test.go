@@ -0,0 +1,191 @@
+package openinghours
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tierklinik-dobersberg/cis/internal/cfgspec"
+	"github.com/tierklinik-dobersberg/cis/internal/holidays"
+	"github.com/tierklinik-dobersberg/cis/pkg/clock"
+)
+
+type noHolidays struct{}
+
+func (noHolidays) ForYear(context.Context, int) ([]holidays.Holiday, error) {
+	return nil, nil
+}
+
+func (noHolidays) IsHoliday(context.Context, time.Time) (bool, error) {
+	return false, nil
+}
+
+// failingHolidays is a holiday provider that is not available.
+type failingHolidays struct{}
+
+func (failingHolidays) ForYear(context.Context, int) ([]holidays.Holiday, error) {
+	return nil, errors.New("holiday service unavailable")
+}
+
+func (failingHolidays) IsHoliday(context.Context, time.Time) (bool, error) {
+	return false, errors.New("holiday service unavailable")
+}
+
+func newTestController(t *testing.T) *Controller {
+	t.Helper()
+
+	ctrl, err := NewController(cfgspec.Config{
+		TimeZone: "Europe/Vienna",
+	}, noHolidays{}, clock.System)
+	require.NoError(t, err)
+
+	return ctrl
+}
+
+func TestForDateYearSpecific(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ctrl := newTestController(t)
+
+	require.NoError(t, ctrl.AddOpeningHours(ctx,
+		Definition{
+			id:         "regular",
+			OnWeekday:  []string{"Mon", "Tue", "Wed", "Thu", "Fri"},
+			TimeRanges: []string{"08:00 - 12:00"},
+		},
+		Definition{
+			id:         "recurring",
+			UseAtDate:  []string{"08/10"},
+			TimeRanges: []string{"10:00 - 11:00"},
+		},
+		Definition{
+			id:         "summer",
+			UseAtDate:  []string{"2026-08-03 - 2026-08-16"},
+			TimeRanges: []string{"09:00 - 10:00"},
+		},
+		Definition{
+			id:         "closed",
+			UseAtDate:  []string{"2026-12-27"},
+			TimeRanges: []string{"00:00 - 00:01"},
+		},
+	))
+
+	cases := []struct {
+		date   time.Time
+		id     string
+		source Source
+	}{
+		{time.Date(2026, time.August, 3, 0, 0, 0, 0, ctrl.Location()), "summer", SourceDateSpecific},
+		{time.Date(2026, time.August, 16, 0, 0, 0, 0, ctrl.Location()), "summer", SourceDateSpecific},
+		// year specific dates take precedence over recurring ones
+		{time.Date(2026, time.August, 10, 0, 0, 0, 0, ctrl.Location()), "summer", SourceDateSpecific},
+		{time.Date(2027, time.August, 10, 0, 0, 0, 0, ctrl.Location()), "recurring", SourceDateSpecific},
+		{time.Date(2026, time.August, 17, 0, 0, 0, 0, ctrl.Location()), "regular", SourceRegular},
+		{time.Date(2026, time.December, 27, 0, 0, 0, 0, ctrl.Location()), "closed", SourceDateSpecific},
+	}
+
+	for _, c := range cases {
+		ranges, source := ctrl.ForDateWithSource(ctx, c.date, nil)
+		require.Len(t, ranges, 1, c.date)
+		assert.Equal(t, c.id, ranges[0].ID, c.date)
+		assert.Equal(t, c.source, source, c.date)
+	}
+}
+
+func TestParseDates(t *testing.T) {
+	t.Parallel()
+
+	s := &state{}
+
+	dates, err := s.parseDates(Definition{
+		UseAtDate: []string{"1/2", "2025-12-30 - 2026-01-02", " 2026-02-28 "},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"01/02",
+		"2025-12-30", "2025-12-31", "2026-01-01", "2026-01-02",
+		"2026-02-28",
+	}, dates)
+
+	for _, invalid := range []string{
+		"13/01",
+		"2026-02-30",
+		"2026-08-16 - 2026-08-03",
+		"2026-01-01 - 2027-06-01",
+		"2026/01/01",
+	} {
+		_, err := s.parseDates(Definition{UseAtDate: []string{invalid}})
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestDateSpecificOverlap(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ctrl := newTestController(t)
+
+	require.NoError(t, ctrl.AddOpeningHours(ctx, Definition{
+		id:         "summer",
+		UseAtDate:  []string{"2026-08-03 - 2026-08-16"},
+		TimeRanges: []string{"09:00 - 10:00"},
+	}))
+
+	// recurring dates do not overlap with year specific ones
+	require.NoError(t, ctrl.AddOpeningHours(ctx, Definition{
+		id:         "recurring",
+		UseAtDate:  []string{"08/10"},
+		TimeRanges: []string{"09:00 - 10:00"},
+	}))
+
+	err := ctrl.AddOpeningHours(ctx, Definition{
+		id:         "overlap",
+		UseAtDate:  []string{"2026-08-10"},
+		TimeRanges: []string{"09:30 - 11:00"},
+	})
+	assert.ErrorContains(t, err, "overlapping time frames")
+
+	require.NoError(t, ctrl.AddOpeningHours(ctx, Definition{
+		id:         "afternoon",
+		UseAtDate:  []string{"2026-08-10"},
+		TimeRanges: []string{"14:00 - 16:00"},
+	}))
+}
+
+func TestForDateHolidayProviderFails(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ctrl, err := NewController(cfgspec.Config{
+		TimeZone: "Europe/Vienna",
+	}, failingHolidays{}, clock.System)
+	require.NoError(t, err)
+
+	require.NoError(t, ctrl.AddOpeningHours(ctx,
+		Definition{
+			id:         "regular",
+			OnWeekday:  []string{"Mon"},
+			TimeRanges: []string{"08:00 - 12:00"},
+			Holiday:    "no",
+		},
+		Definition{
+			id:         "holiday",
+			TimeRanges: []string{"10:00 - 11:00"},
+			Holiday:    "only",
+		},
+	))
+
+	// Easter Monday must not be treated as a regular day just because
+	// the holiday provider is not available.
+	easterMonday := time.Date(2026, time.April, 6, 0, 0, 0, 0, ctrl.Location())
+
+	ranges, source := ctrl.ForDateWithSource(ctx, easterMonday, nil)
+	assert.Equal(t, SourceHoliday, source)
+	require.Len(t, ranges, 1)
+	assert.Equal(t, "holiday", ranges[0].ID)
+}
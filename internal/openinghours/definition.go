@@ -18,7 +18,9 @@ type Definition struct {
 	OnWeekday []string
 
 	// UseAtDate is a list of dates on which this opening hours take effect.
-	// UseAtDate should have the format MM/DD and are year independent.
+	// Entries in the format MM/DD are year independent while YYYY-MM-DD
+	// only applies to the given date. Inclusive date ranges can be
+	// specified as YYYY-MM-DD - YYYY-MM-DD.
 	UseAtDate []string
 
 	// OpenBefore describes the amount of time the entry door
@@ -77,7 +79,7 @@ var Spec = conf.SectionSpec{
 	},
 	{
 		Name:        "UseAtDate",
-		Description: "A list of dates at which this section takes effect. Format is defined MM/DD",
+		Description: "A list of dates at which this section takes effect. Either MM/DD for recurring dates, YYYY-MM-DD for a specific date or YYYY-MM-DD - YYYY-MM-DD for an inclusive date range. Year specific dates take precedence over recurring ones.",
 		Type:        conf.StringSliceType,
 	},
 	{
@@ -17,12 +17,17 @@ type state struct {
 	// DateSpecific contains opening hours that are used
 	// instead of the regular opening hours at special days
 	// during the year (like unofficial holidays or as a holiday
-	// overwrite). The map key has the format "MM/DD".
+	// overwrite). The map key has either the format "MM/DD" for
+	// recurring dates or "YYYY-MM-DD" for year specific ones.
 	DateSpecific map[string][]OpeningHour `json:"dateSpecific"`
 	// Holiday specifies the opening hours during
 	// public holidays.
 	Holiday []OpeningHour `json:"holiday"`
 
+	// Closures holds all days at which the clinic is closed. The map
+	// key has the same format as the one of DateSpecific.
+	Closures map[string]Closure `json:"closures"`
+
 	defaultCloseAfter time.Duration
 	defaultOpenBefore time.Duration
 }
@@ -32,6 +37,7 @@ func (s *state) clone() *state {
 		Regular:           make(map[time.Weekday][]OpeningHour, len(s.Regular)),
 		DateSpecific:      make(map[string][]OpeningHour, len(s.DateSpecific)),
 		Holiday:           make([]OpeningHour, len(s.Holiday)),
+		Closures:          make(map[string]Closure, len(s.Closures)),
 		defaultCloseAfter: s.defaultCloseAfter,
 		defaultOpenBefore: s.defaultOpenBefore,
 	}
@@ -53,6 +59,12 @@ func (s *state) clone() *state {
 		newState.DateSpecific[dateStr] = clone
 	}
 
+	// closures are immutable once added so we only need to copy
+	// the map itself.
+	for dateStr, closure := range s.Closures {
+		newState.Closures[dateStr] = closure
+	}
+
 	return newState
 }
 
@@ -139,36 +151,151 @@ func (s *state) parseWeekDays(openingHourDef Definition) ([]time.Weekday, error)
 	return days, nil
 }
 
+func (s *state) addClosures(closures ...Closure) error {
+	for _, closure := range closures {
+		dates, err := s.parseDates(Definition{UseAtDate: closure.Dates})
+		if err != nil {
+			return err
+		}
+
+		if len(dates) == 0 {
+			return fmt.Errorf("no dates defined in closure")
+		}
+
+		for _, d := range dates {
+			if existing, ok := s.Closures[d]; ok {
+				return fmt.Errorf("closure: %s is already closed by %s", d, existing.ID)
+			}
+
+			s.Closures[d] = closure
+		}
+	}
+
+	return nil
+}
+
+func (s *state) deleteClosure(id string) error {
+	found := false
+	for dateStr, closure := range s.Closures {
+		if closure.ID == id {
+			found = true
+
+			delete(s.Closures, dateStr)
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("closure: id %q not found in controller state", id)
+	}
+
+	return nil
+}
+
+// dateKeys returns the keys used to lookup date specific opening hours
+// and closures for date, ordered by precedence.
+func dateKeys(date time.Time) []string {
+	return []string{
+		date.Format(dateFormat),
+		fmt.Sprintf("%02d/%02d", date.Month(), date.Day()),
+	}
+}
+
+// dateFormat is the layout of year specific dates used in UseAtDate.
+const dateFormat = "2006-01-02"
+
+// maxDateRangeDays limits the number of days a single date range in
+// UseAtDate may span.
+const maxDateRangeDays = 366
+
 func (s *state) parseDates(c Definition) ([]string, error) {
 	dates := make([]string, 0, len(c.UseAtDate))
 	for _, dateStr := range c.UseAtDate {
-		parts := strings.Split(dateStr, "/")
-		if len(parts) != 2 {
-			return nil, fmt.Errorf("invalid date: %q", dateStr)
-		}
+		dateStr = strings.TrimSpace(dateStr)
 
-		month, err := strconv.ParseInt(strings.TrimLeft(parts[0], "0"), 0, 64)
-		if err != nil {
-			return nil, fmt.Errorf("invalid date: %q: %w", dateStr, err)
-		}
-		if month <= 0 || month > 12 {
-			return nil, fmt.Errorf("invalid month: %d", month)
+		if strings.Contains(dateStr, "/") {
+			key, err := parseRecurringDate(dateStr)
+			if err != nil {
+				return nil, err
+			}
+
+			dates = append(dates, key)
+
+			continue
 		}
 
-		day, err := strconv.ParseInt(strings.TrimLeft(parts[1], "0"), 0, 64)
+		keys, err := parseDateRange(dateStr)
 		if err != nil {
-			return nil, fmt.Errorf("invalid date: %q: %w", dateStr, err)
-		}
-		if day <= 0 || day > 31 {
-			return nil, fmt.Errorf("invalid day: %d", day)
+			return nil, err
 		}
 
-		dates = append(dates, fmt.Sprintf("%02d/%02d", month, day))
+		dates = append(dates, keys...)
 	}
 
 	return dates, nil
 }
 
+// parseRecurringDate parses a year independent date in the format MM/DD
+// and returns the normalized key for the DateSpecific map.
+func parseRecurringDate(dateStr string) (string, error) {
+	parts := strings.Split(dateStr, "/")
+	if len(parts) != 2 {
+		return "", fmt.Errorf("invalid date: %q", dateStr)
+	}
+
+	month, err := strconv.ParseInt(strings.TrimLeft(parts[0], "0"), 0, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid date: %q: %w", dateStr, err)
+	}
+	if month <= 0 || month > 12 {
+		return "", fmt.Errorf("invalid month: %d", month)
+	}
+
+	day, err := strconv.ParseInt(strings.TrimLeft(parts[1], "0"), 0, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid date: %q: %w", dateStr, err)
+	}
+	if day <= 0 || day > 31 {
+		return "", fmt.Errorf("invalid day: %d", day)
+	}
+
+	return fmt.Sprintf("%02d/%02d", month, day), nil
+}
+
+// parseDateRange parses a year specific date (YYYY-MM-DD) or an inclusive
+// date range (YYYY-MM-DD - YYYY-MM-DD) and returns the keys for all days
+// covered.
+func parseDateRange(dateStr string) ([]string, error) {
+	fromStr, toStr, isRange := strings.Cut(dateStr, " - ")
+	if !isRange {
+		toStr = fromStr
+	}
+
+	from, err := time.Parse(dateFormat, strings.TrimSpace(fromStr))
+	if err != nil {
+		return nil, fmt.Errorf("invalid date: %q", dateStr)
+	}
+
+	to, err := time.Parse(dateFormat, strings.TrimSpace(toStr))
+	if err != nil {
+		return nil, fmt.Errorf("invalid date: %q", dateStr)
+	}
+
+	if to.Before(from) {
+		return nil, fmt.Errorf("invalid date range: %q: end is before start", dateStr)
+	}
+
+	if days := int(to.Sub(from).Hours()/24) + 1; days > maxDateRangeDays {
+		return nil, fmt.Errorf("invalid date range: %q: must not span more than %d days", dateStr, maxDateRangeDays)
+	}
+
+	var keys []string
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		keys = append(keys, d.Format(dateFormat))
+	}
+
+	return keys, nil
+}
+
 func (s *state) getTimeRanges(openingHourDef Definition) ([]OpeningHour, error) {
 	ranges := make([]OpeningHour, 0, len(openingHourDef.TimeRanges))
 	for _, r := range openingHourDef.TimeRanges {
@@ -0,0 +1,64 @@
+// Package clock provides an abstraction of time so code that depends
+// on the current time and on timers can be tested deterministically.
+package clock
+
+import "time"
+
+// Clock provides access to the current time and to timers.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// After waits for d to elapse and then sends the current time
+	// on the returned channel.
+	After(d time.Duration) <-chan time.Time
+
+	// NewTimer creates a new timer that sends the current time
+	// on its channel after d.
+	NewTimer(d time.Duration) Timer
+
+	// AfterFunc waits for d to elapse and then calls fn in its
+	// own goroutine.
+	AfterFunc(d time.Duration, fn func()) Timer
+
+	// Sleep pauses the current goroutine for at least d.
+	Sleep(d time.Duration)
+}
+
+// Timer is a single event timer as returned by Clock.
+type Timer interface {
+	// C returns the channel on which the time is delivered. It is
+	// nil for timers created using AfterFunc.
+	C() <-chan time.Time
+
+	// Stop prevents the timer from firing. It returns false if the
+	// timer already expired or has been stopped.
+	Stop() bool
+
+	// Reset changes the timer to expire after d. It returns true
+	// if the timer had been active.
+	Reset(d time.Duration) bool
+}
+
+// System is the clock backed by the time package.
+var System Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (systemClock) Sleep(d time.Duration)                  { time.Sleep(d) }
+
+func (systemClock) NewTimer(d time.Duration) Timer {
+	return &systemTimer{Timer: time.NewTimer(d)}
+}
+
+func (systemClock) AfterFunc(d time.Duration, fn func()) Timer {
+	return &systemTimer{Timer: time.AfterFunc(d, fn)}
+}
+
+type systemTimer struct {
+	*time.Timer
+}
+
+func (t *systemTimer) C() <-chan time.Time { return t.Timer.C }
@@ -0,0 +1,187 @@
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Fake is a Clock that only advances when told to. It is meant
+// to be used in tests.
+type Fake struct {
+	lock sync.Mutex
+	cond *sync.Cond
+
+	now    time.Time
+	timers []*fakeTimer
+}
+
+// NewFake returns a new fake clock set to now.
+func NewFake(now time.Time) *Fake {
+	f := &Fake{
+		now: now,
+	}
+	f.cond = sync.NewCond(&f.lock)
+
+	return f
+}
+
+// Now returns the current time of the fake clock.
+func (f *Fake) Now() time.Time {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return f.now
+}
+
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	return f.NewTimer(d).C()
+}
+
+func (f *Fake) NewTimer(d time.Duration) Timer {
+	t := &fakeTimer{
+		clock: f,
+		ch:    make(chan time.Time, 1),
+	}
+	t.Reset(d)
+
+	return t
+}
+
+func (f *Fake) AfterFunc(d time.Duration, fn func()) Timer {
+	t := &fakeTimer{
+		clock: f,
+		fn:    fn,
+	}
+	t.Reset(d)
+
+	return t
+}
+
+func (f *Fake) Sleep(d time.Duration) {
+	<-f.After(d)
+}
+
+// Advance moves the clock forward by d and fires all timers that
+// expire in the meantime in the order of their deadlines.
+func (f *Fake) Advance(d time.Duration) {
+	f.Set(f.Now().Add(d))
+}
+
+// Set sets the clock to t and fires all timers that expire until
+// t in the order of their deadlines. The clock never moves backwards.
+func (f *Fake) Set(t time.Time) {
+	f.lock.Lock()
+
+	if t.After(f.now) {
+		f.now = t
+	}
+
+	sort.SliceStable(f.timers, func(i, j int) bool {
+		return f.timers[i].deadline.Before(f.timers[j].deadline)
+	})
+
+	var expired []*fakeTimer
+	for len(f.timers) > 0 && !f.timers[0].deadline.After(f.now) {
+		expired = append(expired, f.timers[0])
+		f.timers = f.timers[1:]
+	}
+
+	now := f.now
+	f.cond.Broadcast()
+	f.lock.Unlock()
+
+	for _, timer := range expired {
+		timer.fire(now)
+	}
+}
+
+// Timers returns the number of active timers.
+func (f *Fake) Timers() int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return len(f.timers)
+}
+
+// BlockUntil blocks until at least n timers are active. It is used
+// to wait for a goroutine to start waiting on the clock.
+func (f *Fake) BlockUntil(n int) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for len(f.timers) < n {
+		f.cond.Wait()
+	}
+}
+
+// remove removes t from the list of active timers and reports
+// whether it has been active. The caller must hold f.lock.
+func (f *Fake) remove(t *fakeTimer) bool {
+	for idx, other := range f.timers {
+		if other == t {
+			f.timers = append(f.timers[:idx], f.timers[idx+1:]...)
+
+			return true
+		}
+	}
+
+	return false
+}
+
+type fakeTimer struct {
+	clock    *Fake
+	deadline time.Time
+	ch       chan time.Time
+	fn       func()
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.lock.Lock()
+	defer t.clock.lock.Unlock()
+
+	active := t.clock.remove(t)
+	t.clock.cond.Broadcast()
+
+	return active
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.clock.lock.Lock()
+
+	active := t.clock.remove(t)
+	t.deadline = t.clock.now.Add(d)
+
+	if d > 0 {
+		t.clock.timers = append(t.clock.timers, t)
+		t.clock.cond.Broadcast()
+		t.clock.lock.Unlock()
+
+		return active
+	}
+
+	now := t.clock.now
+	t.clock.lock.Unlock()
+
+	// timers with a non-positive duration fire immediately.
+	t.fire(now)
+
+	return active
+}
+
+func (t *fakeTimer) fire(now time.Time) {
+	if t.fn != nil {
+		go t.fn()
+
+		return
+	}
+
+	select {
+	case t.ch <- now:
+	default:
+	}
+}
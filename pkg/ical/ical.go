@@ -0,0 +1,177 @@
+// Package ical implements a minimal writer for iCalendar (RFC 5545)
+// files containing VEVENT components.
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+)
+
+// Event describes a single VEVENT.
+type Event struct {
+	// UID uniquely identifies the event.
+	UID string
+	// Summary is the title of the event.
+	Summary string
+	// Description holds additional information for the event.
+	Description string
+	// Start and End hold the time range of the event.
+	Start time.Time
+	End   time.Time
+	// AllDay marks the event as an all-day event. Only the dates of
+	// Start and End are used and End is exclusive.
+	AllDay bool
+	// Categories is an optional list of categories.
+	Categories []string
+	// Transparent marks the event as not blocking time in
+	// free/busy lookups.
+	Transparent bool
+}
+
+// Calendar is a list of events.
+type Calendar struct {
+	// ProdID identifies the product that created the calendar.
+	ProdID string
+	// Name is the optional display name of the calendar.
+	Name string
+	// Timestamp is used as the DTSTAMP of all events. If zero, the
+	// current time is used.
+	Timestamp time.Time
+	// Events holds all events of the calendar.
+	Events []Event
+}
+
+// dateTimeFormat is the format used for UTC date-time values.
+const dateTimeFormat = "20060102T150405Z"
+
+// dateFormat is the format used for date values.
+const dateFormat = "20060102"
+
+// WriteTo writes the calendar to w.
+func (cal Calendar) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+
+	cw.line("BEGIN:VCALENDAR")
+	cw.line("VERSION:2.0")
+	cw.line("PRODID:" + escape(cal.ProdID))
+	cw.line("CALSCALE:GREGORIAN")
+	cw.line("METHOD:PUBLISH")
+
+	if cal.Name != "" {
+		cw.line("X-WR-CALNAME:" + escape(cal.Name))
+	}
+
+	timestamp := cal.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	stamp := timestamp.UTC().Format(dateTimeFormat)
+
+	for _, evt := range cal.Events {
+		cw.line("BEGIN:VEVENT")
+		cw.line("UID:" + escape(evt.UID))
+		cw.line("DTSTAMP:" + stamp)
+		if evt.AllDay {
+			cw.line("DTSTART;VALUE=DATE:" + evt.Start.Format(dateFormat))
+			cw.line("DTEND;VALUE=DATE:" + evt.End.Format(dateFormat))
+		} else {
+			cw.line("DTSTART:" + evt.Start.UTC().Format(dateTimeFormat))
+			cw.line("DTEND:" + evt.End.UTC().Format(dateTimeFormat))
+		}
+		cw.line("SUMMARY:" + escape(evt.Summary))
+
+		if evt.Description != "" {
+			cw.line("DESCRIPTION:" + escape(evt.Description))
+		}
+
+		if len(evt.Categories) > 0 {
+			categories := make([]string, len(evt.Categories))
+			for idx, c := range evt.Categories {
+				categories[idx] = escape(c)
+			}
+
+			cw.line("CATEGORIES:" + strings.Join(categories, ","))
+		}
+
+		if evt.Transparent {
+			cw.line("TRANSP:TRANSPARENT")
+		}
+
+		cw.line("END:VEVENT")
+	}
+
+	cw.line("END:VCALENDAR")
+
+	if cw.err == nil {
+		cw.err = cw.w.Flush()
+	}
+
+	return cw.n, cw.err
+}
+
+var escaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+)
+
+// escape escapes s for use as a TEXT value.
+func escape(s string) string {
+	return escaper.Replace(s)
+}
+
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+// line writes a content line and folds it at 75 octets as
+// required by RFC 5545.
+func (cw *countingWriter) line(s string) {
+	if cw.err != nil {
+		return
+	}
+
+	const maxLength = 75
+
+	for first := true; ; first = false {
+		limit := maxLength
+		if !first {
+			// continuation lines start with a space.
+			limit--
+		}
+
+		cut := len(s)
+		if cut > limit {
+			cut = limit
+			// never split a multi-byte UTF-8 sequence.
+			for cut > 0 && s[cut]&0xC0 == 0x80 {
+				cut--
+			}
+		}
+
+		prefix := ""
+		if !first {
+			prefix = " "
+		}
+
+		n, err := cw.w.WriteString(prefix + s[:cut] + "\r\n")
+		cw.n += int64(n)
+		if err != nil {
+			cw.err = err
+
+			return
+		}
+
+		s = s[cut:]
+		if s == "" {
+			return
+		}
+	}
+}
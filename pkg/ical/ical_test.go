@@ -0,0 +1,84 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteTo(t *testing.T) {
+	t.Parallel()
+
+	loc, err := time.LoadLocation("Europe/Vienna")
+	require.NoError(t, err)
+
+	cal := Calendar{
+		ProdID:    "-//test//EN",
+		Name:      "Opening hours",
+		Timestamp: time.Date(2026, time.April, 1, 12, 0, 0, 0, loc),
+		Events: []Event{
+			{
+				UID:        "open-1@test",
+				Summary:    "Open",
+				Start:      time.Date(2026, time.April, 7, 8, 0, 0, 0, loc),
+				End:        time.Date(2026, time.April, 7, 12, 0, 0, 0, loc),
+				Categories: []string{"regular", "a,b"},
+			},
+			{
+				UID:         "holiday-1@test",
+				Summary:     "Public holiday: Ostermontag",
+				Start:       time.Date(2026, time.April, 6, 0, 0, 0, 0, loc),
+				End:         time.Date(2026, time.April, 7, 0, 0, 0, 0, loc),
+				AllDay:      true,
+				Transparent: true,
+			},
+		},
+	}
+
+	var sb strings.Builder
+	n, err := cal.WriteTo(&sb)
+	require.NoError(t, err)
+
+	out := sb.String()
+	assert.Equal(t, int64(len(out)), n)
+
+	assert.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\n"))
+	assert.True(t, strings.HasSuffix(out, "END:VCALENDAR\r\n"))
+	assert.Contains(t, out, "X-WR-CALNAME:Opening hours\r\n")
+	assert.Contains(t, out, "DTSTAMP:20260401T100000Z\r\n")
+
+	// date-time values are converted to UTC
+	assert.Contains(t, out, "DTSTART:20260407T060000Z\r\n")
+	assert.Contains(t, out, "DTEND:20260407T100000Z\r\n")
+	assert.Contains(t, out, "CATEGORIES:regular,a\\,b\r\n")
+
+	// all-day events use the local date
+	assert.Contains(t, out, "DTSTART;VALUE=DATE:20260406\r\n")
+	assert.Contains(t, out, "DTEND;VALUE=DATE:20260407\r\n")
+	assert.Contains(t, out, "TRANSP:TRANSPARENT\r\n")
+}
+
+func TestLineFolding(t *testing.T) {
+	t.Parallel()
+
+	cal := Calendar{
+		ProdID: "-//test//EN",
+		Events: []Event{
+			{
+				UID:     "long@test",
+				Summary: strings.Repeat("ä", 100),
+			},
+		},
+	}
+
+	var sb strings.Builder
+	_, err := cal.WriteTo(&sb)
+	require.NoError(t, err)
+
+	for _, line := range strings.Split(strings.TrimSuffix(sb.String(), "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), 75, line)
+	}
+}
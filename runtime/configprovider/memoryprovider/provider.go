@@ -0,0 +1,117 @@
+package memoryprovider
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ppacher/system-conf/conf"
+	"github.com/tierklinik-dobersberg/cis/runtime"
+)
+
+// MemoryProvider is a runtime.ConfigProvider that keeps all configuration
+// data in memory. It is mainly useful for tests.
+type MemoryProvider struct {
+	rw       sync.RWMutex
+	nextID   int
+	sections []runtime.Section
+}
+
+// New returns a new in-memory runtime.ConfigProvider that initially
+// contains sections. IDs are assigned to all sections that do not
+// have one.
+func New(sections ...conf.Section) *MemoryProvider {
+	pr := new(MemoryProvider)
+
+	for _, sec := range sections {
+		_, _ = pr.Create(context.Background(), sec)
+	}
+
+	return pr
+}
+
+// Create stores a new configuration section and returns its ID.
+func (pr *MemoryProvider) Create(_ context.Context, sec conf.Section) (string, error) {
+	pr.rw.Lock()
+	defer pr.rw.Unlock()
+
+	pr.nextID++
+	id := strconv.Itoa(pr.nextID)
+
+	pr.sections = append(pr.sections, runtime.Section{
+		ID: id,
+		Section: conf.Section{
+			Name:    strings.ToLower(sec.Name),
+			Options: sec.Options,
+		},
+	})
+
+	return id, nil
+}
+
+// Update replaces the options of the configuration section identified
+// by id and secType.
+func (pr *MemoryProvider) Update(_ context.Context, id, secType string, opts []conf.Option) error {
+	pr.rw.Lock()
+	defer pr.rw.Unlock()
+
+	for idx, sec := range pr.sections {
+		if sec.ID == id && sec.Name == strings.ToLower(secType) {
+			pr.sections[idx].Options = opts
+
+			return nil
+		}
+	}
+
+	return runtime.ErrCfgSectionNotFound
+}
+
+// Delete deletes the configuration section identified by id.
+func (pr *MemoryProvider) Delete(_ context.Context, id string) error {
+	pr.rw.Lock()
+	defer pr.rw.Unlock()
+
+	for idx, sec := range pr.sections {
+		if sec.ID == id {
+			pr.sections = append(pr.sections[:idx], pr.sections[idx+1:]...)
+
+			return nil
+		}
+	}
+
+	return runtime.ErrCfgSectionNotFound
+}
+
+// Get returns all configuration sections of sectionType.
+func (pr *MemoryProvider) Get(_ context.Context, sectionType string) ([]runtime.Section, error) {
+	pr.rw.RLock()
+	defer pr.rw.RUnlock()
+
+	sectionType = strings.ToLower(sectionType)
+
+	var result []runtime.Section
+	for _, sec := range pr.sections {
+		if sec.Name == sectionType {
+			result = append(result, sec)
+		}
+	}
+
+	return result, nil
+}
+
+// GetID returns the configuration section identified by id.
+func (pr *MemoryProvider) GetID(_ context.Context, id string) (runtime.Section, error) {
+	pr.rw.RLock()
+	defer pr.rw.RUnlock()
+
+	for _, sec := range pr.sections {
+		if sec.ID == id {
+			return sec, nil
+		}
+	}
+
+	return runtime.Section{}, runtime.ErrCfgSectionNotFound
+}
+
+var _ runtime.ConfigProvider = (*MemoryProvider)(nil)
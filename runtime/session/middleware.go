@@ -16,6 +16,11 @@ func UserFromCtx(ctx context.Context) *idmv1.Profile {
 	return value
 }
 
+// WithUser returns a new context that is associated with user.
+func WithUser(ctx context.Context, user *idmv1.Profile) context.Context {
+	return context.WithValue(ctx, userContextKey, user)
+}
+
 // UserProvider is used to retrieve the user by name.
 type UserProvider interface {
 	GetUser(ctx context.Context, userId string) (*idmv1.Profile, error)
@@ -45,7 +50,7 @@ func Middleware(provider UserProvider) func(next echo.HandlerFunc) echo.HandlerF
 					return err
 				}
 
-				ctx = context.WithValue(c.Request().Context(), userContextKey, user)
+				ctx = WithUser(c.Request().Context(), user)
 
 				req := c.Request().WithContext(ctx)
 				c.SetRequest(req)
@@ -128,3 +128,26 @@ var (
 var (
 	IDRef = "_id"
 )
+
+// DependsOnAnnotation marks an option as only being relevant if
+// another option of the same section is set to one of Values.
+type DependsOnAnnotation struct {
+	// Option is the name of the option the annotated option
+	// depends on.
+	Option string `json:"option"`
+	// Values holds the values of Option for which the annotated
+	// option is relevant.
+	Values []string `json:"values"`
+}
+
+// DependsOn returns a new KeyValue for a DependsOnAnnotation. User
+// interfaces may use it to hide options that do not apply.
+func DependsOn(option string, values ...string) conf.KeyValue {
+	return conf.KeyValue{
+		Key: "vet.dobersberg.cis:schema/dependsOn",
+		Value: DependsOnAnnotation{
+			Option: option,
+			Values: values,
+		},
+	}
+}